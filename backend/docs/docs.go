@@ -24,27 +24,16 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/categories": {
+        "/api/v1/catalog/categories": {
             "get": {
-                "description": "商品カテゴリーとアイテムの一覧を取得します",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "無効なカテゴリー・アイテムを含む品目マスタを表示順に取得します。単価は本日適用される価格です",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
-                ],
-                "summary": "カテゴリー一覧を取得",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ひらがなでソートするかどうか (true/false)",
-                        "name": "sort",
-                        "in": "query"
-                    }
+                    "Catalog"
                 ],
+                "summary": "品目マスタを取得（管理用）",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -57,19 +46,26 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.GetCategoriesResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.CatalogCategory"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/api/v1/estimates/": {
-            "get": {
-                "description": "すべての見積もりを取得します",
+            },
+            "post": {
+                "description": "品目マスタにカテゴリーを追加します。IDは英小文字・数字・ハイフンで、登録後は変更できません",
                 "consumes": [
                     "application/json"
                 ],
@@ -77,12 +73,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Estimates"
+                    "Catalog"
+                ],
+                "summary": "カテゴリーを登録",
+                "parameters": [
+                    {
+                        "description": "カテゴリー",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CatalogCategoryRequest"
+                        }
+                    }
                 ],
-                "summary": "見積もり一覧を取得",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "allOf": [
                                 {
@@ -92,18 +99,37 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object",
-                                            "additionalProperties": true
+                                            "$ref": "#/definitions/models.CatalogCategory"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "post": {
-                "description": "新しい見積もりを作成します",
+            }
+        },
+        "/api/v1/catalog/categories/{id}": {
+            "put": {
+                "description": "カテゴリーの名称・読み・表示順・有効フラグを更新します",
                 "consumes": [
                     "application/json"
                 ],
@@ -111,23 +137,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Estimates"
+                    "Catalog"
                 ],
-                "summary": "見積もりを作成",
+                "summary": "カテゴリーを更新",
                 "parameters": [
                     {
-                        "description": "見積もり情報",
-                        "name": "estimate",
+                        "type": "string",
+                        "description": "カテゴリーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "カテゴリー",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateEstimateRequest"
+                            "$ref": "#/definitions/models.CatalogCategoryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -137,7 +170,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Estimate"
+                                            "$ref": "#/definitions/models.CatalogCategory"
                                         }
                                     }
                                 }
@@ -149,43 +182,54 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/api/v1/estimates/pdf": {
-            "post": {
-                "description": "見積もり情報からPDFを生成します",
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "アイテムのないカテゴリーを削除します。アイテムが残っている場合は無効にしてください",
                 "produces": [
-                    "application/pdf"
+                    "application/json"
                 ],
                 "tags": [
-                    "Estimates"
+                    "Catalog"
                 ],
-                "summary": "見積もりPDFを生成",
+                "summary": "カテゴリーを削除",
                 "parameters": [
                     {
-                        "description": "見積もり情報",
-                        "name": "estimate",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PDFEstimateRequest"
-                        }
+                        "type": "string",
+                        "description": "カテゴリーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/utils.Response"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
@@ -199,26 +243,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/estimates/{id}": {
-            "get": {
-                "description": "IDを指定して見積もりを取得します",
+        "/api/v1/catalog/import": {
+            "post": {
+                "description": "CSVまたはExcelファイルの内容で品目マスタを置き換えます。ファイルにないアイテムは削除されます。エラーのある行が1行でもあれば取り込みません",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Estimates"
+                    "Catalog"
                 ],
-                "summary": "見積もりを取得",
+                "summary": "カタログを取り込む",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "見積もりID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "file",
+                        "description": "カタログのファイル (.csv / .xlsx)",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "ファイル形式（省略時は拡張子から判定）",
+                        "name": "format",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
@@ -233,7 +287,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Estimate"
+                                            "$ref": "#/definitions/models.CatalogImportResult"
                                         }
                                     }
                                 }
@@ -245,80 +299,89 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
-                    }
-                }
-            },
-            "put": {
-                "description": "指定したIDの見積もりを更新します",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Estimates"
-                ],
-                "summary": "見積もりを更新",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "見積もりID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     },
-                    {
-                        "description": "更新する見積もり情報",
-                        "name": "estimate",
-                        "in": "body",
-                        "required": true,
+                    "422": {
+                        "description": "ファイルにエラーがあるため取り込みません",
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateEstimateRequest"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CatalogImportResult"
+                                        }
+                                    }
+                                }
+                            ]
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/utils.Response"
+                            "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "指定したIDの見積もりを削除します",
+            }
+        },
+        "/api/v1/catalog/import/preview": {
+            "post": {
+                "description": "CSVまたはExcelファイルを検証し、現在のカタログとの差分（追加・削除・価格変更）を返します。品目マスタは変更しません",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Estimates"
+                    "Catalog"
                 ],
-                "summary": "見積もりを削除",
+                "summary": "カタログの取り込みをプレビュー",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "見積もりID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "file",
+                        "description": "カタログのファイル (.csv / .xlsx)",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "ファイル形式（省略時は拡張子から判定）",
+                        "name": "format",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CatalogImportResult"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -326,39 +389,57 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/instructions/pdf": {
+        "/api/v1/catalog/items": {
             "post": {
-                "description": "指示書情報からPDFを生成します",
+                "description": "品目マスタにアイテムを追加します。IDは英小文字・数字・ハイフンで、登録後は変更できません",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/pdf"
+                    "application/json"
                 ],
                 "tags": [
-                    "Instructions"
+                    "Catalog"
                 ],
-                "summary": "指示書PDFを生成",
+                "summary": "アイテムを登録",
                 "parameters": [
                     {
-                        "description": "指示書情報",
-                        "name": "instruction",
+                        "description": "アイテム",
+                        "name": "item",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.PDFInstruction"
+                            "$ref": "#/definitions/models.CatalogItemRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "file"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CatalogItem"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -367,6 +448,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -376,9 +463,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users/profile": {
-            "get": {
-                "description": "現在のユーザー情報を取得します",
+        "/api/v1/catalog/items/{id}": {
+            "put": {
+                "description": "アイテムの名称・読み・単価・単位・有効フラグ・表示順などを更新します。単価の変更は本日から適用され、価格の履歴に残ります。作成済みの見積もりの単価は変わりません",
                 "consumes": [
                     "application/json"
                 ],
@@ -386,9 +473,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Catalog"
+                ],
+                "summary": "アイテムを更新",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "アイテムID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "アイテム",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CatalogItemRequest"
+                        }
+                    }
                 ],
-                "summary": "ユーザープロフィールを取得",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -401,17 +506,127 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.User"
+                                            "$ref": "#/definitions/models.CatalogItem"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
                     }
                 }
             },
-            "put": {
-                "description": "ユーザー情報を更新します",
+            "delete": {
+                "description": "品目マスタからアイテムを削除します。作成済みの見積もりの明細は残ります",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "アイテムを削除",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "アイテムID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/catalog/items/{id}/prices": {
+            "get": {
+                "description": "アイテムの価格改定の履歴を適用開始日の順に返します。予定されている改定と、削除済みのアイテムの履歴も含みます",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "アイテムの価格履歴を取得",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "アイテムID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CatalogItemPriceHistory"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "指定日から適用される単価を登録します（例: 4月1日からの値上げ）。同じ日の価格は置き換えます。過去の日付は指定できず、作成済みの見積もりの単価は変わりません",
                 "consumes": [
                     "application/json"
                 ],
@@ -419,25 +634,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Catalog"
                 ],
-                "summary": "ユーザープロフィールを更新",
+                "summary": "アイテムの価格改定を登録",
                 "parameters": [
                     {
-                        "description": "更新するユーザー情報",
-                        "name": "user",
+                        "type": "string",
+                        "description": "アイテムID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "価格改定",
+                        "name": "price",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateUserRequest"
+                            "$ref": "#/definitions/models.CatalogItemPriceRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/utils.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CatalogItemPrice"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -445,28 +679,65 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/dev/create-instruction-pdf": {
-            "get": {
-                "description": "開発用のテスト指示書PDFを生成します",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/catalog/items/{id}/prices/{effective_from}": {
+            "delete": {
+                "description": "明日以降に適用される価格改定を取り消します。適用済みの価格は取り消せません",
                 "produces": [
-                    "application/pdf"
+                    "application/json"
                 ],
                 "tags": [
-                    "Development"
+                    "Catalog"
+                ],
+                "summary": "予定している価格改定を取り消す",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "アイテムID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "適用開始日 (YYYY-MM-DD)",
+                        "name": "effective_from",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "テスト指示書PDFを生成",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     },
                     "500": {
@@ -478,24 +749,71 @@ const docTemplate = `{
                 }
             }
         },
-        "/dev/create-pdf": {
-            "get": {
-                "description": "開発用のテストPDFを生成します",
+        "/api/v1/catalog/sync": {
+            "post": {
+                "description": "pull はスプレッドシート（開発環境ではモックデータ）の内容でデータベースの品目マスタを置き換えます。予約した価格改定が適用済みで、シートが改定前の価格のままのアイテムは改定後の価格を残します。push はデータベースの有効なアイテムをスプレッドシートに書き込みます",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/pdf"
+                    "application/json"
                 ],
                 "tags": [
-                    "Development"
+                    "Catalog"
+                ],
+                "summary": "品目マスタとスプレッドシートを同期",
+                "parameters": [
+                    {
+                        "description": "同期の方向",
+                        "name": "sync",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CatalogSyncRequest"
+                        }
+                    }
                 ],
-                "summary": "テストPDFを生成",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CatalogSyncResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "シートにエラーがあるため取り込みません",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CatalogValidationReport"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
@@ -503,23 +821,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/health": {
+        "/api/v1/catalog/validate": {
             "get": {
-                "description": "APIの動作状態を確認します",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "スプレッドシート（開発環境ではモックデータ）を行ごとに検証します。見出し行の列名の違い（列のずれ）・IDの重複・読みの欠落・数値でないか負の価格・空のカテゴリーはエラーとなり、エラーのあるシートは見積もり画面にも取り込みにも使われません",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Catalog"
                 ],
-                "summary": "ヘルスチェック",
+                "summary": "スプレッドシートの品目マスタを検証",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -532,10 +853,2239 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "type": "string"
-                                            }
+                                            "$ref": "#/definitions/handlers.CatalogValidationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/categories": {
+            "get": {
+                "description": "商品カテゴリーとアイテムの一覧を取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "カテゴリー一覧を取得",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ひらがなでソートするかどうか (true/false)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "並び順 (display: 表示順 / popular: よく使われる順)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "価格の基準日 (YYYY-MM-DD、既定は本日)。データベースの品目マスタのみ指定でき、その日に適用される価格を返します",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.GetCategoriesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/categories/export": {
+            "get": {
+                "description": "見積もり画面に表示しているカタログを、スプレッドシート (categories_data) と同じ列構成で出力します。CSVはExcelで開けるBOM付きUTF-8です",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "カタログをCSV・Excelで出力",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "出力形式 (既定csv)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/categories/search": {
+            "get": {
+                "description": "品名（漢字）・ひらがなの読み・ローマ字の前方一致・部分一致でアイテムを検索し、一致度の高い順に返します。カタカナ・全角半角は区別しません",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "カタログのアイテムを検索",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "検索語（例: 冷蔵, れいぞ, reizo）",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "最大件数 (既定20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.CategorySearchResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/": {
+            "get": {
+                "description": "条件に一致する見積もりを検索し、カーソル方式でページングして取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "見積もり一覧を取得",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ステータス（カンマ区切りで複数指定可）",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "作成日の開始 (YYYY-MM-DD)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "作成日の終了 (YYYY-MM-DD, 当日を含む)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "顧客名（部分一致）",
+                        "name": "customer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "電話番号（部分一致、ハイフン無視）",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "担当者ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "合計金額の下限",
+                        "name": "min_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "合計金額の上限",
+                        "name": "max_total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "total",
+                            "customer_name",
+                            "estimate_no"
+                        ],
+                        "type": "string",
+                        "description": "並び順の項目",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "昇順/降順",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "前ページの next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "1ページの件数 (既定20, 最大100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.EstimatePage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "新しい見積もりを作成します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "見積もりを作成",
+                "parameters": [
+                    {
+                        "description": "見積もり情報",
+                        "name": "estimate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEstimateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Estimate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/pdf": {
+            "post": {
+                "description": "見積もり情報を保存し、保存した見積もりからPDFを生成します。PDFはローカルまたはGoogle Driveに保管されます。PDFを生成・保管できなかった場合も見積もりは保存済みのままなので、再送せずに GET /api/v1/estimates/{id}/pdf で再発行してください",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "見積もりPDFを生成",
+                "parameters": [
+                    {
+                        "description": "見積もり情報",
+                        "name": "estimate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PDFEstimateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "X-Estimate-ID": {
+                                "type": "string",
+                                "description": "保存した見積もりのID"
+                            },
+                            "X-Estimate-No": {
+                                "type": "string",
+                                "description": "見積書番号"
+                            },
+                            "X-Estimate-Subtotal": {
+                                "type": "number",
+                                "description": "サーバーで計算した小計"
+                            },
+                            "X-Estimate-Tax": {
+                                "type": "number",
+                                "description": "サーバーで計算した消費税"
+                            },
+                            "X-Estimate-Total": {
+                                "type": "number",
+                                "description": "サーバーで計算した合計金額"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "金額が数量×単価と一致しない行がある",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.PriceMismatch"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "見積もりは保存済みで、PDFの生成・保管に失敗した",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Estimate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/pdf/load-plan": {
+            "post": {
+                "description": "PDFリクエストの明細からカタログの容積・重量を合計し、収集運搬費が最も安くなる車両と台数を返します。見積もりは保存しません",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "積載量と推奨車両を計算",
+                "parameters": [
+                    {
+                        "description": "見積もり情報",
+                        "name": "estimate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PDFEstimateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.LoadPlan"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/pdf/preview": {
+            "post": {
+                "description": "PDFリクエストの明細金額をサーバーで計算し直し、合計と一致しない行を返します。見積もりは保存しません",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "見積もりPDFの金額を確認",
+                "parameters": [
+                    {
+                        "description": "見積もり情報",
+                        "name": "estimate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PDFEstimateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PDFEstimatePreview"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/{id}": {
+            "get": {
+                "description": "IDを指定して見積もりを取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "見積もりを取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Estimate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "指定したIDの見積もりを更新します。明細・値引き・作業条件・計算方法などの金額に関わる項目は下書きか提出済みの間だけ変更でき、受注後は件名や顧客情報のみ変更できます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "見積もりを更新",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新する見積もり情報",
+                        "name": "estimate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateEstimateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Estimate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "指定したIDの下書きの見積もりを削除します。送付済みの見積もりや、請求書・指示書・マニフェストを発行した見積もりは削除できません",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "見積もりを削除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/{id}/instruction": {
+            "post": {
+                "description": "受注済みの見積もりの顧客・明細・処分希望日・合計金額から作業指示書を作成して保存し、PDFを返します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Instructions"
+                ],
+                "summary": "見積もりから作業指示書を作成",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "受付者・収集日・メモ",
+                        "name": "instruction",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateInstructionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "X-Instruction-ID": {
+                                "type": "string",
+                                "description": "保存した指示書のID"
+                            },
+                            "X-Instruction-No": {
+                                "type": "string",
+                                "description": "指示書番号"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/{id}/invoice": {
+            "post": {
+                "description": "回収済みの見積もりと現場の実績（計量・追加明細）から請求書を作成して保存し、PDFを返します。見積もりは請求済みに遷移します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "見積もりから請求書を作成",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "作業実績",
+                        "name": "invoice",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateInvoiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "X-Invoice-ID": {
+                                "type": "string",
+                                "description": "保存した請求書のID"
+                            },
+                            "X-Invoice-No": {
+                                "type": "string",
+                                "description": "請求書番号"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/{id}/pdf": {
+            "get": {
+                "description": "保存済みの見積もりから見積書PDFを再生成します。source=archive を指定すると発行時に保管した原本を返します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "保存済み見積もりのPDFを取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "regenerate",
+                            "archive"
+                        ],
+                        "type": "string",
+                        "description": "regenerate（既定）または archive",
+                        "name": "source",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/{id}/recycling-tickets": {
+            "put": {
+                "description": "家電リサイクル法の品目の明細ごとにリサイクル券のお問合せ管理票番号を記録します。見積もりの版は変わりません",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "リサイクル券番号を記録",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "明細ごとのリサイクル券番号",
+                        "name": "tickets",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecyclingTicketsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Estimate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/{id}/revisions": {
+            "get": {
+                "description": "見積もりの変更履歴（版ごとのスナップショット）を古い順に取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "見積もりの版一覧を取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.EstimateRevision"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/{id}/revisions/diff": {
+            "get": {
+                "description": "2つの版の間で変更された項目・明細を取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "見積もりの版の差分を取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "比較元の版",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "比較先の版",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.EstimateRevisionDiff"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/{id}/revisions/{revision}/pdf": {
+            "get": {
+                "description": "指定した版の内容で見積書PDFを生成します（第2版以降は版数を印字）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "見積もりの版のPDFを取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "版数",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/estimates/{id}/transitions": {
+            "post": {
+                "description": "見積もりのステータスをライフサイクルに従って変更し、遷移日時を記録します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Estimates"
+                ],
+                "summary": "見積もりのステータスを遷移",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "遷移先ステータス",
+                        "name": "transition",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitionEstimateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Estimate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/instructions/pdf": {
+            "post": {
+                "description": "指示書情報からPDFを生成します。指示書は保存せず採番もしないため、指示書番号は指定したものを印字します（登録済みの番号は指定できません）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Instructions"
+                ],
+                "summary": "指示書PDFを生成",
+                "parameters": [
+                    {
+                        "description": "指示書情報",
+                        "name": "instruction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PDFInstruction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/instructions/{id}/manifests": {
+            "post": {
+                "description": "作業指示書に対して交付した産業廃棄物管理票（マニフェスト）を登録します。排出事業者・排出事業場を省略すると見積もりの顧客情報を使います",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manifests"
+                ],
+                "summary": "作業指示書のマニフェストを登録",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作業指示書ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "マニフェストの記載事項",
+                        "name": "manifest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateManifestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Manifest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/manifests": {
+            "get": {
+                "description": "交付したマニフェストを返送状況と返送期限つきで取得します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manifests"
+                ],
+                "summary": "マニフェスト一覧を取得",
+                "parameters": [
+                    {
+                        "enum": [
+                            "issued",
+                            "transported",
+                            "disposed",
+                            "completed"
+                        ],
+                        "type": "string",
+                        "description": "進捗",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "estimate_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "交付日の開始 (YYYY-MM-DD)",
+                        "name": "issued_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "交付日の終了 (YYYY-MM-DD, 当日を含む)",
+                        "name": "issued_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "返送期限を過ぎた写しがあるものだけ",
+                        "name": "overdue",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Manifest"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/manifests/alerts": {
+            "get": {
+                "description": "返送期限を過ぎた写しと、期限が近い未返送の写しを期限の早い順に取得します。期限を過ぎた場合は報告期限までに都道府県知事へ報告が必要です",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manifests"
+                ],
+                "summary": "マニフェストの返送期限アラートを取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "期限までの日数 (既定7)",
+                        "name": "within",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ManifestAlert"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/manifests/export": {
+            "get": {
+                "description": "条件に一致するマニフェストを社内台帳用のShift_JISのCSVで出力します。列構成は独自のもので、電子マニフェスト（JWNET）の一括登録には使えません",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Manifests"
+                ],
+                "summary": "マニフェストをCSVで出力",
+                "parameters": [
+                    {
+                        "enum": [
+                            "issued",
+                            "transported",
+                            "disposed",
+                            "completed"
+                        ],
+                        "type": "string",
+                        "description": "進捗",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "見積もりID",
+                        "name": "estimate_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "交付日の開始 (YYYY-MM-DD)",
+                        "name": "issued_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "交付日の終了 (YYYY-MM-DD, 当日を含む)",
+                        "name": "issued_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "返送期限を過ぎた写しがあるものだけ",
+                        "name": "overdue",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/manifests/{id}": {
+            "get": {
+                "description": "マニフェストの記載事項とB2・D・E票の返送状況を取得します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manifests"
+                ],
+                "summary": "マニフェストを取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "マニフェストID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Manifest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/manifests/{id}/returns": {
+            "post": {
+                "description": "収集運搬業者・処分業者から返送されたB2・D・E票の終了年月日と受領日を記録します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manifests"
+                ],
+                "summary": "マニフェストの写しの返送を記録",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "マニフェストID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "返送された写し",
+                        "name": "return",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecordManifestReturnRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Manifest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pricing-rules": {
+            "get": {
+                "description": "階段作業・横持ち・解体・当日対応・夜間・最低料金の割増ルールを適用順に取得します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PricingRules"
+                ],
+                "summary": "割増ルール一覧を取得",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.PricingRule"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pricing-rules/{id}": {
+            "put": {
+                "description": "割増ルールの名称・金額・割増率・基準値などを変更します。変更後に作成・更新した見積もりから適用されます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PricingRules"
+                ],
+                "summary": "割増ルールを更新",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ルールID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ルールの内容",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatePricingRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PricingRule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/recycling-fees": {
+            "get": {
+                "description": "家電リサイクル法の品目ごとのリサイクル料金をメーカー・サイズ区分別に取得します。メーカーが空の行は標準料金です",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RecyclingFees"
+                ],
+                "summary": "家電リサイクル料金表を取得",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.RecyclingFee"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "メーカー・サイズ区分ごとのリサイクル料金を料金表に追加します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RecyclingFees"
+                ],
+                "summary": "家電リサイクル料金を登録",
+                "parameters": [
+                    {
+                        "description": "リサイクル料金",
+                        "name": "fee",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecyclingFeeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RecyclingFee"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/recycling-fees/{id}": {
+            "put": {
+                "description": "料金表の行を更新します。変更後に作成する見積もりから適用されます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RecyclingFees"
+                ],
+                "summary": "家電リサイクル料金を更新",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "料金表の行ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "リサイクル料金",
+                        "name": "fee",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecyclingFeeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RecyclingFee"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/profile": {
+            "get": {
+                "description": "現在のユーザー情報を取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "ユーザープロフィールを取得",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "ユーザー情報を更新します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "ユーザープロフィールを更新",
+                "parameters": [
+                    {
+                        "description": "更新するユーザー情報",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dev/create-instruction-pdf": {
+            "get": {
+                "description": "開発用のテスト指示書PDFを生成します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Development"
+                ],
+                "summary": "テスト指示書PDFを生成",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dev/create-pdf": {
+            "get": {
+                "description": "開発用のテストPDFを生成します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Development"
+                ],
+                "summary": "テストPDFを生成",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "APIの動作状態を確認します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "ヘルスチェック",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "additionalProperties": {
+                                                "type": "string"
+                                            }
                                         }
                                     }
                                 }
@@ -547,6 +3097,39 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "handlers.CatalogValidationResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer"
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogSheetIssue"
+                    }
+                },
+                "publishable": {
+                    "description": "エラーがなく1件以上のアイテムがある",
+                    "type": "boolean"
+                },
+                "rows": {
+                    "description": "見出しと空行を除いた行数",
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "検証したデータ (google_sheets / mock_data)",
+                    "type": "string"
+                },
+                "valid_rows": {
+                    "description": "エラーのない行数",
+                    "type": "integer"
+                },
+                "warnings": {
+                    "type": "integer"
+                }
+            }
+        },
         "handlers.CategoryResponse": {
             "type": "object",
             "properties": {
@@ -556,360 +3139,2367 @@ const docTemplate = `{
                 "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handlers.Item"
+                        "$ref": "#/definitions/handlers.Item"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CategorySearchMatch": {
+            "type": "string",
+            "enum": [
+                "name",
+                "reading",
+                "romaji"
+            ],
+            "x-enum-varnames": [
+                "CategorySearchMatchName",
+                "CategorySearchMatchReading",
+                "CategorySearchMatchRomaji"
+            ]
+        },
+        "handlers.CategorySearchResponse": {
+            "type": "object",
+            "properties": {
+                "query": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.CategorySearchResult"
+                    }
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CategorySearchResult": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "description": "EffectiveFrom and EffectiveTo are the period of the price (YYYY-MM-DD), set on the catalog\nstored in the database; the sheet and the mock data have no price history",
+                    "type": "string"
+                },
+                "effective_to": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "match": {
+                    "$ref": "#/definitions/handlers.CategorySearchMatch"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "reading": {
+                    "description": "ひらがなの読み",
+                    "type": "string"
+                },
+                "recycling_class": {
+                    "description": "RecyclingClass is set on appliances covered by the recycling law (家電リサイクル法)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ApplianceClass"
+                        }
+                    ]
+                },
+                "romaji": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "usage_count": {
+                    "description": "UsageCount is the number of past estimates using the item, set when ordered by frequency",
+                    "type": "integer"
+                },
+                "volume": {
+                    "description": "1点あたりの容積 (m³)",
+                    "type": "number"
+                },
+                "weight": {
+                    "description": "1点あたりの重量 (kg)",
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.GetCategoriesResponse": {
+            "type": "object",
+            "properties": {
+                "age_seconds": {
+                    "description": "シートから取得してからの秒数",
+                    "type": "integer"
+                },
+                "as_of": {
+                    "description": "価格の基準日 (YYYY-MM-DD)。価格履歴のあるデータベースの品目マスタのみ",
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.CategoryResponse"
+                    }
+                },
+                "fetched_at": {
+                    "description": "シートから取得した日時",
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.Item"
+                    }
+                },
+                "order": {
+                    "description": "display / popular",
+                    "type": "string"
+                },
+                "sorted": {
+                    "type": "boolean"
+                },
+                "source": {
+                    "description": "database / google_sheets / cache / stale_cache / mock_data",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Item": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "description": "EffectiveFrom and EffectiveTo are the period of the price (YYYY-MM-DD), set on the catalog\nstored in the database; the sheet and the mock data have no price history",
+                    "type": "string"
+                },
+                "effective_to": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "recycling_class": {
+                    "description": "RecyclingClass is set on appliances covered by the recycling law (家電リサイクル法)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ApplianceClass"
+                        }
+                    ]
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "usage_count": {
+                    "description": "UsageCount is the number of past estimates using the item, set when ordered by frequency",
+                    "type": "integer"
+                },
+                "volume": {
+                    "description": "1点あたりの容積 (m³)",
+                    "type": "number"
+                },
+                "weight": {
+                    "description": "1点あたりの重量 (kg)",
+                    "type": "number"
+                }
+            }
+        },
+        "models.ApplianceClass": {
+            "type": "string",
+            "enum": [
+                "tv",
+                "refrigerator",
+                "washing_machine",
+                "air_conditioner"
+            ],
+            "x-enum-comments": {
+                "ApplianceAirConditioner": "エアコン",
+                "ApplianceRefrigerator": "冷蔵庫・冷凍庫",
+                "ApplianceTV": "テレビ（ブラウン管・液晶・有機EL・プラズマ）",
+                "ApplianceWashingMachine": "洗濯機・衣類乾燥機"
+            },
+            "x-enum-descriptions": [
+                "テレビ（ブラウン管・液晶・有機EL・プラズマ）",
+                "冷蔵庫・冷凍庫",
+                "洗濯機・衣類乾燥機",
+                "エアコン"
+            ],
+            "x-enum-varnames": [
+                "ApplianceTV",
+                "ApplianceRefrigerator",
+                "ApplianceWashingMachine",
+                "ApplianceAirConditioner"
+            ]
+        },
+        "models.ApplianceRecycling": {
+            "type": "object",
+            "properties": {
+                "class": {
+                    "$ref": "#/definitions/models.ApplianceClass"
+                },
+                "manufacturer": {
+                    "description": "メーカー名（省略時は標準料金）",
+                    "type": "string"
+                },
+                "size_class": {
+                    "description": "テレビ・冷蔵庫のみ",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ApplianceSizeClass"
+                        }
+                    ]
+                },
+                "ticket_numbers": {
+                    "description": "リサイクル券のお問合せ管理票番号",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ApplianceSizeClass": {
+            "type": "string",
+            "enum": [
+                "small",
+                "large"
+            ],
+            "x-enum-comments": {
+                "ApplianceSizeLarge": "テレビ16型以上・冷蔵庫171L以上",
+                "ApplianceSizeSmall": "テレビ15型以下・冷蔵庫170L以下"
+            },
+            "x-enum-descriptions": [
+                "テレビ15型以下・冷蔵庫170L以下",
+                "テレビ16型以上・冷蔵庫171L以上"
+            ],
+            "x-enum-varnames": [
+                "ApplianceSizeSmall",
+                "ApplianceSizeLarge"
+            ]
+        },
+        "models.CatalogCategory": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "無効のカテゴリーは見積もりに表示しない",
+                    "type": "boolean"
+                },
+                "hiragana": {
+                    "description": "読み（ひらがな）",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogItem"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "description": "表示順",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CatalogCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "active": {
+                    "description": "省略時は有効",
+                    "type": "boolean"
+                },
+                "hiragana": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CatalogFileFormat": {
+            "type": "string",
+            "enum": [
+                "csv",
+                "xlsx"
+            ],
+            "x-enum-varnames": [
+                "CatalogFileCSV",
+                "CatalogFileXLSX"
+            ]
+        },
+        "models.CatalogImportDiff": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogImportItem"
+                    }
+                },
+                "price_changed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogPriceChange"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogImportItem"
+                    }
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "description": "価格以外（名前・読み・カテゴリーなど）が変わるアイテム",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogImportItem"
+                    }
+                }
+            }
+        },
+        "models.CatalogImportItem": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "hiragana": {
+                    "type": "string"
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CatalogImportResult": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "diff": {
+                    "$ref": "#/definitions/models.CatalogImportDiff"
+                },
+                "format": {
+                    "$ref": "#/definitions/models.CatalogFileFormat"
+                },
+                "report": {
+                    "$ref": "#/definitions/models.CatalogValidationReport"
+                }
+            }
+        },
+        "models.CatalogIssueSeverity": {
+            "type": "string",
+            "enum": [
+                "error",
+                "warning"
+            ],
+            "x-enum-comments": {
+                "CatalogIssueError": "公開を止める",
+                "CatalogIssueWarning": "公開はするが確認が必要"
+            },
+            "x-enum-descriptions": [
+                "公開を止める",
+                "公開はするが確認が必要"
+            ],
+            "x-enum-varnames": [
+                "CatalogIssueError",
+                "CatalogIssueWarning"
+            ]
+        },
+        "models.CatalogItem": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "description": "EffectiveFrom and EffectiveTo are the period of the price (YYYY-MM-DD). When saving,\na price different from the one effective on EffectiveFrom is recorded in the history.",
+                    "type": "string"
+                },
+                "effective_to": {
+                    "type": "string"
+                },
+                "hiragana": {
+                    "description": "読み（ひらがな）",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "description": "税抜単価",
+                    "type": "integer"
+                },
+                "recycling_class": {
+                    "$ref": "#/definitions/models.ApplianceClass"
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "description": "単位（例: 台、点）",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "volume": {
+                    "description": "1点あたりの容積 (m³)",
+                    "type": "number"
+                },
+                "weight": {
+                    "description": "1点あたりの重量 (kg)",
+                    "type": "number"
+                }
+            }
+        },
+        "models.CatalogItemPrice": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "description": "適用開始日 (YYYY-MM-DD)",
+                    "type": "string"
+                },
+                "effective_to": {
+                    "description": "適用終了日。空は次の改定が未定",
+                    "type": "string"
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "price": {
+                    "description": "税抜単価",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CatalogItemPriceHistory": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "description": "本日適用される価格。未適用の改定しかなければ空",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CatalogItemPrice"
+                        }
+                    ]
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "prices": {
+                    "description": "適用開始日の順。予定されている改定を含む",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogItemPrice"
+                    }
+                }
+            }
+        },
+        "models.CatalogItemPriceRequest": {
+            "type": "object",
+            "required": [
+                "effective_from"
+            ],
+            "properties": {
+                "effective_from": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.CatalogItemRequest": {
+            "type": "object",
+            "required": [
+                "category_id",
+                "hiragana",
+                "name"
+            ],
+            "properties": {
+                "active": {
+                    "description": "省略時は有効",
+                    "type": "boolean"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "hiragana": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "recycling_class": {
+                    "$ref": "#/definitions/models.ApplianceClass"
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "volume": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "weight": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.CatalogPriceChange": {
+            "type": "object",
+            "properties": {
+                "item_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "new_price": {
+                    "type": "integer"
+                },
+                "old_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CatalogSheetIssue": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "description": "列名（行全体の問題では空）",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "row": {
+                    "description": "シートの行番号（見出し行が1）",
+                    "type": "integer"
+                },
+                "severity": {
+                    "$ref": "#/definitions/models.CatalogIssueSeverity"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CatalogSyncDirection": {
+            "type": "string",
+            "enum": [
+                "pull",
+                "push"
+            ],
+            "x-enum-comments": {
+                "CatalogSyncPull": "シート → データベース",
+                "CatalogSyncPush": "データベース → シート"
+            },
+            "x-enum-descriptions": [
+                "シート → データベース",
+                "データベース → シート"
+            ],
+            "x-enum-varnames": [
+                "CatalogSyncPull",
+                "CatalogSyncPush"
+            ]
+        },
+        "models.CatalogSyncRequest": {
+            "type": "object",
+            "required": [
+                "direction"
+            ],
+            "properties": {
+                "direction": {
+                    "enum": [
+                        "pull",
+                        "push"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CatalogSyncDirection"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.CatalogSyncResult": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "integer"
+                },
+                "direction": {
+                    "$ref": "#/definitions/models.CatalogSyncDirection"
+                },
+                "items": {
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "取り込み元 (google_sheets / mock_data)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CatalogValidationReport": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer"
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogSheetIssue"
+                    }
+                },
+                "publishable": {
+                    "description": "エラーがなく1件以上のアイテムがある",
+                    "type": "boolean"
+                },
+                "rows": {
+                    "description": "見出しと空行を除いた行数",
+                    "type": "integer"
+                },
+                "valid_rows": {
+                    "description": "エラーのない行数",
+                    "type": "integer"
+                },
+                "warnings": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateEstimateRequest": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "conditions": {
+                    "description": "作業条件（割増ルールで明細を追加する）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.JobConditions"
+                        }
+                    ]
+                },
+                "customer": {
+                    "$ref": "#/definitions/models.EstimateCustomer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount": {
+                    "description": "書類全体の値引き",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Discount"
+                        }
+                    ]
+                },
+                "hourly_rate": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EstimateItem"
+                    }
+                },
+                "pricing": {
+                    "description": "省略時は税抜・税率ごとに切り捨て",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PricingMode"
+                        }
+                    ]
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total_lines": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "models.CreateInstructionRequest": {
+            "type": "object",
+            "properties": {
+                "accepted_by": {
+                    "description": "受付者",
+                    "type": "string"
+                },
+                "collection_date": {
+                    "description": "収集日（省略時は見積もりの処分希望日）",
+                    "type": "string"
+                },
+                "memo": {
+                    "description": "メモ（省略時は見積もりの説明）",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateInvoiceRequest": {
+            "type": "object",
+            "properties": {
+                "actual_weight": {
+                    "description": "実績重量 (kg)",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "due_date": {
+                    "description": "お支払期限 YYYY-MM-DD（省略時は発行日から規定日数後）",
+                    "type": "string"
+                },
+                "extra_items": {
+                    "description": "現場で追加になった明細",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EstimateItem"
+                    }
+                },
+                "work_date": {
+                    "description": "作業日（省略時は見積もりの処分希望日）",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateManifestRequest": {
+            "type": "object",
+            "required": [
+                "manifest_no",
+                "waste_type"
+            ],
+            "properties": {
+                "discharger": {
+                    "type": "string"
+                },
+                "disposal_method": {
+                    "type": "string"
+                },
+                "disposer": {
+                    "type": "string"
+                },
+                "issue_date": {
+                    "description": "省略時は当日",
+                    "type": "string"
+                },
+                "manifest_no": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "packing_form": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "site_address": {
+                    "type": "string"
+                },
+                "site_name": {
+                    "type": "string"
+                },
+                "special_control": {
+                    "type": "boolean"
+                },
+                "transporter": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "waste_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Discount": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "description": "書類全体の値引きの印字名（省略時は「値引き」）",
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "fixed",
+                        "percent"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DiscountType"
+                        }
+                    ]
+                },
+                "value": {
+                    "description": "円、または%",
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.DiscountType": {
+            "type": "string",
+            "enum": [
+                "fixed",
+                "percent"
+            ],
+            "x-enum-comments": {
+                "DiscountTypeFixed": "金額指定",
+                "DiscountTypePercent": "率指定 (%)"
+            },
+            "x-enum-descriptions": [
+                "金額指定",
+                "率指定 (%)"
+            ],
+            "x-enum-varnames": [
+                "DiscountTypeFixed",
+                "DiscountTypePercent"
+            ]
+        },
+        "models.Estimate": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "description": "階数や駐車距離などの作業条件",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.JobConditions"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "customer": {
+                    "$ref": "#/definitions/models.EstimateCustomer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount": {
+                    "description": "書類全体の値引き",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Discount"
+                        }
+                    ]
+                },
+                "discount_amount": {
+                    "description": "書類全体の値引き額（小計から差し引き済み）",
+                    "type": "number"
+                },
+                "estimate_no": {
+                    "type": "string"
+                },
+                "hourly_rate": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EstimateItem"
+                    }
+                },
+                "pricing": {
+                    "description": "税込/税抜と端数処理",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PricingMode"
+                        }
+                    ]
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.EstimateStatus"
+                },
+                "sub_total": {
+                    "type": "number"
+                },
+                "tax": {
+                    "type": "number"
+                },
+                "tax_breakdown": {
+                    "description": "税区分ごとの合計",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaxBreakdown"
+                    }
+                },
+                "tax_rate": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total_cost": {
+                    "type": "number"
+                },
+                "total_lines": {
+                    "type": "integer"
+                },
+                "transitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EstimateTransition"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EstimateCustomer": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "disposal_date": {
+                    "description": "処分希望日",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EstimateItem": {
+            "type": "object",
+            "required": [
+                "description"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "description": "カタログのカテゴリー名",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount": {
+                    "description": "明細の値引き",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Discount"
+                        }
+                    ]
+                },
+                "item_id": {
+                    "description": "カタログのアイテムID（自由入力の場合は空）",
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "recycling": {
+                    "description": "Recycling is set on appliances covered by the recycling law (家電リサイクル法)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ApplianceRecycling"
+                        }
+                    ]
+                },
+                "specification": {
+                    "type": "string"
+                },
+                "tax_category": {
+                    "description": "税区分（省略時は標準税率）",
+                    "enum": [
+                        "standard",
+                        "reduced",
+                        "exempt",
+                        "non_taxable"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TaxCategory"
+                        }
+                    ]
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "description": "買取や調整は負の単価で入力する",
+                    "type": "number"
+                }
+            }
+        },
+        "models.EstimateItemChange": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "from": {
+                    "$ref": "#/definitions/models.EstimateItem"
+                },
+                "item": {
+                    "type": "string"
+                },
+                "to": {
+                    "$ref": "#/definitions/models.EstimateItem"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EstimatePage": {
+            "type": "object",
+            "properties": {
+                "estimates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Estimate"
+                    }
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "条件に一致する件数",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EstimateRevision": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "estimate_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/models.EstimateSnapshot"
+                }
+            }
+        },
+        "models.EstimateRevisionDiff": {
+            "type": "object",
+            "properties": {
+                "estimate_id": {
+                    "type": "integer"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "from_revision": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EstimateItemChange"
+                    }
+                },
+                "to_revision": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EstimateSnapshot": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "$ref": "#/definitions/models.JobConditions"
+                },
+                "customer": {
+                    "$ref": "#/definitions/models.EstimateCustomer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount": {
+                    "$ref": "#/definitions/models.Discount"
+                },
+                "discount_amount": {
+                    "type": "number"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EstimateItem"
+                    }
+                },
+                "pricing": {
+                    "$ref": "#/definitions/models.PricingMode"
+                },
+                "sub_total": {
+                    "type": "number"
+                },
+                "tax": {
+                    "type": "number"
+                },
+                "tax_rate": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.EstimateStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "sent",
+                "accepted",
+                "declined",
+                "scheduled",
+                "collected",
+                "invoiced",
+                "closed",
+                "cancelled",
+                "expired"
+            ],
+            "x-enum-comments": {
+                "EstimateStatusAccepted": "受注",
+                "EstimateStatusCancelled": "キャンセル",
+                "EstimateStatusClosed": "完了",
+                "EstimateStatusCollected": "回収済み",
+                "EstimateStatusDeclined": "失注",
+                "EstimateStatusDraft": "下書き",
+                "EstimateStatusExpired": "期限切れ",
+                "EstimateStatusInvoiced": "請求済み",
+                "EstimateStatusScheduled": "日程確定",
+                "EstimateStatusSent": "提出済み"
+            },
+            "x-enum-descriptions": [
+                "下書き",
+                "提出済み",
+                "受注",
+                "失注",
+                "日程確定",
+                "回収済み",
+                "請求済み",
+                "完了",
+                "キャンセル",
+                "期限切れ"
+            ],
+            "x-enum-varnames": [
+                "EstimateStatusDraft",
+                "EstimateStatusSent",
+                "EstimateStatusAccepted",
+                "EstimateStatusDeclined",
+                "EstimateStatusScheduled",
+                "EstimateStatusCollected",
+                "EstimateStatusInvoiced",
+                "EstimateStatusClosed",
+                "EstimateStatusCancelled",
+                "EstimateStatusExpired"
+            ]
+        },
+        "models.EstimateTransition": {
+            "type": "object",
+            "properties": {
+                "estimate_id": {
+                    "type": "integer"
+                },
+                "from_status": {
+                    "$ref": "#/definitions/models.EstimateStatus"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "$ref": "#/definitions/models.EstimateStatus"
+                },
+                "transitioned_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "from": {},
+                "to": {}
+            }
+        },
+        "models.JobConditions": {
+            "type": "object",
+            "properties": {
+                "disassembly_count": {
+                    "description": "解体が必要な点数",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "floor": {
+                    "description": "搬出する階（1階は1）",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "has_elevator": {
+                    "description": "エレベーターの有無",
+                    "type": "boolean"
+                },
+                "night": {
+                    "description": "夜間作業",
+                    "type": "boolean"
+                },
+                "parking_distance": {
+                    "description": "駐車位置から搬出場所までの距離 (m)",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "same_day": {
+                    "description": "当日対応",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.LoadPlan": {
+            "type": "object",
+            "properties": {
+                "total_volume": {
+                    "description": "合計容積 (m³)",
+                    "type": "number"
+                },
+                "total_weight": {
+                    "description": "合計重量 (kg)",
+                    "type": "number"
+                },
+                "transport_fee": {
+                    "description": "収集運搬費 = 車両の料金 × 台数",
+                    "type": "number"
+                },
+                "trips": {
+                    "description": "必要な台数（往復回数）",
+                    "type": "integer"
+                },
+                "unmeasured_rows": {
+                    "description": "容積・重量が登録されていない行（1始まり）",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "vehicle": {
+                    "description": "推奨車両（車両が登録されていなければ null）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Vehicle"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.Manifest": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "discharger": {
+                    "description": "排出事業者の氏名または名称",
+                    "type": "string"
+                },
+                "disposal_method": {
+                    "description": "処分方法",
+                    "type": "string"
+                },
+                "disposer": {
+                    "description": "処分業者",
+                    "type": "string"
+                },
+                "estimate_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "instruction_id": {
+                    "type": "integer"
+                },
+                "issue_date": {
+                    "description": "交付年月日 YYYY-MM-DD",
+                    "type": "string"
+                },
+                "manifest_no": {
+                    "description": "交付番号",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "overdue": {
+                    "description": "返送期限を過ぎた写しがある",
+                    "type": "boolean"
+                },
+                "packing_form": {
+                    "description": "荷姿",
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "数量",
+                    "type": "number"
+                },
+                "site_address": {
+                    "description": "排出事業場の所在地",
+                    "type": "string"
+                },
+                "site_name": {
+                    "description": "排出事業場の名称",
+                    "type": "string"
+                },
+                "special_control": {
+                    "description": "特別管理産業廃棄物",
+                    "type": "boolean"
+                },
+                "stages": {
+                    "description": "B2・D・E票の返送状況",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ManifestStage"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ManifestStatus"
+                },
+                "transporter": {
+                    "description": "収集運搬業者",
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "t / m3 / kg",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "waste_type": {
+                    "description": "産業廃棄物の種類（例: 廃プラスチック類）",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ManifestAlert": {
+            "type": "object",
+            "properties": {
+                "copy": {
+                    "$ref": "#/definitions/models.ManifestCopy"
+                },
+                "days_left": {
+                    "description": "期限までの日数（超過は負数）",
+                    "type": "integer"
+                },
+                "discharger": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "estimate_id": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "manifest_id": {
+                    "type": "integer"
+                },
+                "manifest_no": {
+                    "type": "string"
+                },
+                "overdue": {
+                    "type": "boolean"
+                },
+                "report_by_date": {
+                    "description": "都道府県知事への報告期限",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ManifestCopy": {
+            "type": "string",
+            "enum": [
+                "B2",
+                "D",
+                "E"
+            ],
+            "x-enum-comments": {
+                "ManifestCopyB2": "運搬終了の写し",
+                "ManifestCopyD": "処分終了の写し",
+                "ManifestCopyE": "最終処分終了の写し"
+            },
+            "x-enum-descriptions": [
+                "運搬終了の写し",
+                "処分終了の写し",
+                "最終処分終了の写し"
+            ],
+            "x-enum-varnames": [
+                "ManifestCopyB2",
+                "ManifestCopyD",
+                "ManifestCopyE"
+            ]
+        },
+        "models.ManifestStage": {
+            "type": "object",
+            "properties": {
+                "completed_on": {
+                    "description": "写しに記載された終了年月日",
+                    "type": "string"
+                },
+                "copy": {
+                    "$ref": "#/definitions/models.ManifestCopy"
+                },
+                "due_date": {
+                    "description": "返送期限 YYYY-MM-DD",
+                    "type": "string"
+                },
+                "overdue": {
+                    "description": "期限を過ぎても返送されていない",
+                    "type": "boolean"
+                },
+                "returned_on": {
+                    "description": "写しを受け取った日（未返送は空）",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ManifestStatus": {
+            "type": "string",
+            "enum": [
+                "issued",
+                "transported",
+                "disposed",
+                "completed"
+            ],
+            "x-enum-comments": {
+                "ManifestStatusCompleted": "最終処分終了（E票受領）",
+                "ManifestStatusDisposed": "処分終了（D票受領）",
+                "ManifestStatusIssued": "交付済み",
+                "ManifestStatusTransported": "運搬終了（B2票受領）"
+            },
+            "x-enum-descriptions": [
+                "交付済み",
+                "運搬終了（B2票受領）",
+                "処分終了（D票受領）",
+                "最終処分終了（E票受領）"
+            ],
+            "x-enum-varnames": [
+                "ManifestStatusIssued",
+                "ManifestStatusTransported",
+                "ManifestStatusDisposed",
+                "ManifestStatusCompleted"
+            ]
+        },
+        "models.PDFCollectorInfo": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "住所",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "名称",
+                    "type": "string"
+                },
+                "person": {
+                    "description": "担当",
+                    "type": "string"
+                },
+                "recipient": {
+                    "description": "受付",
+                    "type": "string"
+                },
+                "tel": {
+                    "description": "TEL",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PDFContractorInfo": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "住所",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "名称",
+                    "type": "string"
+                },
+                "person": {
+                    "description": "担当",
+                    "type": "string"
+                },
+                "recipient": {
+                    "description": "受付",
+                    "type": "string"
+                },
+                "tel": {
+                    "description": "TEL",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PDFEstimatePreview": {
+            "type": "object",
+            "properties": {
+                "discount_amount": {
+                    "description": "書類全体の値引き額",
+                    "type": "number"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EstimateItem"
+                    }
+                },
+                "load_plan": {
+                    "description": "合計容積・重量と推奨車両",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LoadPlan"
+                        }
+                    ]
+                },
+                "mismatches": {
+                    "description": "金額が一致しない行（なければ空）",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceMismatch"
+                    }
+                },
+                "pricing": {
+                    "$ref": "#/definitions/models.PricingMode"
+                },
+                "sub_total": {
+                    "type": "number"
+                },
+                "tax": {
+                    "type": "number"
+                },
+                "tax_breakdown": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaxBreakdown"
+                    }
+                },
+                "tax_rate": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PDFEstimateRequest": {
+            "type": "object",
+            "properties": {
+                "addTransportFee": {
+                    "description": "AddTransportFee adds the transport fee of the recommended vehicle as a line (収集運搬費)",
+                    "type": "boolean"
+                },
+                "conditions": {
+                    "description": "作業条件（割増ルールで明細を追加する）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.JobConditions"
+                        }
+                    ]
+                },
+                "customer": {
+                    "$ref": "#/definitions/models.PDFRequestCustomer"
+                },
+                "discount": {
+                    "description": "書類全体の値引き",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Discount"
+                        }
+                    ]
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PDFImage"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PDFRequestItem"
                     }
                 },
+                "pricing": {
+                    "description": "省略時は税抜・税率ごとに切り捨て",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PricingMode"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.PDFImage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "base64 encoded image data",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 }
             }
         },
-        "handlers.GetCategoriesResponse": {
+        "models.PDFInstruction": {
             "type": "object",
             "properties": {
-                "categories": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handlers.CategoryResponse"
-                    }
+                "acceptance_check": {
+                    "description": "受付チェック",
+                    "type": "boolean"
+                },
+                "accepted_by": {
+                    "description": "受付者",
+                    "type": "string"
+                },
+                "collection_date": {
+                    "description": "収集日",
+                    "type": "string"
+                },
+                "collector": {
+                    "description": "控 - 収集先",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PDFCollectorInfo"
+                        }
+                    ]
+                },
+                "contractor": {
+                    "description": "作業指示書 - 収集先",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PDFContractorInfo"
+                        }
+                    ]
+                },
+                "instruction_no": {
+                    "type": "string"
+                },
+                "issue_date": {
+                    "type": "string"
                 },
                 "items": {
+                    "description": "作業内容",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handlers.Item"
+                        "$ref": "#/definitions/models.PDFWorkItem"
                     }
                 },
-                "sorted": {
-                    "type": "boolean"
+                "memo": {
+                    "description": "メモ（印刷されません）",
+                    "type": "string"
                 },
-                "source": {
+                "vehicle": {
+                    "description": "車両（例: 2tトラック × 1台）",
                     "type": "string"
+                },
+                "work_details": {
+                    "description": "作業詳細",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PDFWorkDetails"
+                        }
+                    ]
                 }
             }
         },
-        "handlers.Item": {
+        "models.PDFRequestCustomer": {
             "type": "object",
             "properties": {
-                "category": {
+                "address": {
                     "type": "string"
                 },
-                "id": {
+                "disposalDate": {
+                    "type": "string"
+                },
+                "email": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "integer"
+                "phone": {
+                    "type": "string"
                 }
             }
         },
-        "models.CreateEstimateRequest": {
+        "models.PDFRequestItem": {
             "type": "object",
-            "required": [
-                "hourly_rate",
-                "title",
-                "total_lines"
-            ],
             "properties": {
-                "description": {
+                "amount": {
+                    "type": "number"
+                },
+                "customPrice": {
+                    "description": "負の単価は other-custom（買取・調整）のみ",
+                    "type": "number"
+                },
+                "discount": {
+                    "description": "明細の値引き",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Discount"
+                        }
+                    ]
+                },
+                "id": {
                     "type": "string"
                 },
-                "hourly_rate": {
-                    "type": "number",
-                    "minimum": 0
+                "manufacturer": {
+                    "description": "Manufacturer and SizeClass select the recycling fee of recycling law appliances",
+                    "type": "string"
                 },
-                "title": {
+                "name": {
+                    "description": "自由入力の品名（other-custom のとき）",
                     "type": "string"
                 },
-                "total_lines": {
-                    "type": "integer",
-                    "minimum": 1
+                "quantity": {
+                    "type": "number"
+                },
+                "sizeClass": {
+                    "description": "small / large（テレビ・冷蔵庫のみ）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ApplianceSizeClass"
+                        }
+                    ]
+                },
+                "specification": {
+                    "type": "string"
+                },
+                "taxCategory": {
+                    "description": "standard / reduced / exempt / non_taxable（省略時は標準税率）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TaxCategory"
+                        }
+                    ]
                 }
             }
         },
-        "models.Estimate": {
+        "models.PDFWorkDetails": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "collection_amount": {
+                    "description": "集金額（税込）",
                     "type": "string"
                 },
-                "description": {
+                "manifest": {
+                    "description": "マニフェスト",
                     "type": "string"
                 },
-                "hourly_rate": {
-                    "type": "number"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "status": {
+                "points": {
+                    "description": "ポイント",
                     "type": "string"
                 },
-                "title": {
+                "recycling_ticket": {
+                    "description": "リサイクル券",
                     "type": "string"
                 },
-                "total_cost": {
-                    "type": "number"
+                "recycling_ticket_no": {
+                    "description": "リサイクル券 無",
+                    "type": "boolean"
                 },
-                "total_lines": {
-                    "type": "integer"
+                "tax_excluded_rate": {
+                    "description": "税抜@",
+                    "type": "string"
                 },
-                "updated_at": {
+                "v_point": {
+                    "description": "Vポイント",
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/models.User"
+                "weight": {
+                    "description": "計量",
+                    "type": "string"
                 },
-                "user_id": {
-                    "type": "integer"
+                "work_slip": {
+                    "description": "作業伝票",
+                    "type": "string"
                 }
             }
         },
-        "models.PDFCollectorInfo": {
+        "models.PDFWorkItem": {
             "type": "object",
             "properties": {
-                "address": {
-                    "description": "住所",
+                "description": {
                     "type": "string"
+                }
+            }
+        },
+        "models.PriceBasis": {
+            "type": "string",
+            "enum": [
+                "exclusive",
+                "inclusive"
+            ],
+            "x-enum-comments": {
+                "PriceBasisExclusive": "税抜価格（外税）",
+                "PriceBasisInclusive": "税込価格（内税・総額表示）"
+            },
+            "x-enum-descriptions": [
+                "税抜価格（外税）",
+                "税込価格（内税・総額表示）"
+            ],
+            "x-enum-varnames": [
+                "PriceBasisExclusive",
+                "PriceBasisInclusive"
+            ]
+        },
+        "models.PriceMismatch": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "リクエストの金額",
+                    "type": "number"
                 },
-                "name": {
-                    "description": "名称",
-                    "type": "string"
+                "expected": {
+                    "description": "サーバーで計算した金額",
+                    "type": "number"
                 },
-                "person": {
-                    "description": "担当",
+                "item_id": {
                     "type": "string"
                 },
-                "recipient": {
-                    "description": "受付",
-                    "type": "string"
+                "quantity": {
+                    "type": "number"
                 },
-                "tel": {
-                    "description": "TEL",
-                    "type": "string"
+                "row": {
+                    "description": "1始まりの行番号",
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "description": "指定単価、または未指定時のカタログ単価",
+                    "type": "number"
                 }
             }
         },
-        "models.PDFContractorInfo": {
+        "models.PricingMode": {
             "type": "object",
             "properties": {
-                "address": {
-                    "description": "住所",
-                    "type": "string"
+                "price_basis": {
+                    "enum": [
+                        "exclusive",
+                        "inclusive"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PriceBasis"
+                        }
+                    ]
+                },
+                "rounding": {
+                    "enum": [
+                        "floor",
+                        "round",
+                        "ceil"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TaxRounding"
+                        }
+                    ]
+                },
+                "rounding_unit": {
+                    "enum": [
+                        "document",
+                        "line"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TaxRoundingUnit"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.PricingRule": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "単価、固定金額、または最低料金 (円)",
+                    "type": "number"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.PricingRuleKind"
                 },
                 "name": {
-                    "description": "名称",
+                    "description": "明細に印字する名称",
                     "type": "string"
                 },
-                "person": {
-                    "description": "担当",
-                    "type": "string"
+                "percent": {
+                    "description": "割増率 (%)。当日対応・夜間のみ",
+                    "type": "number"
                 },
-                "recipient": {
-                    "description": "受付",
+                "sort_order": {
+                    "type": "integer"
+                },
+                "step": {
+                    "description": "距離の刻み (m)",
+                    "type": "number"
+                },
+                "tax_category": {
+                    "$ref": "#/definitions/models.TaxCategory"
+                },
+                "threshold": {
+                    "description": "割増なしで対応できる階数・距離",
+                    "type": "number"
+                },
+                "unit": {
+                    "description": "明細の単位",
                     "type": "string"
                 },
-                "tel": {
-                    "description": "TEL",
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.PDFEstimateRequest": {
+        "models.PricingRuleKind": {
+            "type": "string",
+            "enum": [
+                "floor",
+                "parking_distance",
+                "disassembly",
+                "same_day",
+                "night",
+                "minimum_charge"
+            ],
+            "x-enum-comments": {
+                "PricingRuleDisassembly": "解体: 1点ごとに金額",
+                "PricingRuleFloor": "エレベーターなしの階段作業: 基準階を超える1階ごとに金額",
+                "PricingRuleMinimumCharge": "最低料金: 合計が金額に満たない場合に差額を加算",
+                "PricingRuleNight": "夜間作業: 品目合計に対する割増率、または固定金額",
+                "PricingRuleParkingDistance": "横持ち: 基準距離を超える刻みごとに金額",
+                "PricingRuleSameDay": "当日対応: 品目合計に対する割増率、または固定金額"
+            },
+            "x-enum-descriptions": [
+                "エレベーターなしの階段作業: 基準階を超える1階ごとに金額",
+                "横持ち: 基準距離を超える刻みごとに金額",
+                "解体: 1点ごとに金額",
+                "当日対応: 品目合計に対する割増率、または固定金額",
+                "夜間作業: 品目合計に対する割増率、または固定金額",
+                "最低料金: 合計が金額に満たない場合に差額を加算"
+            ],
+            "x-enum-varnames": [
+                "PricingRuleFloor",
+                "PricingRuleParkingDistance",
+                "PricingRuleDisassembly",
+                "PricingRuleSameDay",
+                "PricingRuleNight",
+                "PricingRuleMinimumCharge"
+            ]
+        },
+        "models.RecordManifestReturnRequest": {
             "type": "object",
+            "required": [
+                "completed_on",
+                "copy"
+            ],
             "properties": {
-                "customer": {
-                    "$ref": "#/definitions/models.PDFRequestCustomer"
+                "completed_on": {
+                    "description": "写しに記載された終了年月日",
+                    "type": "string"
                 },
-                "images": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.PDFImage"
-                    }
+                "copy": {
+                    "enum": [
+                        "B2",
+                        "D",
+                        "E"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ManifestCopy"
+                        }
+                    ]
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.PDFRequestItem"
-                    }
+                "returned_on": {
+                    "description": "省略時は当日",
+                    "type": "string"
                 }
             }
         },
-        "models.PDFImage": {
+        "models.RecyclingFee": {
             "type": "object",
             "properties": {
-                "data": {
-                    "description": "base64 encoded image data",
-                    "type": "string"
+                "class": {
+                    "$ref": "#/definitions/models.ApplianceClass"
+                },
+                "fee": {
+                    "description": "リサイクル料金（税込・不課税）",
+                    "type": "number"
                 },
                 "id": {
+                    "type": "integer"
+                },
+                "manufacturer": {
                     "type": "string"
                 },
-                "name": {
+                "size_class": {
+                    "$ref": "#/definitions/models.ApplianceSizeClass"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.PDFInstruction": {
+        "models.RecyclingFeeRequest": {
             "type": "object",
+            "required": [
+                "class"
+            ],
             "properties": {
-                "acceptance_check": {
-                    "description": "受付チェック",
-                    "type": "boolean"
-                },
-                "accepted_by": {
-                    "description": "受付者",
-                    "type": "string"
-                },
-                "collection_date": {
-                    "description": "収集日",
-                    "type": "string"
-                },
-                "collector": {
-                    "description": "控 - 収集先",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.PDFCollectorInfo"
-                        }
-                    ]
-                },
-                "contractor": {
-                    "description": "作業指示書 - 収集先",
+                "class": {
+                    "enum": [
+                        "tv",
+                        "refrigerator",
+                        "washing_machine",
+                        "air_conditioner"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.PDFContractorInfo"
+                            "$ref": "#/definitions/models.ApplianceClass"
                         }
                     ]
                 },
-                "instruction_no": {
-                    "type": "string"
-                },
-                "issue_date": {
-                    "type": "string"
-                },
-                "items": {
-                    "description": "作業内容",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.PDFWorkItem"
-                    }
+                "fee": {
+                    "type": "number",
+                    "minimum": 0
                 },
-                "memo": {
-                    "description": "メモ（印刷されません）",
+                "manufacturer": {
                     "type": "string"
                 },
-                "work_details": {
-                    "description": "作業詳細",
+                "size_class": {
+                    "enum": [
+                        "small",
+                        "large"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.PDFWorkDetails"
+                            "$ref": "#/definitions/models.ApplianceSizeClass"
                         }
                     ]
                 }
             }
         },
-        "models.PDFRequestCustomer": {
+        "models.RecyclingTicketEntry": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "disposalDate": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
+                "line": {
+                    "description": "1始まりの明細行",
+                    "type": "integer",
+                    "minimum": 1
                 },
-                "phone": {
-                    "type": "string"
+                "ticket_numbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "models.PDFRequestItem": {
+        "models.RecyclingTicketsRequest": {
             "type": "object",
+            "required": [
+                "items"
+            ],
             "properties": {
-                "amount": {
-                    "type": "number"
-                },
-                "customPrice": {
-                    "type": "number"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "quantity": {
-                    "type": "number"
-                },
-                "specification": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecyclingTicketEntry"
+                    }
                 }
             }
         },
-        "models.PDFWorkDetails": {
+        "models.TaxBreakdown": {
             "type": "object",
             "properties": {
-                "collection_amount": {
-                    "description": "集金額（税込）",
-                    "type": "string"
-                },
-                "manifest": {
-                    "description": "マニフェスト",
-                    "type": "string"
-                },
-                "points": {
-                    "description": "ポイント",
-                    "type": "string"
-                },
-                "recycling_ticket": {
-                    "description": "リサイクル券",
-                    "type": "string"
-                },
-                "recycling_ticket_no": {
-                    "description": "リサイクル券 無",
-                    "type": "boolean"
-                },
-                "tax_excluded_rate": {
-                    "description": "税抜@",
-                    "type": "string"
+                "category": {
+                    "$ref": "#/definitions/models.TaxCategory"
                 },
-                "v_point": {
-                    "description": "Vポイント",
-                    "type": "string"
+                "rate": {
+                    "type": "number"
                 },
-                "weight": {
-                    "description": "計量",
-                    "type": "string"
+                "tax": {
+                    "description": "税率ごとに区分した消費税額（非課税・不課税は0）",
+                    "type": "number"
                 },
-                "work_slip": {
-                    "description": "作業伝票",
-                    "type": "string"
+                "taxable": {
+                    "description": "税区分ごとに区分した対価の額（税込価格の場合は税込）",
+                    "type": "number"
                 }
             }
         },
-        "models.PDFWorkItem": {
+        "models.TaxCategory": {
+            "type": "string",
+            "enum": [
+                "standard",
+                "reduced",
+                "exempt",
+                "non_taxable"
+            ],
+            "x-enum-comments": {
+                "TaxCategoryExempt": "非課税",
+                "TaxCategoryNonTaxable": "不課税（家電リサイクル料金などの預り金・立替金）",
+                "TaxCategoryReduced": "軽減税率",
+                "TaxCategoryStandard": "標準税率"
+            },
+            "x-enum-descriptions": [
+                "標準税率",
+                "軽減税率",
+                "非課税",
+                "不課税（家電リサイクル料金などの預り金・立替金）"
+            ],
+            "x-enum-varnames": [
+                "TaxCategoryStandard",
+                "TaxCategoryReduced",
+                "TaxCategoryExempt",
+                "TaxCategoryNonTaxable"
+            ]
+        },
+        "models.TaxRounding": {
+            "type": "string",
+            "enum": [
+                "floor",
+                "round",
+                "ceil"
+            ],
+            "x-enum-comments": {
+                "TaxRoundingCeil": "切り上げ",
+                "TaxRoundingFloor": "切り捨て",
+                "TaxRoundingRound": "四捨五入"
+            },
+            "x-enum-descriptions": [
+                "切り捨て",
+                "四捨五入",
+                "切り上げ"
+            ],
+            "x-enum-varnames": [
+                "TaxRoundingFloor",
+                "TaxRoundingRound",
+                "TaxRoundingCeil"
+            ]
+        },
+        "models.TaxRoundingUnit": {
+            "type": "string",
+            "enum": [
+                "document",
+                "line"
+            ],
+            "x-enum-comments": {
+                "TaxRoundingPerDocument": "書類ごと・税率ごとに1回（適格請求書の原則）",
+                "TaxRoundingPerLine": "明細行ごと"
+            },
+            "x-enum-descriptions": [
+                "書類ごと・税率ごとに1回（適格請求書の原則）",
+                "明細行ごと"
+            ],
+            "x-enum-varnames": [
+                "TaxRoundingPerDocument",
+                "TaxRoundingPerLine"
+            ]
+        },
+        "models.TransitionEstimateRequest": {
             "type": "object",
+            "required": [
+                "status"
+            ],
             "properties": {
-                "description": {
+                "note": {
                     "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.EstimateStatus"
                 }
             }
         },
         "models.UpdateEstimateRequest": {
             "type": "object",
             "properties": {
+                "conditions": {
+                    "description": "指定した場合は作業条件を置き換えて割増を計算し直す",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.JobConditions"
+                        }
+                    ]
+                },
+                "customer": {
+                    "$ref": "#/definitions/models.EstimateCustomer"
+                },
                 "description": {
                     "type": "string"
                 },
+                "discount": {
+                    "description": "指定した場合は書類全体の値引きを置き換える",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Discount"
+                        }
+                    ]
+                },
                 "hourly_rate": {
                     "type": "number",
                     "minimum": 0
                 },
-                "status": {
-                    "type": "string"
+                "items": {
+                    "description": "指定した場合は明細を置き換える",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EstimateItem"
+                    }
+                },
+                "pricing": {
+                    "description": "指定した場合は計算方法を変更する",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PricingMode"
+                        }
+                    ]
                 },
                 "title": {
                     "type": "string"
@@ -920,6 +5510,56 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdatePricingRuleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number",
+                    "maximum": 1000,
+                    "minimum": 0
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "step": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "tax_category": {
+                    "enum": [
+                        "standard",
+                        "reduced",
+                        "exempt",
+                        "non_taxable"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TaxCategory"
+                        }
+                    ]
+                },
+                "threshold": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
         "models.UpdateUserRequest": {
             "type": "object",
             "properties": {
@@ -958,6 +5598,37 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Vehicle": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "荷台の積載容積 (m³)",
+                    "type": "number"
+                },
+                "code": {
+                    "description": "light, 2t, 4t",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_load": {
+                    "description": "最大積載量 (kg)",
+                    "type": "number"
+                },
+                "name": {
+                    "description": "軽トラック / 2tトラック / 4tトラック",
+                    "type": "string"
+                },
+                "price": {
+                    "description": "1台1回あたりの収集運搬費 (円)",
+                    "type": "number"
+                },
+                "sort_order": {
+                    "type": "integer"
+                }
+            }
+        },
         "utils.ErrorResponse": {
             "description": "Standard error response structure",
             "type": "object",
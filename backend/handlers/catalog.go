@@ -0,0 +1,573 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+	"line-estimate-backend/services"
+	"line-estimate-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sources of the catalog reported by GetCategories
+const (
+	catalogSourceDatabase = "database"
+	catalogSourceSheets   = "google_sheets"
+	catalogSourceMock     = "mock_data"
+)
+
+// catalogSheetRange is the range of the categories sheet:
+// カテゴリーID, カテゴリー名, アイテムID, アイテム名, ひらがな, 価格, 容積, 重量, 家電リサイクル, 単位,
+// カテゴリー表示順, アイテム表示順
+const catalogSheetRange = "categories_data!A1:L"
+
+// CatalogHandler serves the catalog shown on the estimate screens and the endpoints for
+// editing the item master. The estimate and instruction handlers read the catalog through it.
+type CatalogHandler struct {
+	// catalog is the item master edited from the admin API. When it holds any category,
+	// it is served instead of Google Sheets or the mock data.
+	catalog repository.CatalogRepository
+	// usage counts how often catalog items appear in past estimates for the popular order
+	usage repository.EstimateRepository
+	// cache is the catalog read from Google Sheets; nil serves the mock data (development)
+	cache     *CatalogCache
+	syncSheet bool
+}
+
+// NewCatalogHandler creates a new CatalogHandler. When syncSheet is true, every change
+// is written back to the categories sheet so that it stays in sync with the database.
+func NewCatalogHandler(catalog repository.CatalogRepository, usage repository.EstimateRepository, cache *CatalogCache, syncSheet bool) *CatalogHandler {
+	return &CatalogHandler{catalog: catalog, usage: usage, cache: cache, syncSheet: syncSheet}
+}
+
+// GetCatalog godoc
+// @Summary 品目マスタを取得（管理用）
+// @Description 無効なカテゴリー・アイテムを含む品目マスタを表示順に取得します。単価は本日適用される価格です
+// @Tags Catalog
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.CatalogCategory}
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/categories [get]
+func (h *CatalogHandler) GetCatalog(c *gin.Context) {
+	categories, err := h.catalog.List(c.Request.Context())
+	if err != nil {
+		utils.Logger.Printf("Failed to list catalog: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get catalog")
+		return
+	}
+
+	utils.SuccessResponse(c, categories)
+}
+
+// CreateCatalogCategory godoc
+// @Summary カテゴリーを登録
+// @Description 品目マスタにカテゴリーを追加します。IDは英小文字・数字・ハイフンで、登録後は変更できません
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Param category body models.CatalogCategoryRequest true "カテゴリー"
+// @Success 201 {object} utils.Response{data=models.CatalogCategory}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/categories [post]
+func (h *CatalogHandler) CreateCatalogCategory(c *gin.Context) {
+	var req models.CatalogCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := models.ValidateCatalogID(req.ID); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category := req.ToCategory()
+	err := h.catalog.CreateCategory(c.Request.Context(), &category)
+	if errors.Is(err, repository.ErrDuplicate) {
+		utils.SendErrorResponse(c, http.StatusConflict, "Category already exists")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to create catalog category: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create category")
+		return
+	}
+	category.Items = []models.CatalogItem{}
+	h.syncToSheet(c.Request.Context())
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Data:    category,
+	})
+}
+
+// UpdateCatalogCategory godoc
+// @Summary カテゴリーを更新
+// @Description カテゴリーの名称・読み・表示順・有効フラグを更新します
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Param id path string true "カテゴリーID"
+// @Param category body models.CatalogCategoryRequest true "カテゴリー"
+// @Success 200 {object} utils.Response{data=models.CatalogCategory}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/categories/{id} [put]
+func (h *CatalogHandler) UpdateCatalogCategory(c *gin.Context) {
+	var req models.CatalogCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category := req.ToCategory()
+	category.ID = c.Param("id")
+	err := h.catalog.UpdateCategory(c.Request.Context(), &category)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Category not found")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to update catalog category %s: %v", category.ID, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to update category")
+		return
+	}
+	h.syncToSheet(c.Request.Context())
+
+	utils.SuccessResponse(c, category)
+}
+
+// DeleteCatalogCategory godoc
+// @Summary カテゴリーを削除
+// @Description アイテムのないカテゴリーを削除します。アイテムが残っている場合は無効にしてください
+// @Tags Catalog
+// @Produce json
+// @Param id path string true "カテゴリーID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/categories/{id} [delete]
+func (h *CatalogHandler) DeleteCatalogCategory(c *gin.Context) {
+	id := c.Param("id")
+	err := h.catalog.DeleteCategory(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		utils.SendErrorResponse(c, http.StatusNotFound, "Category not found")
+	case errors.Is(err, repository.ErrCategoryNotEmpty):
+		utils.SendErrorResponse(c, http.StatusConflict, "Category still has items")
+	case err != nil:
+		utils.Logger.Printf("Failed to delete catalog category %s: %v", id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to delete category")
+	default:
+		h.syncToSheet(c.Request.Context())
+		utils.SuccessResponse(c, gin.H{
+			"message": "Category deleted successfully",
+			"id":      id,
+		})
+	}
+}
+
+// CreateCatalogItem godoc
+// @Summary アイテムを登録
+// @Description 品目マスタにアイテムを追加します。IDは英小文字・数字・ハイフンで、登録後は変更できません
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Param item body models.CatalogItemRequest true "アイテム"
+// @Success 201 {object} utils.Response{data=models.CatalogItem}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/items [post]
+func (h *CatalogHandler) CreateCatalogItem(c *gin.Context) {
+	item, ok := bindCatalogItem(c)
+	if !ok {
+		return
+	}
+	if err := models.ValidateCatalogID(item.ID); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	item.EffectiveFrom = catalogToday()
+	err := h.catalog.CreateItem(c.Request.Context(), &item)
+	switch {
+	case errors.Is(err, repository.ErrUnknownCategory):
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Unknown category: "+item.CategoryID)
+		return
+	case errors.Is(err, repository.ErrDuplicate):
+		utils.SendErrorResponse(c, http.StatusConflict, "Item already exists")
+		return
+	case err != nil:
+		utils.Logger.Printf("Failed to create catalog item: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create item")
+		return
+	}
+	h.syncToSheet(c.Request.Context())
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Data:    item,
+	})
+}
+
+// UpdateCatalogItem godoc
+// @Summary アイテムを更新
+// @Description アイテムの名称・読み・単価・単位・有効フラグ・表示順などを更新します。単価の変更は本日から適用され、価格の履歴に残ります。作成済みの見積もりの単価は変わりません
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Param id path string true "アイテムID"
+// @Param item body models.CatalogItemRequest true "アイテム"
+// @Success 200 {object} utils.Response{data=models.CatalogItem}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/items/{id} [put]
+func (h *CatalogHandler) UpdateCatalogItem(c *gin.Context) {
+	item, ok := bindCatalogItem(c)
+	if !ok {
+		return
+	}
+	item.ID = c.Param("id")
+
+	// 単価を変えると本日から適用される価格として履歴に残る
+	item.EffectiveFrom = catalogToday()
+	err := h.catalog.UpdateItem(c.Request.Context(), &item)
+	switch {
+	case errors.Is(err, repository.ErrUnknownCategory):
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Unknown category: "+item.CategoryID)
+	case errors.Is(err, repository.ErrNotFound):
+		utils.SendErrorResponse(c, http.StatusNotFound, "Item not found")
+	case err != nil:
+		utils.Logger.Printf("Failed to update catalog item %s: %v", item.ID, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to update item")
+	default:
+		h.syncToSheet(c.Request.Context())
+		utils.SuccessResponse(c, item)
+	}
+}
+
+// DeleteCatalogItem godoc
+// @Summary アイテムを削除
+// @Description 品目マスタからアイテムを削除します。作成済みの見積もりの明細は残ります
+// @Tags Catalog
+// @Produce json
+// @Param id path string true "アイテムID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/items/{id} [delete]
+func (h *CatalogHandler) DeleteCatalogItem(c *gin.Context) {
+	id := c.Param("id")
+	err := h.catalog.DeleteItem(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Item not found")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to delete catalog item %s: %v", id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to delete item")
+		return
+	}
+	h.syncToSheet(c.Request.Context())
+
+	utils.SuccessResponse(c, gin.H{
+		"message": "Item deleted successfully",
+		"id":      id,
+	})
+}
+
+// CatalogValidationResponse is the validation report of the categories sheet
+type CatalogValidationResponse struct {
+	Source string `json:"source"` // 検証したデータ (google_sheets / mock_data)
+	models.CatalogValidationReport
+}
+
+// ValidateCatalogSheet godoc
+// @Summary スプレッドシートの品目マスタを検証
+// @Description スプレッドシート（開発環境ではモックデータ）を行ごとに検証します。見出し行の列名の違い（列のずれ）・IDの重複・読みの欠落・数値でないか負の価格・空のカテゴリーはエラーとなり、エラーのあるシートは見積もり画面にも取り込みにも使われません
+// @Tags Catalog
+// @Produce json
+// @Success 200 {object} utils.Response{data=CatalogValidationResponse}
+// @Failure 502 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/validate [get]
+func (h *CatalogHandler) ValidateCatalogSheet(c *gin.Context) {
+	source := catalogSourceMock
+	values := catalogSheetRows(catalogFromCategories(getMockCategories()))
+	if os.Getenv("GO_ENV") == "production" {
+		fetched, err := fetchCatalogSheet()
+		if err != nil {
+			utils.Logger.Printf("Failed to fetch catalog sheet: %v", err)
+			utils.SendErrorResponse(c, http.StatusBadGateway, "Failed to read catalog sheet")
+			return
+		}
+		source, values = catalogSourceSheets, fetched
+	}
+
+	_, report := models.ParseCatalogSheet(values)
+	utils.SuccessResponse(c, CatalogValidationResponse{Source: source, CatalogValidationReport: report})
+}
+
+// SyncCatalog godoc
+// @Summary 品目マスタとスプレッドシートを同期
+// @Description pull はスプレッドシート（開発環境ではモックデータ）の内容でデータベースの品目マスタを置き換えます。予約した価格改定が適用済みで、シートが改定前の価格のままのアイテムは改定後の価格を残します。push はデータベースの有効なアイテムをスプレッドシートに書き込みます
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Param sync body models.CatalogSyncRequest true "同期の方向"
+// @Success 200 {object} utils.Response{data=models.CatalogSyncResult}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.Response{data=models.CatalogValidationReport} "シートにエラーがあるため取り込みません"
+// @Failure 500 {object} utils.ErrorResponse
+// @Failure 502 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/sync [post]
+func (h *CatalogHandler) SyncCatalog(c *gin.Context) {
+	var req models.CatalogSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result := models.CatalogSyncResult{Direction: req.Direction}
+	if req.Direction == models.CatalogSyncPull {
+		source := catalogSourceMock
+		categories := getMockCategories()
+		if os.Getenv("GO_ENV") == "production" {
+			fetched, err := fetchCategoriesFromGoogleSheets()
+			var sheetErr *models.CatalogSheetError
+			if errors.As(err, &sheetErr) {
+				// エラーのあるシートはデータベースにも取り込まない
+				utils.SendErrorResponseWithData(c, http.StatusUnprocessableEntity, "Catalog sheet has errors", sheetErr.Report)
+				return
+			}
+			if err != nil {
+				utils.Logger.Printf("Failed to fetch catalog sheet: %v", err)
+				utils.SendErrorResponse(c, http.StatusBadGateway, "Failed to read catalog sheet")
+				return
+			}
+			source, categories = catalogSourceSheets, fetched
+		}
+
+		stored := catalogFromCategories(categories)
+		setPriceDate(stored, catalogToday())
+		if err := h.catalog.ReplaceAll(c.Request.Context(), stored); err != nil {
+			utils.Logger.Printf("Failed to import catalog: %v", err)
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to import catalog: "+err.Error())
+			return
+		}
+		// 予約した価格改定が適用済みなら、シートにも適用中の価格を書き戻す
+		h.syncToSheet(c.Request.Context())
+		result.Source = source
+		result.Categories, result.Items = countCatalog(stored)
+		utils.SuccessResponse(c, result)
+		return
+	}
+
+	categories, err := h.catalog.List(c.Request.Context())
+	if err != nil {
+		utils.Logger.Printf("Failed to list catalog: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get catalog")
+		return
+	}
+	if err := pushCatalogToSheet(categories); err != nil {
+		utils.Logger.Printf("Failed to push catalog to sheet: %v", err)
+		utils.SendErrorResponse(c, http.StatusBadGateway, "Failed to write catalog sheet")
+		return
+	}
+	active := categoriesFromCatalog(categories)
+	result.Source = catalogSourceDatabase
+	result.Categories = len(active)
+	for _, category := range active {
+		result.Items += len(category.Items)
+	}
+	utils.SuccessResponse(c, result)
+}
+
+// bindCatalogItem binds and validates the item request body,
+// writing the error response when it is invalid
+func bindCatalogItem(c *gin.Context) (models.CatalogItem, bool) {
+	var req models.CatalogItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return models.CatalogItem{}, false
+	}
+	item, err := req.ToItem()
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return models.CatalogItem{}, false
+	}
+	return item, true
+}
+
+// syncToSheet writes the catalog back to the sheet after a change when sync is enabled.
+// A failure is only logged; the database remains the source of truth.
+func (h *CatalogHandler) syncToSheet(ctx context.Context) {
+	if !h.syncSheet {
+		return
+	}
+	categories, err := h.catalog.List(ctx)
+	if err == nil {
+		err = pushCatalogToSheet(categories)
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to sync catalog to sheet: %v", err)
+	}
+}
+
+// catalogToday returns today's date in JST, from which price changes take effect
+func catalogToday() string {
+	return time.Now().In(jst).Format("2006-01-02")
+}
+
+// setPriceDate sets the date from which the prices of the items take effect when saved
+func setPriceDate(categories []models.CatalogCategory, date string) {
+	for i := range categories {
+		for j := range categories[i].Items {
+			categories[i].Items[j].EffectiveFrom = date
+		}
+	}
+}
+
+// pushCatalogToSheet overwrites the categories sheet with the active items
+func pushCatalogToSheet(categories []models.CatalogCategory) error {
+	spreadsheetID := os.Getenv("SPREADSHEET_ID")
+	if spreadsheetID == "" {
+		return fmt.Errorf("SPREADSHEET_ID is not configured")
+	}
+	sheetsService, err := services.NewSheetsService()
+	if err != nil {
+		return err
+	}
+	return sheetsService.ReplaceValues(spreadsheetID, catalogSheetRange, catalogSheetRows(categories))
+}
+
+// catalogSheetRows converts the active catalog into rows of the categories sheet
+func catalogSheetRows(categories []models.CatalogCategory) [][]string {
+	rows := [][]string{models.CatalogSheetColumns}
+	for _, category := range categoriesFromCatalog(categories) {
+		for _, item := range category.Items {
+			rows = append(rows, []string{
+				category.ID,
+				category.Name,
+				item.ID,
+				item.Name,
+				item.Hiragana,
+				strconv.Itoa(item.Price),
+				strconv.FormatFloat(item.Volume, 'f', -1, 64),
+				strconv.FormatFloat(item.Weight, 'f', -1, 64),
+				string(item.RecyclingClass),
+				item.Unit,
+				strconv.Itoa(category.SortOrder),
+				strconv.Itoa(item.SortOrder),
+			})
+		}
+	}
+	return rows
+}
+
+// categoriesFromCatalog converts the stored item master into the catalog served to
+// the estimate screens, leaving out inactive categories and items
+func categoriesFromCatalog(stored []models.CatalogCategory) []CategoryResponse {
+	categories := []CategoryResponse{}
+	for _, category := range stored {
+		if !category.Active {
+			continue
+		}
+		response := CategoryResponse{
+			ID:        category.ID,
+			Name:      category.Name,
+			Items:     []Item{},
+			Hiragana:  category.Hiragana,
+			SortOrder: category.SortOrder,
+		}
+		for _, item := range category.Items {
+			if !item.Active {
+				continue
+			}
+			response.Items = append(response.Items, Item{
+				ID:             item.ID,
+				Name:           item.Name,
+				Price:          item.Price,
+				Category:       category.ID,
+				Volume:         item.Volume,
+				Weight:         item.Weight,
+				Unit:           item.Unit,
+				RecyclingClass: item.RecyclingClass,
+				Hiragana:       item.Hiragana,
+				SortOrder:      item.SortOrder,
+				EffectiveFrom:  item.EffectiveFrom,
+				EffectiveTo:    item.EffectiveTo,
+			})
+		}
+		categories = append(categories, response)
+	}
+	return categories
+}
+
+// catalogFromCategories converts a catalog read from the sheet or the mock data into
+// the stored item master, keeping the order of the categories and items. Entries without
+// a display order are numbered after the preceding one.
+func catalogFromCategories(categories []CategoryResponse) []models.CatalogCategory {
+	stored := make([]models.CatalogCategory, 0, len(categories))
+	lastCategory := 0
+	for _, category := range categories {
+		entry := models.CatalogCategory{
+			ID:        category.ID,
+			Name:      category.Name,
+			Hiragana:  category.Hiragana,
+			SortOrder: nextDisplayOrder(category.SortOrder, lastCategory),
+			Active:    true,
+			Items:     make([]models.CatalogItem, 0, len(category.Items)),
+		}
+		lastCategory = entry.SortOrder
+		lastItem := 0
+		for _, item := range category.Items {
+			entry.Items = append(entry.Items, models.CatalogItem{
+				ID:             item.ID,
+				CategoryID:     category.ID,
+				Name:           item.Name,
+				Hiragana:       item.Hiragana,
+				Price:          item.Price,
+				Unit:           item.Unit,
+				Volume:         item.Volume,
+				Weight:         item.Weight,
+				RecyclingClass: item.RecyclingClass,
+				SortOrder:      nextDisplayOrder(item.SortOrder, lastItem),
+				Active:         true,
+			})
+			lastItem = entry.Items[len(entry.Items)-1].SortOrder
+		}
+		stored = append(stored, entry)
+	}
+	return stored
+}
+
+// nextDisplayOrder returns the display order of an entry following one ordered at last.
+// The given order is kept unless it is unset or would not come after last.
+func nextDisplayOrder(order, last int) int {
+	if order > last {
+		return order
+	}
+	return last + 10
+}
+
+// countCatalog returns the number of categories and items
+func countCatalog(categories []models.CatalogCategory) (int, int) {
+	items := 0
+	for _, category := range categories {
+		items += len(category.Items)
+	}
+	return len(categories), items
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/utils"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Sources of a catalog served from the Google Sheets cache
+const (
+	catalogSourceCache      = "cache"       // TTL内のキャッシュ
+	catalogSourceStaleCache = "stale_cache" // TTL切れ、またはシートの取得に失敗したためのキャッシュ
+)
+
+// catalogFetchKey is the singleflight key of fetching the categories sheet
+const catalogFetchKey = "categories"
+
+// DefaultCatalogCacheTTL is how long the catalog fetched from Google Sheets is served without refreshing
+const DefaultCatalogCacheTTL = 5 * time.Minute
+
+// catalogInfo describes where a served catalog came from
+type catalogInfo struct {
+	Source    string
+	FetchedAt time.Time // シートから取得した日時（データベース・モックデータは空）
+}
+
+// Age returns how old the catalog data is at now
+func (info catalogInfo) Age(now time.Time) time.Duration {
+	if info.FetchedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(info.FetchedAt)
+}
+
+// catalogCacheFile is the last-known-good catalog persisted to disk. The readings are
+// not serialized on CategoryResponse, so the stored item master form is used.
+type catalogCacheFile struct {
+	FetchedAt  time.Time                `json:"fetched_at"`
+	Categories []models.CatalogCategory `json:"categories"`
+}
+
+// CatalogCache keeps the catalog fetched from Google Sheets in memory. Stale data is
+// served while a refresh runs in the background, and the last successful fetch is
+// persisted to disk so that a restart during a Sheets outage still serves real prices.
+type CatalogCache struct {
+	fetch func() ([]CategoryResponse, error)
+	now   func() time.Time
+	group singleflight.Group // 同時に来た初回取得・更新をシートへの1回の取得にまとめる
+
+	mu         sync.Mutex
+	ttl        time.Duration
+	path       string // 空ならディスクに保存しない
+	categories []CategoryResponse
+	fetchedAt  time.Time
+	refreshing bool
+	fileRead   bool
+}
+
+// NewCatalogCache creates the cache of the catalog read from the categories sheet. The last
+// successful fetch is kept in the file at path. The cache is refreshed in the background when
+// a request finds it stale, so Sheets is only polled while it is the source of the catalog.
+func NewCatalogCache(ttl time.Duration, path string) *CatalogCache {
+	if ttl <= 0 {
+		ttl = DefaultCatalogCacheTTL
+	}
+	return newCatalogCache(fetchCategoriesFromGoogleSheets, ttl, path)
+}
+
+func newCatalogCache(fetch func() ([]CategoryResponse, error), ttl time.Duration, path string) *CatalogCache {
+	return &CatalogCache{fetch: fetch, now: time.Now, ttl: ttl, path: path}
+}
+
+// Get returns the cached catalog, fetching it synchronously only when neither memory
+// nor the last-known-good file holds one. Stale data triggers a background refresh.
+func (cc *CatalogCache) Get() ([]CategoryResponse, catalogInfo, error) {
+	cc.mu.Lock()
+	if cc.categories == nil && !cc.fileRead {
+		cc.fileRead = true
+		cc.readFileLocked()
+	}
+	if cc.categories != nil {
+		categories, fetchedAt := cc.categories, cc.fetchedAt
+		source := catalogSourceCache
+		if cc.now().Sub(fetchedAt) >= cc.ttl {
+			source = catalogSourceStaleCache
+			if !cc.refreshing {
+				cc.refreshing = true
+				go cc.refreshInBackground()
+			}
+		}
+		cc.mu.Unlock()
+		return categories, catalogInfo{Source: source, FetchedAt: fetchedAt}, nil
+	}
+	cc.mu.Unlock()
+
+	fetched, err := cc.load()
+	if err != nil {
+		return nil, catalogInfo{}, err
+	}
+	return fetched.categories, catalogInfo{Source: catalogSourceSheets, FetchedAt: fetched.fetchedAt}, nil
+}
+
+// fetchedCatalog is the result of a fetch shared by the callers waiting on it
+type fetchedCatalog struct {
+	categories []CategoryResponse
+	fetchedAt  time.Time
+}
+
+// load fetches the catalog and replaces the cache. Concurrent calls share a single fetch so
+// that a cold cache or an expired TTL under load does not hit the Sheets quota once per request.
+func (cc *CatalogCache) load() (fetchedCatalog, error) {
+	v, err, _ := cc.group.Do(catalogFetchKey, func() (interface{}, error) {
+		categories, err := cc.fetchCatalog()
+		if err != nil {
+			return nil, err
+		}
+		return fetchedCatalog{categories: categories, fetchedAt: cc.store(categories)}, nil
+	})
+	if err != nil {
+		return fetchedCatalog{}, err
+	}
+	return v.(fetchedCatalog), nil
+}
+
+// refresh fetches the catalog and replaces the cache. On failure the cache is kept.
+func (cc *CatalogCache) refresh() error {
+	_, err := cc.load()
+	return err
+}
+
+func (cc *CatalogCache) refreshInBackground() {
+	if err := cc.refresh(); err != nil {
+		utils.Logger.Printf("Catalog refresh failed, serving stale cache: %v", err)
+	}
+	cc.mu.Lock()
+	cc.refreshing = false
+	cc.mu.Unlock()
+}
+
+// fetchCatalog fetches the catalog and rejects an empty one so that it never replaces good data
+func (cc *CatalogCache) fetchCatalog() ([]CategoryResponse, error) {
+	categories, err := cc.fetch()
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("catalog sheet has no items")
+	}
+	return categories, nil
+}
+
+// store replaces the cache and persists it as the last-known-good catalog
+func (cc *CatalogCache) store(categories []CategoryResponse) time.Time {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.categories = categories
+	cc.fetchedAt = cc.now()
+	if cc.path != "" {
+		if err := cc.writeFileLocked(); err != nil {
+			utils.Logger.Printf("Failed to persist catalog cache: %v", err)
+		}
+	}
+	return cc.fetchedAt
+}
+
+// readFileLocked loads the last-known-good catalog. cc.mu must be held.
+func (cc *CatalogCache) readFileLocked() {
+	if cc.path == "" {
+		return
+	}
+	data, err := os.ReadFile(cc.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			utils.Logger.Printf("Failed to read catalog cache: %v", err)
+		}
+		return
+	}
+
+	var file catalogCacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		utils.Logger.Printf("Failed to decode catalog cache %s: %v", cc.path, err)
+		return
+	}
+	if len(file.Categories) == 0 {
+		return
+	}
+	cc.categories = categoriesFromCatalog(file.Categories)
+	cc.fetchedAt = file.FetchedAt
+}
+
+// writeFileLocked writes the cache to a temporary file and renames it so that a crash
+// never leaves a truncated file. cc.mu must be held.
+func (cc *CatalogCache) writeFileLocked() error {
+	data, err := json.Marshal(catalogCacheFile{
+		FetchedAt:  cc.fetchedAt,
+		Categories: catalogFromCategories(cc.categories),
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cc.path), 0755); err != nil {
+		return err
+	}
+	tmp := cc.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cc.path)
+}
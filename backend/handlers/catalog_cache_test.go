@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogCache(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		fail    bool
+		fetches int
+	)
+	refreshed := make(chan struct{}, 1)
+	fetch := func() ([]CategoryResponse, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		defer func() {
+			select {
+			case refreshed <- struct{}{}:
+			default:
+			}
+		}()
+		if fail {
+			return nil, errors.New("sheets unavailable")
+		}
+		return getMockCategories(), nil
+	}
+
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, jst)
+	path := filepath.Join(t.TempDir(), "catalog_cache.json")
+	cache := newCatalogCache(fetch, 5*time.Minute, path)
+	cache.now = func() time.Time { return now }
+
+	// 初回はシートから同期で取得する
+	categories, info, err := cache.Get()
+	require.NoError(t, err)
+	assert.Equal(t, catalogSourceSheets, info.Source)
+	assert.Len(t, categories, 6)
+	<-refreshed
+
+	// TTL内はキャッシュを返す
+	now = now.Add(4 * time.Minute)
+	_, info, err = cache.Get()
+	require.NoError(t, err)
+	assert.Equal(t, catalogSourceCache, info.Source)
+	assert.Equal(t, 4*time.Minute, info.Age(now))
+	assert.Equal(t, 1, fetches)
+
+	// TTL切れはキャッシュを返しつつ裏で更新し、失敗しても古いデータを保つ
+	mu.Lock()
+	fail = true
+	mu.Unlock()
+	now = now.Add(2 * time.Minute)
+	_, info, err = cache.Get()
+	require.NoError(t, err)
+	assert.Equal(t, catalogSourceStaleCache, info.Source)
+	<-refreshed
+	require.Eventually(t, func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return !cache.refreshing
+	}, time.Second, time.Millisecond)
+
+	// 再起動後は最後に取得できた内容をディスクから読み込み、読みも保たれる
+	restarted := newCatalogCache(fetch, 5*time.Minute, path)
+	restarted.now = func() time.Time { return now }
+	categories, info, err = restarted.Get()
+	require.NoError(t, err)
+	assert.Equal(t, catalogSourceStaleCache, info.Source)
+	assert.Equal(t, 6*time.Minute, info.Age(now))
+	assert.Equal(t, "ぱいぷいす", categories[0].Items[0].Hiragana)
+	<-refreshed
+
+	// キャッシュもシートもなければエラーにする（モックデータは返さない）
+	_, _, err = newCatalogCache(fetch, 5*time.Minute, "").Get()
+	assert.Error(t, err)
+}
+
+func TestCatalogCacheColdFillFetchesOnce(t *testing.T) {
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	fetch := func() ([]CategoryResponse, error) {
+		fetches.Add(1)
+		<-release
+		return getMockCategories(), nil
+	}
+	cache := newCatalogCache(fetch, 5*time.Minute, "")
+
+	// 初回取得中に来たリクエストは同じ取得結果を待つ
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			categories, _, err := cache.Get()
+			assert.NoError(t, err)
+			assert.Len(t, categories, 6)
+		}()
+	}
+	require.Eventually(t, func() bool { return fetches.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), fetches.Load())
+}
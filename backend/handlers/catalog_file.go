@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// catalogSheetName is the worksheet name of the exported workbook, matching the spreadsheet
+const catalogSheetName = "categories_data"
+
+// maxCatalogImportSize is the largest catalog file accepted by the import endpoints
+const maxCatalogImportSize = 5 << 20
+
+// utf8BOM lets Excel open the exported CSV as UTF-8
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// catalogNumericColumns are written as numbers in the workbook so that Excel can calculate with them
+var catalogNumericColumns = map[string]bool{
+	models.CatalogColumnPrice:         true,
+	models.CatalogColumnVolume:        true,
+	models.CatalogColumnWeight:        true,
+	models.CatalogColumnCategoryOrder: true,
+	models.CatalogColumnItemOrder:     true,
+}
+
+// ExportCategories godoc
+// @Summary カタログをCSV・Excelで出力
+// @Description 見積もり画面に表示しているカタログを、スプレッドシート (categories_data) と同じ列構成で出力します。CSVはExcelで開けるBOM付きUTF-8です
+// @Tags Categories
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "出力形式 (既定csv)" Enums(csv, xlsx)
+// @Success 200 {file} binary
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 503 {object} utils.ErrorResponse
+// @Router /api/v1/categories/export [get]
+func (h *CatalogHandler) ExportCategories(c *gin.Context) {
+	format := models.CatalogFileFormat(c.DefaultQuery("format", string(models.CatalogFileCSV)))
+	if !format.IsValid() {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "format must be csv or xlsx")
+		return
+	}
+
+	categories, _, err := h.loadCategories(c.Request.Context())
+	if err != nil {
+		utils.Logger.Printf("Failed to load catalog: %v", err)
+		utils.SendErrorResponse(c, http.StatusServiceUnavailable, "Catalog is temporarily unavailable")
+		return
+	}
+	rows := catalogSheetRows(catalogFromCategories(categories))
+
+	var buf bytes.Buffer
+	contentType := "text/csv; charset=UTF-8"
+	if format == models.CatalogFileXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		err = writeCatalogWorkbook(&buf, rows)
+	} else {
+		err = writeCatalogCSV(&buf, rows)
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to export catalog: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to export catalog")
+		return
+	}
+
+	filename := fmt.Sprintf("catalog_%s.%s", time.Now().In(jst).Format("20060102_150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// PreviewCatalogImport godoc
+// @Summary カタログの取り込みをプレビュー
+// @Description CSVまたはExcelファイルを検証し、現在のカタログとの差分（追加・削除・価格変更）を返します。品目マスタは変更しません
+// @Tags Catalog
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "カタログのファイル (.csv / .xlsx)"
+// @Param format formData string false "ファイル形式（省略時は拡張子から判定）" Enums(csv, xlsx)
+// @Success 200 {object} utils.Response{data=models.CatalogImportResult}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 503 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/import/preview [post]
+func (h *CatalogHandler) PreviewCatalogImport(c *gin.Context) {
+	result, _, ok := h.prepareCatalogImport(c)
+	if !ok {
+		return
+	}
+	utils.SuccessResponse(c, result)
+}
+
+// ImportCatalog godoc
+// @Summary カタログを取り込む
+// @Description CSVまたはExcelファイルの内容で品目マスタを置き換えます。ファイルにないアイテムは削除されます。エラーのある行が1行でもあれば取り込みません
+// @Tags Catalog
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "カタログのファイル (.csv / .xlsx)"
+// @Param format formData string false "ファイル形式（省略時は拡張子から判定）" Enums(csv, xlsx)
+// @Success 200 {object} utils.Response{data=models.CatalogImportResult}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.Response{data=models.CatalogImportResult} "ファイルにエラーがあるため取り込みません"
+// @Failure 500 {object} utils.ErrorResponse
+// @Failure 503 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/import [post]
+func (h *CatalogHandler) ImportCatalog(c *gin.Context) {
+	result, imported, ok := h.prepareCatalogImport(c)
+	if !ok {
+		return
+	}
+	if !result.Report.Publishable {
+		utils.SendErrorResponseWithData(c, http.StatusUnprocessableEntity, "Catalog file has errors", result)
+		return
+	}
+
+	setPriceDate(imported, catalogToday())
+	if err := h.catalog.ReplaceAll(c.Request.Context(), imported); err != nil {
+		utils.Logger.Printf("Failed to import catalog: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to import catalog: "+err.Error())
+		return
+	}
+	h.syncToSheet(c.Request.Context())
+
+	result.Applied = true
+	utils.SuccessResponse(c, result)
+}
+
+// prepareCatalogImport reads and validates the uploaded file and compares it with the
+// catalog currently served, writing the error response when the file cannot be read
+func (h *CatalogHandler) prepareCatalogImport(c *gin.Context) (models.CatalogImportResult, []models.CatalogCategory, bool) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "file is required")
+		return models.CatalogImportResult{}, nil, false
+	}
+	if header.Size > maxCatalogImportSize {
+		utils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("file must not exceed %d MB", maxCatalogImportSize>>20))
+		return models.CatalogImportResult{}, nil, false
+	}
+
+	format := models.CatalogFileFormat(c.PostForm("format"))
+	if format == "" {
+		format = models.CatalogFileFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), "."))
+	}
+	if !format.IsValid() {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "format must be csv or xlsx")
+		return models.CatalogImportResult{}, nil, false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Failed to read file: "+err.Error())
+		return models.CatalogImportResult{}, nil, false
+	}
+	defer file.Close()
+
+	var values [][]string
+	if format == models.CatalogFileXLSX {
+		values, err = readCatalogWorkbook(file)
+	} else {
+		values, err = readCatalogCSV(file)
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Failed to read "+string(format)+" file: "+err.Error())
+		return models.CatalogImportResult{}, nil, false
+	}
+
+	current, _, err := h.loadCategories(c.Request.Context())
+	if err != nil {
+		utils.Logger.Printf("Failed to load catalog: %v", err)
+		utils.SendErrorResponse(c, http.StatusServiceUnavailable, "Catalog is temporarily unavailable")
+		return models.CatalogImportResult{}, nil, false
+	}
+
+	rows, report := models.ParseCatalogSheet(values)
+	imported := catalogFromCategories(transformRowsToCategories(rows))
+	return models.CatalogImportResult{
+		Format: format,
+		Report: report,
+		Diff:   models.DiffCatalog(catalogFromCategories(current), imported),
+	}, imported, true
+}
+
+// writeCatalogCSV writes the rows as CSV. UTF-8 is used instead of Shift_JIS so that
+// names and readings survive a round trip through Excel unchanged.
+func writeCatalogCSV(w io.Writer, rows [][]string) error {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+	return writer.WriteAll(rows)
+}
+
+// readCatalogCSV reads a CSV file saved as UTF-8 (with or without BOM) or as Shift_JIS,
+// which Excel uses for "CSV (コンマ区切り)" on Japanese Windows
+func readCatalogCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if !utf8.Valid(data) {
+		if data, err = japanese.ShiftJIS.NewDecoder().Bytes(data); err != nil {
+			return nil, err
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1 // 末尾の省略可能な列は空でもよい
+	return reader.ReadAll()
+}
+
+// writeCatalogWorkbook writes the rows to the categories_data sheet of a workbook
+func writeCatalogWorkbook(w io.Writer, rows [][]string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := f.SetSheetName(f.GetSheetName(0), catalogSheetName); err != nil {
+		return err
+	}
+
+	header := rows[0]
+	for i, row := range rows {
+		values := make([]any, len(row))
+		for j, value := range row {
+			values[j] = value
+			if i == 0 || !catalogNumericColumns[header[j]] || value == "" {
+				continue
+			}
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				values[j] = number
+			}
+		}
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(catalogSheetName, cell, &values); err != nil {
+			return err
+		}
+	}
+	return f.Write(w)
+}
+
+// readCatalogWorkbook reads the categories_data sheet of a workbook, or its first sheet
+func readCatalogWorkbook(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sheet := f.GetSheetName(0)
+	if index, err := f.GetSheetIndex(catalogSheetName); err == nil && index >= 0 {
+		sheet = catalogSheetName
+	}
+	return f.GetRows(sheet)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+)
+
+func uploadCatalogFile(router *gin.Engine, path, filename string, data []byte) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("file", filename)
+	part.Write(data)
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", path, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCatalogExportImport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newTestDB(t)
+	catalog := repository.NewCatalogRepository(db)
+	ch := NewCatalogHandler(catalog, nil, nil, false)
+	router := gin.New()
+	router.GET("/categories/export", ch.ExportCategories)
+	router.GET("/catalog/categories", ch.GetCatalog)
+	router.POST("/catalog/import/preview", ch.PreviewCatalogImport)
+	router.POST("/catalog/import", ch.ImportCatalog)
+
+	decodeResult := func(w *httptest.ResponseRecorder) models.CatalogImportResult {
+		t.Helper()
+		var body struct {
+			Data models.CatalogImportResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.Data
+	}
+
+	w := doJSON(router, "GET", "/categories/export?format=pdf", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 出力したExcelをそのまま取り込んでも変更はない
+	w = doJSON(router, "GET", "/categories/export?format=xlsx", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = uploadCatalogFile(router, "/catalog/import/preview", "catalog.xlsx", w.Body.Bytes())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	result := decodeResult(w)
+	assert.True(t, result.Report.Publishable)
+	assert.Equal(t, 26, result.Diff.Unchanged)
+	assert.Empty(t, result.Diff.Added)
+	assert.Empty(t, result.Diff.PriceChanged)
+
+	w = doJSON(router, "GET", "/categories/export", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".csv")
+	values, err := readCatalogCSV(w.Body)
+	require.NoError(t, err)
+	require.Equal(t, models.CatalogSheetColumns, values[0])
+
+	// 価格変更・削除・追加を含むファイルを作る
+	edited := [][]string{values[0]}
+	for _, row := range values[1 : len(values)-1] {
+		if row[2] == "refrigerator" {
+			row[5] = "4500"
+		}
+		edited = append(edited, row)
+	}
+	removed := values[len(values)-1][2]
+	edited = append(edited, []string{"garden", "園芸用品", "planter", "プランター", "ぷらんたー", "800"})
+	var file bytes.Buffer
+	require.NoError(t, writeCatalogCSV(&file, edited))
+
+	w = uploadCatalogFile(router, "/catalog/import/preview", "catalog.csv", file.Bytes())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	result = decodeResult(w)
+	assert.False(t, result.Applied)
+	require.Len(t, result.Diff.Added, 1)
+	assert.Equal(t, "planter", result.Diff.Added[0].ItemID)
+	require.Len(t, result.Diff.Removed, 1)
+	assert.Equal(t, removed, result.Diff.Removed[0].ItemID)
+	require.Len(t, result.Diff.PriceChanged, 1)
+	assert.Equal(t, "refrigerator", result.Diff.PriceChanged[0].ItemID)
+	assert.Equal(t, 4500, result.Diff.PriceChanged[0].NewPrice)
+
+	// プレビューでは品目マスタを変更しない
+	w = doJSON(router, "GET", "/catalog/categories", nil)
+	assert.NotContains(t, w.Body.String(), "planter")
+
+	// エラーのある行があれば取り込まない
+	var broken bytes.Buffer
+	require.NoError(t, writeCatalogCSV(&broken, append(edited, []string{"garden", "園芸用品", "shovel", "スコップ", "", "500"})))
+	w = uploadCatalogFile(router, "/catalog/import", "catalog.csv", broken.Bytes())
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+	// 見出しが違う（列がずれた）ファイルは取り込まない
+	shifted := [][]string{append([]string{"no"}, values[0]...)}
+	for _, row := range edited[1:] {
+		shifted = append(shifted, append([]string{""}, row...))
+	}
+	var misaligned bytes.Buffer
+	require.NoError(t, writeCatalogCSV(&misaligned, shifted))
+	w = uploadCatalogFile(router, "/catalog/import", "catalog.csv", misaligned.Bytes())
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "header of column A")
+
+	w = uploadCatalogFile(router, "/catalog/import", "catalog.txt", file.Bytes())
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = uploadCatalogFile(router, "/catalog/import", "catalog.csv", file.Bytes())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, decodeResult(w).Applied)
+
+	// 読みは取り込んだファイルのまま保存される
+	w = doJSON(router, "GET", "/catalog/categories", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var stored struct {
+		Data []models.CatalogCategory `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stored))
+	hiragana := map[string]string{}
+	for _, category := range stored.Data {
+		for _, item := range category.Items {
+			hiragana[item.ID] = item.Hiragana
+		}
+	}
+	assert.Equal(t, "ぷらんたー", hiragana["planter"])
+	assert.NotContains(t, hiragana, removed)
+	for _, row := range edited[1:] {
+		assert.Equal(t, row[4], hiragana[row[2]], row[2])
+	}
+}
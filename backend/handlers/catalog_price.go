@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+	"line-estimate-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCatalogItemPrices godoc
+// @Summary アイテムの価格履歴を取得
+// @Description アイテムの価格改定の履歴を適用開始日の順に返します。予定されている改定と、削除済みのアイテムの履歴も含みます
+// @Tags Catalog
+// @Produce json
+// @Param id path string true "アイテムID"
+// @Success 200 {object} utils.Response{data=models.CatalogItemPriceHistory}
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/items/{id}/prices [get]
+func (h *CatalogHandler) GetCatalogItemPrices(c *gin.Context) {
+	id := c.Param("id")
+	prices, err := h.catalog.ListPrices(c.Request.Context(), id)
+	if err != nil {
+		utils.Logger.Printf("Failed to list prices of catalog item %s: %v", id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get price history")
+		return
+	}
+	if len(prices) == 0 {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Item not found")
+		return
+	}
+
+	history := models.CatalogItemPriceHistory{ItemID: id, Prices: prices}
+	if current, ok := models.PriceAsOf(prices, catalogToday()); ok {
+		history.Current = &current
+	}
+	utils.SuccessResponse(c, history)
+}
+
+// ScheduleCatalogItemPrice godoc
+// @Summary アイテムの価格改定を登録
+// @Description 指定日から適用される単価を登録します（例: 4月1日からの値上げ）。同じ日の価格は置き換えます。過去の日付は指定できず、作成済みの見積もりの単価は変わりません
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Param id path string true "アイテムID"
+// @Param price body models.CatalogItemPriceRequest true "価格改定"
+// @Success 201 {object} utils.Response{data=models.CatalogItemPrice}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/items/{id}/prices [post]
+func (h *CatalogHandler) ScheduleCatalogItemPrice(c *gin.Context) {
+	var req models.CatalogItemPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.Validate(catalogToday()); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	price := models.CatalogItemPrice{
+		ItemID:        c.Param("id"),
+		Price:         req.Price,
+		EffectiveFrom: req.EffectiveFrom,
+		Note:          req.Note,
+	}
+	err := h.catalog.SetPrice(c.Request.Context(), &price)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Item not found")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to set price of catalog item %s: %v", price.ItemID, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to set price")
+		return
+	}
+	if price.EffectiveFrom == catalogToday() {
+		h.syncToSheet(c.Request.Context())
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Data:    price,
+	})
+}
+
+// CancelCatalogItemPrice godoc
+// @Summary 予定している価格改定を取り消す
+// @Description 明日以降に適用される価格改定を取り消します。適用済みの価格は取り消せません
+// @Tags Catalog
+// @Produce json
+// @Param id path string true "アイテムID"
+// @Param effective_from path string true "適用開始日 (YYYY-MM-DD)"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/catalog/items/{id}/prices/{effective_from} [delete]
+func (h *CatalogHandler) CancelCatalogItemPrice(c *gin.Context) {
+	id, effectiveFrom := c.Param("id"), c.Param("effective_from")
+	if effectiveFrom <= catalogToday() {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Only price changes scheduled after today can be cancelled")
+		return
+	}
+
+	err := h.catalog.DeletePrice(c.Request.Context(), id, effectiveFrom)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Scheduled price not found")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to delete price of catalog item %s: %v", id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to cancel price change")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"message":        "Price change cancelled",
+		"id":             id,
+		"effective_from": effectiveFrom,
+	})
+}
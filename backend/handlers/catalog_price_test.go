@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+)
+
+func TestCatalogItemPrices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newTestDB(t)
+	catalog := repository.NewCatalogRepository(db)
+	ch := NewCatalogHandler(catalog, nil, nil, false)
+	router := gin.New()
+	router.GET("/categories", ch.GetCategories)
+	router.POST("/catalog/categories", ch.CreateCatalogCategory)
+	router.POST("/catalog/items", ch.CreateCatalogItem)
+	router.PUT("/catalog/items/:id", ch.UpdateCatalogItem)
+	router.GET("/catalog/items/:id/prices", ch.GetCatalogItemPrices)
+	router.POST("/catalog/items/:id/prices", ch.ScheduleCatalogItemPrice)
+	router.DELETE("/catalog/items/:id/prices/:effective_from", ch.CancelCatalogItemPrice)
+
+	now := time.Now().In(jst)
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	april := now.AddDate(0, 0, 30).Format("2006-01-02")
+
+	w := doJSON(router, "POST", "/catalog/categories", gin.H{"id": "appliances", "name": "家電"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	item := gin.H{"id": "refrigerator", "category_id": "appliances", "name": "冷蔵庫", "hiragana": "れいぞうこ", "price": 3000, "unit": "台"}
+	w = doJSON(router, "POST", "/catalog/items", item)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// 過去の日付には改定できない
+	w = doJSON(router, "POST", "/catalog/items/refrigerator/prices", gin.H{"price": 3500, "effective_from": yesterday})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = doJSON(router, "POST", "/catalog/items/unknown/prices", gin.H{"price": 3500, "effective_from": april})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = doJSON(router, "POST", "/catalog/items/refrigerator/prices", gin.H{"price": 4000, "effective_from": april, "note": "値上げ"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	priceAsOf := func(query string) Item {
+		t.Helper()
+		w := doJSON(router, "GET", "/categories?"+query, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body struct {
+			Data GetCategoriesResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Data.Categories, 1)
+		return body.Data.Categories[0].Items[0]
+	}
+
+	// 改定日の前日までは元の価格、改定日からは新しい価格
+	current := priceAsOf("")
+	assert.Equal(t, 3000, current.Price)
+	assert.Equal(t, today, current.EffectiveFrom)
+	assert.Equal(t, now.AddDate(0, 0, 29).Format("2006-01-02"), current.EffectiveTo)
+	scheduled := priceAsOf("as_of=" + april)
+	assert.Equal(t, 4000, scheduled.Price)
+	assert.Empty(t, scheduled.EffectiveTo)
+	w = doJSON(router, "GET", "/categories?as_of=04/01", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	// 登録前の日付には価格がないので、そのアイテムは返さない
+	w = doJSON(router, "GET", "/categories?as_of="+yesterday, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "refrigerator")
+
+	// 単価を変えずに更新しても履歴は増えない。変えると本日から適用される
+	item["name"] = "冷蔵庫（大型）"
+	w = doJSON(router, "PUT", "/catalog/items/refrigerator", item)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	item["price"] = 3200
+	w = doJSON(router, "PUT", "/catalog/items/refrigerator", item)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = doJSON(router, "GET", "/catalog/items/refrigerator/prices", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var history struct {
+		Data models.CatalogItemPriceHistory `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	assert.Equal(t, 3200, history.Data.Current.Price)
+	require.Len(t, history.Data.Prices, 2)
+	assert.Equal(t, april, history.Data.Prices[1].EffectiveFrom)
+	assert.Equal(t, "値上げ", history.Data.Prices[1].Note)
+
+	// 予定している改定だけを取り消せる
+	w = doJSON(router, "DELETE", "/catalog/items/refrigerator/prices/"+today, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = doJSON(router, "DELETE", "/catalog/items/refrigerator/prices/"+april, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 3200, priceAsOf("as_of="+april).Price)
+
+	w = doJSON(router, "GET", "/catalog/items/unknown/prices", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+)
+
+func TestCatalogAdministration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newTestDB(t)
+	catalog := repository.NewCatalogRepository(db)
+	ch := NewCatalogHandler(catalog, nil, nil, false)
+	router := newEstimateTestRouterWithCatalog(t, ch)
+	router.GET("/categories", ch.GetCategories)
+	router.GET("/catalog/categories", ch.GetCatalog)
+	router.POST("/catalog/categories", ch.CreateCatalogCategory)
+	router.DELETE("/catalog/categories/:id", ch.DeleteCatalogCategory)
+	router.POST("/catalog/items", ch.CreateCatalogItem)
+	router.PUT("/catalog/items/:id", ch.UpdateCatalogItem)
+	router.POST("/catalog/sync", ch.SyncCatalog)
+
+	// 品目マスタが空の間はモックデータを返す
+	w := doJSON(router, "GET", "/categories", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"source":"mock_data"`)
+	assert.NotContains(t, w.Body.String(), `"as_of"`)
+	// モックデータには価格履歴がないので基準日は指定できない
+	w = doJSON(router, "GET", "/categories?as_of=2026-04-01", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doJSON(router, "POST", "/catalog/categories", gin.H{"id": "Garden", "name": "園芸用品"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = doJSON(router, "POST", "/catalog/categories", gin.H{"id": "garden", "name": "園芸用品", "hiragana": "えんげいようひん"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = doJSON(router, "POST", "/catalog/categories", gin.H{"id": "garden", "name": "園芸用品"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	item := gin.H{"id": "planter", "category_id": "garden", "name": "プランター", "hiragana": "ぷらんたー", "price": 800, "unit": "個", "volume": 0.1, "weight": 5}
+	w = doJSON(router, "POST", "/catalog/items", gin.H{"id": "planter", "category_id": "unknown", "name": "プランター", "hiragana": "ぷらんたー"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = doJSON(router, "POST", "/catalog/items", item)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = doJSON(router, "POST", "/catalog/items", gin.H{"id": "lawn-mower", "category_id": "garden", "name": "芝刈り機", "hiragana": "しばかりき", "price": 3000, "active": false})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// アイテムが残っているカテゴリーは削除できない
+	w = doJSON(router, "DELETE", "/catalog/categories/garden", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// 品目マスタが登録されるとデータベースの有効なアイテムだけを返す
+	w = doJSON(router, "GET", "/categories", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var listed struct {
+		Data GetCategoriesResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	assert.Equal(t, "database", listed.Data.Source)
+	require.Len(t, listed.Data.Categories, 1)
+	require.Len(t, listed.Data.Categories[0].Items, 1)
+	assert.Equal(t, "planter", listed.Data.Categories[0].Items[0].ID)
+
+	// 単価の変更は新しい見積もりの計算に使われる
+	item["price"] = 1000
+	w = doJSON(router, "PUT", "/catalog/items/planter", item)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = doJSON(router, "POST", "/estimates/pdf/preview", gin.H{
+		"customer": gin.H{"name": "佐藤"},
+		"items":    []gin.H{{"id": "planter", "quantity": 2, "amount": 2000}},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var preview struct {
+		Data models.PDFEstimatePreview `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Empty(t, preview.Data.Mismatches)
+	assert.Equal(t, "個", preview.Data.Items[0].Unit)
+	assert.Equal(t, "園芸用品", preview.Data.Items[0].Category)
+
+	// 無効なアイテムは見積もりに使えない
+	w = doJSON(router, "POST", "/estimates/pdf/preview", gin.H{
+		"customer": gin.H{"name": "佐藤"},
+		"items":    []gin.H{{"id": "lawn-mower", "quantity": 1, "amount": 3000}},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 開発環境の pull はモックデータで品目マスタを置き換える
+	w = doJSON(router, "POST", "/catalog/sync", gin.H{"direction": "pull"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var synced struct {
+		Data models.CatalogSyncResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &synced))
+	assert.Equal(t, models.CatalogSyncResult{Direction: "pull", Source: "mock_data", Categories: 6, Items: 26}, synced.Data)
+
+	var stored struct {
+		Data []models.CatalogCategory `json:"data"`
+	}
+	w = doJSON(router, "GET", "/catalog/categories", nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stored))
+	require.Len(t, stored.Data, 6)
+	assert.Equal(t, "chairs", stored.Data[0].ID)
+	assert.Equal(t, "pipe-chair", stored.Data[0].Items[0].ID)
+}
+
+func TestValidateCatalogSheet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ch := NewCatalogHandler(nil, nil, nil, false)
+	router := gin.New()
+	router.GET("/catalog/validate", ch.ValidateCatalogSheet)
+
+	// 開発環境ではモックデータをシートの形式にして検証する
+	w := doJSON(router, "GET", "/catalog/validate", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body struct {
+		Data CatalogValidationResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, catalogSourceMock, body.Data.Source)
+	assert.True(t, body.Data.Publishable)
+	assert.Equal(t, 26, body.Data.ValidRows)
+	assert.Zero(t, body.Data.Errors)
+}
@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
+	"time"
 
+	"line-estimate-backend/models"
 	"line-estimate-backend/utils"
 
 	"github.com/gin-gonic/gin"
@@ -16,25 +21,49 @@ type CategoryResponse struct {
 	Name     string `json:"name"`
 	Items    []Item `json:"items"`
 	Hiragana string `json:"-"` // Internal field for sorting, not exposed in JSON
+	// SortOrder is the display order (表示順); categories and items are returned in this order
+	SortOrder int `json:"-"`
 }
 
 // Item represents an item within a category
 type Item struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Price    int    `json:"price"`
-	Category string `json:"category"`
-	Hiragana string `json:"-"` // Internal field for sorting, not exposed in JSON
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Price    int     `json:"price"`
+	Category string  `json:"category"`
+	Volume   float64 `json:"volume"` // 1点あたりの容積 (m³)
+	Weight   float64 `json:"weight"` // 1点あたりの重量 (kg)
+	Unit     string  `json:"unit,omitempty"`
+	// RecyclingClass is set on appliances covered by the recycling law (家電リサイクル法)
+	RecyclingClass models.ApplianceClass `json:"recycling_class,omitempty"`
+	// UsageCount is the number of past estimates using the item, set when ordered by frequency
+	UsageCount int `json:"usage_count,omitempty"`
+	// EffectiveFrom and EffectiveTo are the period of the price (YYYY-MM-DD), set on the catalog
+	// stored in the database; the sheet and the mock data have no price history
+	EffectiveFrom string `json:"effective_from,omitempty"`
+	EffectiveTo   string `json:"effective_to,omitempty"`
+	Hiragana      string `json:"-"` // Internal field for sorting, not exposed in JSON
+	SortOrder     int    `json:"-"` // 表示順
 }
 
 // GetCategoriesResponse represents the response for the GetCategories endpoint
 type GetCategoriesResponse struct {
 	Categories []CategoryResponse `json:"categories,omitempty"`
 	Items      []Item             `json:"items,omitempty"`
-	Source     string             `json:"source"`
+	Source     string             `json:"source"`               // database / google_sheets / cache / stale_cache / mock_data
+	FetchedAt  *time.Time         `json:"fetched_at,omitempty"` // シートから取得した日時
+	AgeSeconds int64              `json:"age_seconds"`          // シートから取得してからの秒数
 	Sorted     bool               `json:"sorted"`
+	Order      string             `json:"order"`           // display / popular
+	AsOf       string             `json:"as_of,omitempty"` // 価格の基準日 (YYYY-MM-DD)。価格履歴のあるデータベースの品目マスタのみ
 }
 
+// Orders of the categories and items returned by GetCategories
+const (
+	categoryOrderDisplay = "display" // 表示順
+	categoryOrderPopular = "popular" // 過去の見積もりでよく使われる順
+)
+
 // GetCategories godoc
 // @Summary カテゴリー一覧を取得
 // @Description 商品カテゴリーとアイテムの一覧を取得します
@@ -42,52 +71,212 @@ type GetCategoriesResponse struct {
 // @Accept json
 // @Produce json
 // @Param sort query string false "ひらがなでソートするかどうか (true/false)"
+// @Param order query string false "並び順 (display: 表示順 / popular: よく使われる順)"
+// @Param as_of query string false "価格の基準日 (YYYY-MM-DD、既定は本日)。データベースの品目マスタのみ指定でき、その日に適用される価格を返します"
 // @Success 200 {object} utils.Response{data=GetCategoriesResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 503 {object} utils.ErrorResponse
 // @Router /api/v1/categories [get]
-func GetCategories(c *gin.Context) {
+func (h *CatalogHandler) GetCategories(c *gin.Context) {
 	// Check if sort parameter is provided
 	sort := c.DefaultQuery("sort", "false") == "true"
+	order := c.DefaultQuery("order", categoryOrderDisplay)
+	if order != categoryOrderDisplay && order != categoryOrderPopular {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "order must be display or popular")
+		return
+	}
+	asOf, asOfGiven := c.GetQuery("as_of")
+	if !asOfGiven {
+		asOf = catalogToday()
+	} else if _, err := time.Parse("2006-01-02", asOf); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "as_of must be YYYY-MM-DD")
+		return
+	}
 
-	// Determine data source based on environment
-	env := os.Getenv("GO_ENV")
-	useGoogleSheets := env == "production"
-
-	var categories []CategoryResponse
-	var err error
+	categories, info, err := h.loadCategoriesAsOf(c.Request.Context(), asOf)
+	if err != nil {
+		utils.Logger.Printf("Failed to load catalog: %v", err)
+		utils.SendErrorResponse(c, http.StatusServiceUnavailable, "Catalog is temporarily unavailable")
+		return
+	}
+	// シートとモックデータには価格履歴がないので、基準日を指定されても本日の価格しか返せない
+	if info.Source != catalogSourceDatabase {
+		if asOfGiven {
+			utils.SendErrorResponse(c, http.StatusBadRequest, "as_of is only supported for the catalog stored in the database")
+			return
+		}
+		asOf = ""
+	}
+	age := int64(info.Age(time.Now()).Seconds())
 
-	if useGoogleSheets {
-		categories, err = fetchCategoriesFromGoogleSheets()
+	if order == categoryOrderPopular {
+		popular, err := h.orderByUsage(c.Request.Context(), categories)
 		if err != nil {
-			// Log error but fallback to mock data
-			utils.Logger.Printf("Google Sheets fetch failed, falling back to mock data: %v", err)
-			categories = getMockCategories()
+			// 使用回数が取れなくてもカタログは表示順で返す
+			utils.Logger.Printf("Failed to count item usage: %v", err)
+			order = categoryOrderDisplay
+		} else {
+			categories = popular
 		}
-	} else {
-		// Use mock data for development
-		categories = getMockCategories()
 	}
 
 	if sort {
 		// Create flat list of all items sorted by hiragana
 		allItems := sortAllItemsByHiragana(categories)
-		utils.SuccessResponse(c, gin.H{
-			"items":  allItems,
-			"source": getDataSource(useGoogleSheets),
-			"sorted": sort,
-		})
+		if order == categoryOrderPopular {
+			sortItemsByUsage(allItems)
+		}
+		utils.SuccessResponse(c, withAsOf(gin.H{
+			"items":       allItems,
+			"source":      info.Source,
+			"fetched_at":  fetchedAt(info),
+			"age_seconds": age,
+			"sorted":      sort,
+			"order":       order,
+		}, asOf))
 		return // End function execution here when sort=true
 	}
 
-	utils.SuccessResponse(c, gin.H{
-		"categories": categories,
-		"source":     getDataSource(useGoogleSheets),
-		"sorted":     sort,
+	utils.SuccessResponse(c, withAsOf(gin.H{
+		"categories":  categories,
+		"source":      info.Source,
+		"fetched_at":  fetchedAt(info),
+		"age_seconds": age,
+		"sorted":      sort,
+		"order":       order,
+	}, asOf))
+
+}
+
+// orderByUsage returns a copy of the catalog with the usage count of each item set,
+// ordering items by how many past estimates used them and categories by the total of
+// their items. Ties keep the display order. The cached catalog itself is not modified.
+func (h *CatalogHandler) orderByUsage(ctx context.Context, categories []CategoryResponse) ([]CategoryResponse, error) {
+	if h.usage == nil {
+		return nil, fmt.Errorf("item usage is not available")
+	}
+	usage, err := h.usage.CountItemUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]CategoryResponse, len(categories))
+	totals := make(map[string]int, len(categories))
+	for i, category := range categories {
+		category.Items = append([]Item(nil), category.Items...)
+		for j := range category.Items {
+			category.Items[j].UsageCount = usage[category.Items[j].ID]
+			totals[category.ID] += category.Items[j].UsageCount
+		}
+		sortItemsByUsage(category.Items)
+		ordered[i] = category
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return totals[ordered[i].ID] > totals[ordered[j].ID]
 	})
+	return ordered, nil
+}
+
+// sortItemsByUsage orders items by usage count, most used first, keeping the order of ties
+func sortItemsByUsage(items []Item) {
+	sort.SliceStable(items, func(i, j int) bool { return items[i].UsageCount > items[j].UsageCount })
+}
+
+// loadCategories returns the active catalog with today's prices together with where it came
+// from. The catalog stored from the admin API takes precedence; without it, the cached Google
+// Sheets catalog is used in production and the mock data otherwise. Production never falls
+// back to the mock data, so that sales staff are not shown made-up prices.
+func (h *CatalogHandler) loadCategories(ctx context.Context) ([]CategoryResponse, catalogInfo, error) {
+	return h.loadCategoriesAsOf(ctx, catalogToday())
+}
+
+// loadCategoriesAsOf is loadCategories with the prices effective on the date (YYYY-MM-DD).
+// Only the catalog stored in the database keeps price history; the sheet and the mock data
+// are returned with their current prices.
+func (h *CatalogHandler) loadCategoriesAsOf(ctx context.Context, date string) ([]CategoryResponse, catalogInfo, error) {
+	if h.catalog != nil {
+		stored, err := h.catalog.ListAsOf(ctx, date)
+		if err != nil {
+			utils.Logger.Printf("Failed to load stored catalog, falling back: %v", err)
+		} else if len(stored) > 0 {
+			return categoriesFromCatalog(stored), catalogInfo{Source: catalogSourceDatabase}, nil
+		}
+	}
+
+	if h.cache == nil {
+		// Use mock data for development
+		return getMockCategories(), catalogInfo{Source: catalogSourceMock}, nil
+	}
 
+	categories, info, err := h.cache.Get()
+	if err != nil {
+		return nil, catalogInfo{}, fmt.Errorf("unable to load catalog from Google Sheets: %v", err)
+	}
+	return categories, info, nil
 }
 
-// fetchCategoriesFromGoogleSheets fetches data from Google Sheets
+// withAsOf sets the price date on the response. It is left out for sources without price history.
+func withAsOf(response gin.H, asOf string) gin.H {
+	if asOf != "" {
+		response["as_of"] = asOf
+	}
+	return response
+}
+
+// fetchedAt returns the fetch time of a catalog read from Google Sheets, or nil for other sources
+func fetchedAt(info catalogInfo) *time.Time {
+	if info.FetchedAt.IsZero() {
+		return nil
+	}
+	return &info.FetchedAt
+}
+
+// customCatalogItemID is the catalog item used for free-text lines
+const customCatalogItemID = "other-custom"
+
+// catalogItem is a catalog item together with the name of its category
+type catalogItem struct {
+	Item
+	CategoryName string
+}
+
+// catalogItemsByID indexes the items of all categories by item ID
+func catalogItemsByID(categories []CategoryResponse) map[string]catalogItem {
+	items := map[string]catalogItem{}
+	for _, category := range categories {
+		for _, item := range category.Items {
+			items[item.ID] = catalogItem{Item: item, CategoryName: category.Name}
+		}
+	}
+	return items
+}
+
+// catalogHTTPClient fetches the categories sheet. A slow Sheets API must not hang requests.
+var catalogHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// fetchCategoriesFromGoogleSheets fetches the categories sheet and validates it. A sheet with
+// errors is rejected as a whole with a *models.CatalogSheetError, so that a typo never reaches
+// the estimate screens and the cache keeps serving the last-known-good catalog.
 func fetchCategoriesFromGoogleSheets() ([]CategoryResponse, error) {
+	values, err := fetchCatalogSheet()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, report := models.ParseCatalogSheet(values)
+	if !report.Publishable {
+		return nil, &models.CatalogSheetError{Report: report}
+	}
+	if report.Warnings > 0 {
+		utils.Logger.Printf("Catalog sheet has %d warnings", report.Warnings)
+	}
+
+	// Transform data to categories
+	return transformRowsToCategories(rows), nil
+}
+
+// fetchCatalogSheet fetches the values of the categories sheet, including the header row
+func fetchCatalogSheet() ([][]string, error) {
 	// Get API key and spreadsheet ID from environment
 	apiKey := os.Getenv("GOOGLE_SHEETS_API_KEY")
 	spreadsheetID := os.Getenv("SPREADSHEET_ID")
@@ -101,9 +290,9 @@ func fetchCategoriesFromGoogleSheets() ([]CategoryResponse, error) {
 	}
 
 	// Make HTTP request to Google Sheets API
-	url := "https://sheets.googleapis.com/v4/spreadsheets/" + spreadsheetID + "/values/categories_data!A1:F?key=" + apiKey
+	url := "https://sheets.googleapis.com/v4/spreadsheets/" + spreadsheetID + "/values/" + catalogSheetRange + "?key=" + apiKey
 
-	resp, err := http.Get(url)
+	resp, err := catalogHTTPClient.Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -126,64 +315,74 @@ func fetchCategoriesFromGoogleSheets() ([]CategoryResponse, error) {
 		return nil, err
 	}
 
-	// Skip header row (first row) for data processing
-	dataRows := data.Values
-	if len(dataRows) > 0 {
-		dataRows = dataRows[1:] // Remove header row
-	}
-
-	// Transform data to categories
-	categories := transformRowsToCategories(dataRows)
-
-	return categories, nil
+	return data.Values, nil
 }
 
-// transformRowsToCategories transforms Google Sheets rows to categories
-func transformRowsToCategories(rows [][]string) []CategoryResponse {
-	categoryMap := make(map[string]*CategoryResponse)
+// transformRowsToCategories transforms validated Google Sheets rows to categories.
+// Categories and items are ordered by their display order columns; those without one
+// follow in the order they first appear in the sheet, so the output is deterministic.
+func transformRowsToCategories(rows []models.CatalogSheetRow) []CategoryResponse {
+	categories := []CategoryResponse{}
+	index := make(map[string]int)
 
 	for _, row := range rows {
-		if len(row) < 6 { // Expecting 6 columns
-			continue
-		}
-
-		categoryID := row[0]
-		categoryName := row[1]
-		itemID := row[2]
-		itemName := row[3]
-		itemNameHiragana := row[4]       // Column E - hiragana (for items)
-		price, _ := strconv.Atoi(row[5]) // Parse price from column F
-
 		// Get or create category
-		if _, exists := categoryMap[categoryID]; !exists {
-			categoryMap[categoryID] = &CategoryResponse{
-				ID:       categoryID,
-				Name:     categoryName,
+		i, exists := index[row.CategoryID]
+		if !exists {
+			i = len(categories)
+			index[row.CategoryID] = i
+			categories = append(categories, CategoryResponse{
+				ID:       row.CategoryID,
+				Name:     row.CategoryName,
 				Items:    []Item{},
-				Hiragana: categoryName, // Category name for reference (not used for sorting)
-			}
+				Hiragana: row.CategoryName, // Category name for reference (not used for sorting)
+			})
+		}
+		// カテゴリーの表示順は最初に指定された行の値を使う
+		if categories[i].SortOrder == 0 {
+			categories[i].SortOrder = row.CategoryOrder
 		}
 
 		// Add item to category
-		categoryMap[categoryID].Items = append(categoryMap[categoryID].Items, Item{
-			ID:       itemID,
-			Name:     itemName,
-			Price:    price,
-			Category: categoryID,
-			Hiragana: itemNameHiragana, // Use hiragana from column E
+		categories[i].Items = append(categories[i].Items, Item{
+			ID:        row.ItemID,
+			Name:      row.ItemName,
+			Price:     row.Price,
+			Category:  row.CategoryID,
+			Volume:    row.Volume,
+			Weight:    row.Weight,
+			Unit:      row.Unit,
+			Hiragana:  row.Hiragana, // Use hiragana from column E
+			SortOrder: row.ItemOrder,
+
+			RecyclingClass: row.RecyclingClass,
 		})
 	}
 
-	// Convert map to slice
-	categories := make([]CategoryResponse, 0, len(categoryMap))
-	for _, category := range categoryMap {
-		categories = append(categories, *category)
-	}
-
+	sortByDisplayOrder(categories)
 	return categories
 }
 
-// sortAllItemsByHiragana collects all items from all categories and sorts them by hiragana
+// sortByDisplayOrder orders categories and their items by display order. Entries
+// without a display order (0) keep their relative order after the ordered ones.
+func sortByDisplayOrder(categories []CategoryResponse) {
+	before := func(a, b int) bool {
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+		return a < b
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		return before(categories[i].SortOrder, categories[j].SortOrder)
+	})
+	for _, category := range categories {
+		sort.SliceStable(category.Items, func(i, j int) bool {
+			return before(category.Items[i].SortOrder, category.Items[j].SortOrder)
+		})
+	}
+}
+
+// sortAllItemsByHiragana collects all items from all categories and sorts them in 五十音 order of their readings
 func sortAllItemsByHiragana(categories []CategoryResponse) []Item {
 	// Collect all items from all categories
 	var allItems []Item
@@ -191,14 +390,7 @@ func sortAllItemsByHiragana(categories []CategoryResponse) []Item {
 		allItems = append(allItems, category.Items...)
 	}
 
-	// Sort all items by hiragana using bubble sort
-	for i := 0; i < len(allItems)-1; i++ {
-		for j := i + 1; j < len(allItems); j++ {
-			if allItems[i].Hiragana > allItems[j].Hiragana {
-				allItems[i], allItems[j] = allItems[j], allItems[i]
-			}
-		}
-	}
+	utils.SortJapanese(allItems, func(item Item) string { return item.Hiragana })
 
 	return allItems
 }
@@ -207,79 +399,78 @@ func sortAllItemsByHiragana(categories []CategoryResponse) []Item {
 func getMockCategories() []CategoryResponse {
 	return []CategoryResponse{
 		{
-			ID:       "chairs",
-			Name:     "椅子",
-			Hiragana: "いす",
+			ID:        "chairs",
+			Name:      "椅子",
+			Hiragana:  "いす",
+			SortOrder: 10,
 			Items: []Item{
-				{ID: "pipe-chair", Name: "パイプ椅子", Price: 500, Category: "chairs", Hiragana: "ぱいぷいす"},
-				{ID: "office-chair", Name: "オフィスチェア", Price: 800, Category: "chairs", Hiragana: "おふぃすちぇあ"},
-				{ID: "sofa-1p", Name: "ソファー（1人掛け）", Price: 2000, Category: "chairs", Hiragana: "そふぁーひとりがけ"},
-				{ID: "sofa-2p", Name: "ソファー（2人掛け）", Price: 3000, Category: "chairs", Hiragana: "そふぁーふたりがけ"},
-				{ID: "sofa-3p", Name: "ソファー（3人掛け）", Price: 4000, Category: "chairs", Hiragana: "そふぁーさんにんがけ"},
+				{ID: "pipe-chair", Name: "パイプ椅子", Price: 500, Category: "chairs", Volume: 0.1, Weight: 4, Hiragana: "ぱいぷいす", SortOrder: 10},
+				{ID: "office-chair", Name: "オフィスチェア", Price: 800, Category: "chairs", Volume: 0.3, Weight: 12, Hiragana: "おふぃすちぇあ", SortOrder: 20},
+				{ID: "sofa-1p", Name: "ソファー（1人掛け）", Price: 2000, Category: "chairs", Volume: 0.6, Weight: 25, Hiragana: "そふぁーひとりがけ", SortOrder: 30},
+				{ID: "sofa-2p", Name: "ソファー（2人掛け）", Price: 3000, Category: "chairs", Volume: 1, Weight: 40, Hiragana: "そふぁーふたりがけ", SortOrder: 40},
+				{ID: "sofa-3p", Name: "ソファー（3人掛け）", Price: 4000, Category: "chairs", Volume: 1.5, Weight: 55, Hiragana: "そふぁーさんにんがけ", SortOrder: 50},
 			},
 		},
 		{
-			ID:       "tables",
-			Name:     "机・テーブル",
-			Hiragana: "つくえてーぶる",
+			ID:        "tables",
+			Name:      "机・テーブル",
+			Hiragana:  "つくえてーぶる",
+			SortOrder: 20,
 			Items: []Item{
-				{ID: "work-desk", Name: "事務机", Price: 1500, Category: "tables", Hiragana: "じむづくえ"},
-				{ID: "dining-table", Name: "ダイニングテーブル", Price: 2500, Category: "tables", Hiragana: "だいにんぐてーぶる"},
-				{ID: "coffee-table", Name: "コーヒーテーブル", Price: 1000, Category: "tables", Hiragana: "こーひーてーぶる"},
-				{ID: "side-table", Name: "サイドテーブル", Price: 700, Category: "tables", Hiragana: "さいどてーぶる"},
+				{ID: "work-desk", Name: "事務机", Price: 1500, Category: "tables", Volume: 0.8, Weight: 40, Hiragana: "じむづくえ", SortOrder: 10},
+				{ID: "dining-table", Name: "ダイニングテーブル", Price: 2500, Category: "tables", Volume: 0.8, Weight: 30, Hiragana: "だいにんぐてーぶる", SortOrder: 20},
+				{ID: "coffee-table", Name: "コーヒーテーブル", Price: 1000, Category: "tables", Volume: 0.3, Weight: 10, Hiragana: "こーひーてーぶる", SortOrder: 30},
+				{ID: "side-table", Name: "サイドテーブル", Price: 700, Category: "tables", Volume: 0.1, Weight: 5, Hiragana: "さいどてーぶる", SortOrder: 40},
 			},
 		},
 		{
-			ID:       "cabinets",
-			Name:     "タンス・収納",
-			Hiragana: "たんすしゅうのう",
+			ID:        "cabinets",
+			Name:      "タンス・収納",
+			Hiragana:  "たんすしゅうのう",
+			SortOrder: 30,
 			Items: []Item{
-				{ID: "clothes-cabinet", Name: "洋服タンス", Price: 3000, Category: "cabinets", Hiragana: "ようふくたんす"},
-				{ID: "bookshelf", Name: "本棚", Price: 1500, Category: "cabinets", Hiragana: "ほんだな"},
-				{ID: "tv-stand", Name: "テレビ台", Price: 2000, Category: "cabinets", Hiragana: "てれびだい"},
-				{ID: "chest", Name: "引き出し（4段）", Price: 2500, Category: "cabinets", Hiragana: "ひきだしよんだん"},
+				{ID: "clothes-cabinet", Name: "洋服タンス", Price: 3000, Category: "cabinets", Volume: 1.5, Weight: 60, Hiragana: "ようふくたんす", SortOrder: 10},
+				{ID: "bookshelf", Name: "本棚", Price: 1500, Category: "cabinets", Volume: 0.6, Weight: 25, Hiragana: "ほんだな", SortOrder: 20},
+				{ID: "tv-stand", Name: "テレビ台", Price: 2000, Category: "cabinets", Volume: 0.3, Weight: 20, Hiragana: "てれびだい", SortOrder: 30},
+				{ID: "chest", Name: "引き出し（4段）", Price: 2500, Category: "cabinets", Volume: 0.4, Weight: 30, Hiragana: "ひきだしよんだん", SortOrder: 40},
 			},
 		},
 		{
-			ID:       "appliances",
-			Name:     "家電製品",
-			Hiragana: "かでんせいひん",
+			ID:        "appliances",
+			Name:      "家電製品",
+			Hiragana:  "かでんせいひん",
+			SortOrder: 40,
 			Items: []Item{
-				{ID: "tv", Name: "テレビ", Price: 3500, Category: "appliances", Hiragana: "てれび"},
-				{ID: "refrigerator", Name: "冷蔵庫", Price: 5000, Category: "appliances", Hiragana: "れいぞうこ"},
-				{ID: "washing-machine", Name: "洗濯機", Price: 4000, Category: "appliances", Hiragana: "せんたくき"},
-				{ID: "microwave", Name: "電子レンジ", Price: 2000, Category: "appliances", Hiragana: "でんしれんじ"},
+				{ID: "tv", Name: "テレビ", Price: 3500, Category: "appliances", Volume: 0.2, Weight: 15, RecyclingClass: models.ApplianceTV, Hiragana: "てれび", SortOrder: 10},
+				{ID: "refrigerator", Name: "冷蔵庫", Price: 5000, Category: "appliances", Volume: 1.2, Weight: 70, RecyclingClass: models.ApplianceRefrigerator, Hiragana: "れいぞうこ", SortOrder: 20},
+				{ID: "washing-machine", Name: "洗濯機", Price: 4000, Category: "appliances", Volume: 0.5, Weight: 40, RecyclingClass: models.ApplianceWashingMachine, Hiragana: "せんたくき", SortOrder: 30},
+				{ID: "air-conditioner", Name: "エアコン", Price: 3000, Category: "appliances", Volume: 0.3, Weight: 40, RecyclingClass: models.ApplianceAirConditioner, Hiragana: "えあこん", SortOrder: 40},
+				{ID: "microwave", Name: "電子レンジ", Price: 2000, Category: "appliances", Volume: 0.1, Weight: 15, Hiragana: "でんしれんじ", SortOrder: 50},
 			},
 		},
 		{
-			ID:       "beds",
-			Name:     "ベッド・寝具",
-			Hiragana: "べっどしんぐ",
+			ID:        "beds",
+			Name:      "ベッド・寝具",
+			Hiragana:  "べっどしんぐ",
+			SortOrder: 50,
 			Items: []Item{
-				{ID: "single-bed", Name: "シングルベッド", Price: 3000, Category: "beds", Hiragana: "しんぐるべっど"},
-				{ID: "double-bed", Name: "ダブルベッド", Price: 4500, Category: "beds", Hiragana: "だぶるべっど"},
-				{ID: "mattress", Name: "マットレス", Price: 2000, Category: "beds", Hiragana: "まっとれす"},
-				{ID: "futon", Name: "布団", Price: 1500, Category: "beds", Hiragana: "ふとん"},
+				{ID: "single-bed", Name: "シングルベッド", Price: 3000, Category: "beds", Volume: 1.2, Weight: 40, Hiragana: "しんぐるべっど", SortOrder: 10},
+				{ID: "double-bed", Name: "ダブルベッド", Price: 4500, Category: "beds", Volume: 1.8, Weight: 60, Hiragana: "だぶるべっど", SortOrder: 20},
+				{ID: "mattress", Name: "マットレス", Price: 2000, Category: "beds", Volume: 0.6, Weight: 20, Hiragana: "まっとれす", SortOrder: 30},
+				{ID: "futon", Name: "布団", Price: 1500, Category: "beds", Volume: 0.2, Weight: 5, Hiragana: "ふとん", SortOrder: 40},
 			},
 		},
 		{
-			ID:       "other",
-			Name:     "その他",
-			Hiragana: "そのた",
+			ID:        "other",
+			Name:      "その他",
+			Hiragana:  "そのた",
+			SortOrder: 60,
 			Items: []Item{
-				{ID: "other-small", Name: "その他（小）", Price: 500, Category: "other", Hiragana: "そのたしょう"},
-				{ID: "other-medium", Name: "その他（中）", Price: 1500, Category: "other", Hiragana: "そのたちゅう"},
-				{ID: "other-large", Name: "その他（大）", Price: 3000, Category: "other", Hiragana: "そのただい"},
-				{ID: "other-custom", Name: "その他（カスタム）", Price: 0, Category: "other", Hiragana: "そのたかすたむ"},
+				{ID: "other-small", Name: "その他（小）", Price: 500, Category: "other", Volume: 0.1, Weight: 3, Hiragana: "そのたしょう", SortOrder: 10},
+				{ID: "other-medium", Name: "その他（中）", Price: 1500, Category: "other", Volume: 0.3, Weight: 10, Hiragana: "そのたちゅう", SortOrder: 20},
+				{ID: "other-large", Name: "その他（大）", Price: 3000, Category: "other", Volume: 0.8, Weight: 30, Hiragana: "そのただい", SortOrder: 30},
+				{ID: "other-custom", Name: "その他（カスタム）", Price: 0, Category: "other", Hiragana: "そのたかすたむ", SortOrder: 40},
 			},
 		},
 	}
 }
-
-// getDataSource returns the data source string for response
-func getDataSource(useGoogleSheets bool) string {
-	if useGoogleSheets {
-		return "google_sheets"
-	}
-	return "mock_data"
-}
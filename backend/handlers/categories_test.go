@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+)
+
+func TestGetCategoriesOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	numberer, err := repository.NewDocumentNumberer(db, nil)
+	require.NoError(t, err)
+	estimates := repository.NewEstimateRepository(db, numberer)
+
+	for _, itemIDs := range [][]string{{"futon", "tv"}, {"futon"}, {"futon", "futon", ""}} {
+		estimate := &models.Estimate{Title: "回収", Customer: models.EstimateCustomer{Name: "佐藤"}}
+		for _, id := range itemIDs {
+			estimate.Items = append(estimate.Items, models.EstimateItem{ItemID: id, Description: id, Quantity: 1, UnitPrice: 1000})
+		}
+		require.NoError(t, estimates.Create(ctx, estimate))
+	}
+
+	router := gin.New()
+	router.GET("/categories", NewCatalogHandler(nil, estimates, nil, false).GetCategories)
+	get := func(query string) GetCategoriesResponse {
+		t.Helper()
+		w := doJSON(router, "GET", "/categories"+query, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body struct {
+			Data GetCategoriesResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.Data
+	}
+
+	// 表示順はリクエストごとに変わらない
+	display := get("")
+	assert.Equal(t, categoryOrderDisplay, display.Order)
+	ids := []string{}
+	for _, category := range display.Categories {
+		ids = append(ids, category.ID)
+	}
+	assert.Equal(t, []string{"chairs", "tables", "cabinets", "appliances", "beds", "other"}, ids)
+	assert.Equal(t, "pipe-chair", display.Categories[0].Items[0].ID)
+
+	// よく使われる順: 布団(3件)のある寝具、テレビ(1件)のある家電、残りは表示順
+	popular := get("?order=popular")
+	assert.Equal(t, categoryOrderPopular, popular.Order)
+	require.Len(t, popular.Categories, 6)
+	assert.Equal(t, "beds", popular.Categories[0].ID)
+	assert.Equal(t, "futon", popular.Categories[0].Items[0].ID)
+	assert.Equal(t, 3, popular.Categories[0].Items[0].UsageCount)
+	assert.Equal(t, "appliances", popular.Categories[1].ID)
+	assert.Equal(t, "tv", popular.Categories[1].Items[0].ID)
+	assert.Equal(t, "chairs", popular.Categories[2].ID)
+
+	flat := get("?order=popular&sort=true")
+	require.NotEmpty(t, flat.Items)
+	assert.Equal(t, []string{"futon", "tv", "air-conditioner"}, []string{flat.Items[0].ID, flat.Items[1].ID, flat.Items[2].ID})
+
+	// キャッシュされたカタログは変更されない
+	assert.Equal(t, "pipe-chair", get("").Categories[0].Items[0].ID)
+
+	w := doJSON(router, "GET", "/categories?order=random", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTransformRowsToCategoriesOrder(t *testing.T) {
+	rows, report := models.ParseCatalogSheet([][]string{
+		models.CatalogSheetColumns,
+		{"other", "その他", "other-small", "その他（小）", "そのたしょう", "500"},
+		{"chairs", "椅子", "sofa", "ソファー", "そふぁー", "3000", "", "", "", "", "10", "20"},
+		{"chairs", "椅子", "pipe-chair", "パイプ椅子", "ぱいぷいす", "500", "", "", "", "", "", "10"},
+		{"beds", "寝具", "futon", "布団", "ふとん", "1500", "", "", "", "", "20"},
+	})
+	require.True(t, report.Publishable)
+
+	categories := transformRowsToCategories(rows)
+	require.Len(t, categories, 3)
+	assert.Equal(t, []string{"chairs", "beds", "other"}, []string{categories[0].ID, categories[1].ID, categories[2].ID})
+	assert.Equal(t, "pipe-chair", categories[0].Items[0].ID)
+
+	stored := catalogFromCategories(categories)
+	assert.Equal(t, []int{10, 20, 30}, []int{stored[0].SortOrder, stored[1].SortOrder, stored[2].SortOrder})
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"line-estimate-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultCategorySearchLimit is the number of candidates returned to the item picker
+const defaultCategorySearchLimit = 20
+
+// CategorySearchMatch tells which field of an item matched the query
+type CategorySearchMatch string
+
+const (
+	CategorySearchMatchName    CategorySearchMatch = "name"
+	CategorySearchMatchReading CategorySearchMatch = "reading"
+	CategorySearchMatchRomaji  CategorySearchMatch = "romaji"
+)
+
+// Scores of each kind of match. Exact and prefix matches rank above substring matches,
+// and the name ranks above the reading and romaji.
+const (
+	scoreExact            = 100
+	scoreNamePrefix       = 90
+	scoreReadingPrefix    = 85
+	scoreRomajiPrefix     = 80
+	scoreNameSubstring    = 60
+	scoreReadingSubstring = 55
+	scoreRomajiSubstring  = 50
+)
+
+// CategorySearchResult is a catalog item matching a search query
+type CategorySearchResult struct {
+	Item
+	CategoryName string              `json:"category_name"`
+	Reading      string              `json:"reading"` // ひらがなの読み
+	Romaji       string              `json:"romaji"`
+	Match        CategorySearchMatch `json:"match"`
+	Score        int                 `json:"score"`
+}
+
+// CategorySearchResponse represents the response for the SearchCategories endpoint
+type CategorySearchResponse struct {
+	Query   string                 `json:"query"`
+	Results []CategorySearchResult `json:"results"`
+	Source  string                 `json:"source"`
+}
+
+// SearchCategories godoc
+// @Summary カタログのアイテムを検索
+// @Description 品名（漢字）・ひらがなの読み・ローマ字の前方一致・部分一致でアイテムを検索し、一致度の高い順に返します。カタカナ・全角半角は区別しません
+// @Tags Categories
+// @Produce json
+// @Param q query string true "検索語（例: 冷蔵, れいぞ, reizo）"
+// @Param limit query int false "最大件数 (既定20)"
+// @Success 200 {object} utils.Response{data=CategorySearchResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 503 {object} utils.ErrorResponse
+// @Router /api/v1/categories/search [get]
+func (h *CatalogHandler) SearchCategories(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "q is required")
+		return
+	}
+	limit := defaultCategorySearchLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			utils.SendErrorResponse(c, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+		limit = n
+	}
+
+	categories, info, err := h.loadCategories(c.Request.Context())
+	if err != nil {
+		utils.Logger.Printf("Failed to load catalog: %v", err)
+		utils.SendErrorResponse(c, http.StatusServiceUnavailable, "Catalog is temporarily unavailable")
+		return
+	}
+
+	results := searchCatalog(categories, query)
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	utils.SuccessResponse(c, CategorySearchResponse{Query: query, Results: results, Source: info.Source})
+}
+
+// searchCatalog returns the items matching the query, best match first.
+// Items with the same score are ordered by their reading in 五十音 order.
+func searchCatalog(categories []CategoryResponse, query string) []CategorySearchResult {
+	normalized := utils.NormalizeKana(query)
+	// ローマ字で入力された場合はひらがなに変換して読みとも照合する（reizo → れいぞ）
+	kana := utils.RomajiToHiragana(normalized)
+	romaji := strings.ReplaceAll(normalized, " ", "")
+
+	results := []CategorySearchResult{}
+	for _, category := range categories {
+		for _, item := range category.Items {
+			reading := utils.NormalizeKana(item.Hiragana)
+			result := CategorySearchResult{
+				Item:         item,
+				CategoryName: category.Name,
+				Reading:      item.Hiragana,
+				Romaji:       utils.ToRomaji(item.Hiragana),
+			}
+			result.Match, result.Score = matchCatalogItem(utils.NormalizeKana(item.Name), reading, result.Romaji, normalized, kana, romaji)
+			if result.Score > 0 {
+				results = append(results, result)
+			}
+		}
+	}
+
+	utils.SortJapanese(results, func(result CategorySearchResult) string { return result.Reading })
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results
+}
+
+// matchCatalogItem returns the best match of the query against an item's name, reading and romaji
+func matchCatalogItem(name, reading, itemRomaji, query, kana, romaji string) (CategorySearchMatch, int) {
+	switch {
+	case name == query:
+		return CategorySearchMatchName, scoreExact
+	case reading != "" && (reading == query || reading == kana):
+		return CategorySearchMatchReading, scoreExact
+	case strings.HasPrefix(name, query):
+		return CategorySearchMatchName, scoreNamePrefix
+	case reading != "" && strings.HasPrefix(reading, query):
+		return CategorySearchMatchReading, scoreReadingPrefix
+	case reading != "" && kana != "" && kana != query && strings.HasPrefix(reading, kana):
+		return CategorySearchMatchReading, scoreReadingPrefix
+	case itemRomaji != "" && strings.HasPrefix(itemRomaji, romaji):
+		return CategorySearchMatchRomaji, scoreRomajiPrefix
+	case strings.Contains(name, query):
+		return CategorySearchMatchName, scoreNameSubstring
+	case reading != "" && (strings.Contains(reading, query) || (kana != "" && kana != query && strings.Contains(reading, kana))):
+		return CategorySearchMatchReading, scoreReadingSubstring
+	case itemRomaji != "" && romaji != "" && strings.Contains(itemRomaji, romaji):
+		return CategorySearchMatchRomaji, scoreRomajiSubstring
+	}
+	return "", 0
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchCategories(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ch := NewCatalogHandler(nil, nil, nil, false)
+	router := gin.New()
+	router.GET("/categories", ch.GetCategories)
+	router.GET("/categories/search", ch.SearchCategories)
+
+	search := func(query string) []CategorySearchResult {
+		t.Helper()
+		w := doJSON(router, "GET", "/categories/search?"+query, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body struct {
+			Data CategorySearchResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.Data.Results
+	}
+
+	// 漢字・ひらがな・カタカナ・ローマ字のいずれでも同じアイテムが見つかる
+	for _, query := range []string{"q=冷蔵", "q=れいぞ", "q=レイゾ", "q=reizo"} {
+		results := search(query)
+		require.NotEmpty(t, results, query)
+		assert.Equal(t, "refrigerator", results[0].ID, query)
+	}
+
+	// 同点は読みの五十音順に並ぶ
+	results := search("q=テーブル")
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"coffee-table", "side-table", "dining-table"}, []string{results[0].ID, results[1].ID, results[2].ID})
+	results = search("q=ほん")
+	require.Len(t, results, 1)
+	assert.Equal(t, CategorySearchMatchReading, results[0].Match)
+
+	results = search("q=sofa&limit=2")
+	require.Len(t, results, 2)
+	assert.Equal(t, CategorySearchMatchReading, results[0].Match)
+	assert.Equal(t, "sofa-3p", results[0].ID, "そふぁーさんにんがけ sorts before ひとりがけ and ふたりがけ")
+
+	w := doJSON(router, "GET", "/categories/search", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = doJSON(router, "GET", "/categories/search?q=a&limit=0", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// sort=true は読みの五十音順（長音・濁音を考慮）で返す
+	w = doJSON(router, "GET", "/categories?sort=true", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var sorted struct {
+		Data GetCategoriesResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sorted))
+	require.NotEmpty(t, sorted.Data.Items)
+	assert.Equal(t, "air-conditioner", sorted.Data.Items[0].ID)
+}
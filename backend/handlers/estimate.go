@@ -1,42 +1,85 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
 	"line-estimate-backend/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// EstimateHandler serves the estimate endpoints backed by an EstimateRepository
+type EstimateHandler struct {
+	repo     repository.EstimateRepository
+	numberer *repository.DocumentNumberer
+	rules    repository.PricingRuleRepository
+	vehicles repository.VehicleRepository
+	// recyclingFees is the fee table of recycling law appliances (家電リサイクル料金)
+	recyclingFees repository.RecyclingFeeRepository
+	// catalog provides the catalog the PDF requests and load plans are priced from
+	catalog *CatalogHandler
+	// registrationNumber is the issuer's qualified invoice registration number printed on estimates
+	registrationNumber string
+}
+
+// NewEstimateHandler creates a new EstimateHandler
+func NewEstimateHandler(repo repository.EstimateRepository, numberer *repository.DocumentNumberer, rules repository.PricingRuleRepository, vehicles repository.VehicleRepository, recyclingFees repository.RecyclingFeeRepository, catalog *CatalogHandler, registrationNumber string) *EstimateHandler {
+	return &EstimateHandler{repo: repo, numberer: numberer, rules: rules, vehicles: vehicles, recyclingFees: recyclingFees, catalog: catalog, registrationNumber: registrationNumber}
+}
+
 // GetEstimates godoc
 // @Summary 見積もり一覧を取得
-// @Description すべての見積もりを取得します
+// @Description 条件に一致する見積もりを検索し、カーソル方式でページングして取得します
 // @Tags Estimates
 // @Accept json
 // @Produce json
-// @Success 200 {object} utils.Response{data=map[string]interface{}}
+// @Param status query string false "ステータス（カンマ区切りで複数指定可）"
+// @Param created_from query string false "作成日の開始 (YYYY-MM-DD)"
+// @Param created_to query string false "作成日の終了 (YYYY-MM-DD, 当日を含む)"
+// @Param customer query string false "顧客名（部分一致）"
+// @Param phone query string false "電話番号（部分一致、ハイフン無視）"
+// @Param user_id query int false "担当者ID"
+// @Param min_total query number false "合計金額の下限"
+// @Param max_total query number false "合計金額の上限"
+// @Param sort query string false "並び順の項目" Enums(created_at, total, customer_name, estimate_no)
+// @Param order query string false "昇順/降順" Enums(asc, desc)
+// @Param cursor query string false "前ページの next_cursor"
+// @Param limit query int false "1ページの件数 (既定20, 最大100)"
+// @Success 200 {object} utils.Response{data=models.EstimatePage}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
 // @Router /api/v1/estimates/ [get]
-func GetEstimates(c *gin.Context) {
-	// TODO: データベースから見積もり一覧取得
-	estimates := []models.Estimate{
-		{
-			ID:          1,
-			UserID:      1,
-			Title:       "Webアプリケーション開発",
-			Description: "React + Node.js",
-			TotalLines:  5000,
-			HourlyRate:  8000,
-			TotalCost:   400000,
-			Status:      "completed",
-		},
+func (h *EstimateHandler) GetEstimates(c *gin.Context) {
+	var query models.EstimateListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	utils.SuccessResponse(c, gin.H{
-		"estimates": estimates,
-		"total":     len(estimates),
-	})
+	filter, err := query.ToFilter(jst)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.repo.List(c.Request.Context(), filter)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to list estimates: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get estimates")
+		return
+	}
+
+	utils.SuccessResponse(c, page)
 }
 
 // CreateEstimate godoc
@@ -48,34 +91,55 @@ func GetEstimates(c *gin.Context) {
 // @Param estimate body models.CreateEstimateRequest true "見積もり情報"
 // @Success 201 {object} utils.Response{data=models.Estimate}
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
 // @Router /api/v1/estimates/ [post]
-func CreateEstimate(c *gin.Context) {
+func (h *EstimateHandler) CreateEstimate(c *gin.Context) {
 	var req models.CreateEstimateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	if err := models.ValidateDiscounts(req.Items, req.Discount); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.Pricing.ValidateForIssuer(h.registrationNumber); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	userID := c.GetFloat64("userID")
 
-	// 総コスト計算（簡単な例：1行あたり0.1時間として計算）
-	estimatedHours := float64(req.TotalLines) * 0.1
-	totalCost := estimatedHours * req.HourlyRate
-
 	estimate := models.Estimate{
-		ID:          2,
 		UserID:      uint(userID),
 		Title:       req.Title,
 		Description: req.Description,
 		TotalLines:  req.TotalLines,
 		HourlyRate:  req.HourlyRate,
-		TotalCost:   totalCost,
-		Status:      "draft",
+		Customer:    req.Customer,
+		Items:       req.Items,
+		TaxRate:     models.DefaultTaxRate,
+		Pricing:     req.Pricing.OrDefault(),
+		Discount:    req.Discount,
+		Conditions:  req.Conditions,
+		Status:      models.EstimateStatusDraft,
+	}
+	if err := h.applyPricingRules(c.Request.Context(), &estimate); err != nil {
+		utils.Logger.Printf("Failed to apply pricing rules: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create estimate")
+		return
 	}
 
-	// TODO: データベースに保存
+	if err := h.repo.Create(c.Request.Context(), &estimate); err != nil {
+		utils.Logger.Printf("Failed to create estimate: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create estimate")
+		return
+	}
 
-	utils.SuccessResponse(c, estimate)
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Data:    estimate,
+	})
 }
 
 // GetEstimate godoc
@@ -87,84 +151,248 @@ func CreateEstimate(c *gin.Context) {
 // @Param id path int true "見積もりID"
 // @Success 200 {object} utils.Response{data=models.Estimate}
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
 // @Router /api/v1/estimates/{id} [get]
-func GetEstimate(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid estimate ID")
+func (h *EstimateHandler) GetEstimate(c *gin.Context) {
+	estimate, ok := h.loadEstimate(c)
+	if !ok {
 		return
 	}
 
-	// TODO: データベースから特定の見積もり取得
-	estimate := models.Estimate{
-		ID:          uint(id),
-		UserID:      1,
-		Title:       "Webアプリケーション開発",
-		Description: "React + Node.js",
-		TotalLines:  5000,
-		HourlyRate:  8000,
-		TotalCost:   400000,
-		Status:      "completed",
+	transitions, err := h.repo.ListTransitions(c.Request.Context(), estimate.ID)
+	if err != nil {
+		utils.Logger.Printf("Failed to list transitions of estimate %d: %v", estimate.ID, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get estimate")
+		return
 	}
+	estimate.Transitions = transitions
 
 	utils.SuccessResponse(c, estimate)
 }
 
 // UpdateEstimate godoc
 // @Summary 見積もりを更新
-// @Description 指定したIDの見積もりを更新します
+// @Description 指定したIDの見積もりを更新します。明細・値引き・作業条件・計算方法などの金額に関わる項目は下書きか提出済みの間だけ変更でき、受注後は件名や顧客情報のみ変更できます
 // @Tags Estimates
 // @Accept json
 // @Produce json
 // @Param id path int true "見積もりID"
 // @Param estimate body models.UpdateEstimateRequest true "更新する見積もり情報"
-// @Success 200 {object} utils.Response
+// @Success 200 {object} utils.Response{data=models.Estimate}
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Router /api/v1/estimates/{id} [put]
-func UpdateEstimate(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid estimate ID")
+func (h *EstimateHandler) UpdateEstimate(c *gin.Context) {
+	var req models.UpdateEstimateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	var req models.UpdateEstimateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	estimate, ok := h.loadEstimate(c)
+	if !ok {
+		return
+	}
+	if req.ChangesAmounts() && !estimate.Status.CanEditItems() {
+		utils.SendErrorResponse(c, http.StatusConflict, fmt.Sprintf("Items and prices cannot be changed in status %s (allowed: %v)", estimate.Status, models.ItemEditableStatuses))
+		return
+	}
+
+	if req.Title != "" {
+		estimate.Title = req.Title
+	}
+	if req.Description != "" {
+		estimate.Description = req.Description
+	}
+	if req.TotalLines > 0 {
+		estimate.TotalLines = req.TotalLines
+		estimate.HourlyRate = req.HourlyRate
+	}
+	if req.Customer != nil {
+		estimate.Customer = *req.Customer
+	}
+	if req.Items != nil {
+		estimate.Items = req.Items
+	}
+	if req.Conditions != nil {
+		estimate.Conditions = *req.Conditions
+	}
+	if req.Pricing != nil {
+		estimate.Pricing = req.Pricing.OrDefault()
+	}
+	if req.Discount != nil {
+		estimate.Discount = *req.Discount
+	}
+	if err := models.ValidateDiscounts(estimate.Items, estimate.Discount); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := estimate.Pricing.ValidateForIssuer(h.registrationNumber); err != nil {
 		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	if estimate.TaxRate == 0 {
+		estimate.TaxRate = models.DefaultTaxRate
+	}
+	if req.Items != nil || req.Conditions != nil {
+		// 割増は明細か作業条件が変わったときだけ現在のルールで計算し直す
+		if err := h.applyPricingRules(c.Request.Context(), estimate); err != nil {
+			utils.Logger.Printf("Failed to apply pricing rules to estimate %d: %v", estimate.ID, err)
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to update estimate")
+			return
+		}
+	} else {
+		estimate.Recalculate()
+	}
 
-	// TODO: データベースで見積もり更新
-	utils.SuccessResponse(c, gin.H{
-		"message": "Estimate updated successfully",
-		"id":      id,
-	})
+	if err := h.repo.Update(c.Request.Context(), estimate); err != nil {
+		utils.Logger.Printf("Failed to update estimate %d: %v", estimate.ID, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to update estimate")
+		return
+	}
+
+	utils.SuccessResponse(c, estimate)
 }
 
 // DeleteEstimate godoc
 // @Summary 見積もりを削除
-// @Description 指定したIDの見積もりを削除します
+// @Description 指定したIDの下書きの見積もりを削除します。送付済みの見積もりや、請求書・指示書・マニフェストを発行した見積もりは削除できません
 // @Tags Estimates
 // @Accept json
 // @Produce json
 // @Param id path int true "見積もりID"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Router /api/v1/estimates/{id} [delete]
-func DeleteEstimate(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid estimate ID")
+func (h *EstimateHandler) DeleteEstimate(c *gin.Context) {
+	id, ok := parseEstimateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			utils.SendErrorResponse(c, http.StatusNotFound, "Estimate not found")
+			return
+		}
+		if errors.Is(err, repository.ErrEstimateNotDraft) {
+			utils.SendErrorResponse(c, http.StatusConflict, "Only draft estimates can be deleted")
+			return
+		}
+		if errors.Is(err, repository.ErrEstimateHasDocuments) {
+			utils.SendErrorResponse(c, http.StatusConflict, "Estimate has issued invoices, instructions or manifests and cannot be deleted")
+			return
+		}
+		utils.Logger.Printf("Failed to delete estimate %d: %v", id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to delete estimate")
 		return
 	}
 
-	// TODO: データベースから見積もり削除
 	utils.SuccessResponse(c, gin.H{
 		"message": "Estimate deleted successfully",
 		"id":      id,
 	})
 }
 
+// TransitionEstimate godoc
+// @Summary 見積もりのステータスを遷移
+// @Description 見積もりのステータスをライフサイクルに従って変更し、遷移日時を記録します
+// @Tags Estimates
+// @Accept json
+// @Produce json
+// @Param id path int true "見積もりID"
+// @Param transition body models.TransitionEstimateRequest true "遷移先ステータス"
+// @Success 200 {object} utils.Response{data=models.Estimate}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/v1/estimates/{id}/transitions [post]
+func (h *EstimateHandler) TransitionEstimate(c *gin.Context) {
+	id, ok := parseEstimateID(c)
+	if !ok {
+		return
+	}
+
+	var req models.TransitionEstimateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !req.Status.IsValid() {
+		utils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Unknown status: %s", req.Status))
+		return
+	}
+
+	estimate, err := h.repo.Transition(c.Request.Context(), id, req.Status, req.Note)
+	if err != nil {
+		var transitionErr *models.TransitionError
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			utils.SendErrorResponse(c, http.StatusNotFound, "Estimate not found")
+		case errors.As(err, &transitionErr):
+			utils.SendErrorResponse(c, http.StatusConflict, fmt.Sprintf("%s (allowed: %v)", transitionErr.Error(), transitionErr.From.NextStatuses()))
+		default:
+			utils.Logger.Printf("Failed to transition estimate %d: %v", id, err)
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to transition estimate")
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, estimate)
+}
+
+// applyPricingRules replaces the surcharge lines of the estimate with the lines the current
+// pricing rules produce for its items and job conditions, then recomputes the totals.
+// Estimates without items get no surcharges.
+func (h *EstimateHandler) applyPricingRules(ctx context.Context, estimate *models.Estimate) error {
+	rules, err := h.rules.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	items := models.WithoutPricingRuleLines(estimate.Items)
+	if len(items) > 0 {
+		items = append(items, models.PricingRuleLines(items, estimate.Conditions, rules)...)
+	}
+	estimate.Items = items
+	estimate.Recalculate()
+	return nil
+}
+
+// parseEstimateID parses the :id path parameter, writing a 400 response on failure
+func parseEstimateID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid estimate ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// loadEstimate fetches the estimate addressed by the :id path parameter,
+// writing the appropriate error response when it cannot be loaded
+func (h *EstimateHandler) loadEstimate(c *gin.Context) (*models.Estimate, bool) {
+	id, ok := parseEstimateID(c)
+	if !ok {
+		return nil, false
+	}
+
+	estimate, err := h.repo.Get(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Estimate not found")
+		return nil, false
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to get estimate %d: %v", id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get estimate")
+		return nil, false
+	}
+	return estimate, true
+}
+
 // GetProfile godoc
 // @Summary ユーザープロフィールを取得
 // @Description 現在のユーザー情報を取得します
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+	"line-estimate-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListEstimateRevisions godoc
+// @Summary 見積もりの版一覧を取得
+// @Description 見積もりの変更履歴（版ごとのスナップショット）を古い順に取得します
+// @Tags Estimates
+// @Accept json
+// @Produce json
+// @Param id path int true "見積もりID"
+// @Success 200 {object} utils.Response{data=[]models.EstimateRevision}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/estimates/{id}/revisions [get]
+func (h *EstimateHandler) ListEstimateRevisions(c *gin.Context) {
+	estimate, ok := h.loadEstimate(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.repo.ListRevisions(c.Request.Context(), estimate.ID)
+	if err != nil {
+		utils.Logger.Printf("Failed to list revisions of estimate %d: %v", estimate.ID, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get revisions")
+		return
+	}
+
+	utils.SuccessResponse(c, revisions)
+}
+
+// GetEstimateRevisionDiff godoc
+// @Summary 見積もりの版の差分を取得
+// @Description 2つの版の間で変更された項目・明細を取得します
+// @Tags Estimates
+// @Accept json
+// @Produce json
+// @Param id path int true "見積もりID"
+// @Param from query int true "比較元の版"
+// @Param to query int true "比較先の版"
+// @Success 200 {object} utils.Response{data=models.EstimateRevisionDiff}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/estimates/{id}/revisions/diff [get]
+func (h *EstimateHandler) GetEstimateRevisionDiff(c *gin.Context) {
+	id, ok := parseEstimateID(c)
+	if !ok {
+		return
+	}
+
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Query parameters from and to must be revision numbers")
+		return
+	}
+
+	fromRevision, ok := h.loadRevision(c, id, from)
+	if !ok {
+		return
+	}
+	toRevision, ok := h.loadRevision(c, id, to)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, models.DiffRevisions(fromRevision, toRevision))
+}
+
+// GetEstimateRevisionPDF godoc
+// @Summary 見積もりの版のPDFを取得
+// @Description 指定した版の内容で見積書PDFを生成します（第2版以降は版数を印字）
+// @Tags Estimates
+// @Accept json
+// @Produce application/pdf
+// @Param id path int true "見積もりID"
+// @Param revision path int true "版数"
+// @Success 200 {file} binary
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/estimates/{id}/revisions/{revision}/pdf [get]
+func (h *EstimateHandler) GetEstimateRevisionPDF(c *gin.Context) {
+	estimate, ok := h.loadEstimate(c)
+	if !ok {
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid revision")
+		return
+	}
+
+	revision, ok := h.loadRevision(c, estimate.ID, number)
+	if !ok {
+		return
+	}
+
+	pdf, err := GenerateEstimatePDF(h.buildPDFEstimate(estimate.EstimateNo, revision.CreatedAt, revision.Revision, revision.Snapshot))
+	if err != nil {
+		utils.SendErrorResponse(c, 500, "PDF生成に失敗しました: "+err.Error())
+		return
+	}
+
+	writePDFResponse(c, pdf, fmt.Sprintf("estimate_%s_rev%d.pdf", estimate.EstimateNo, revision.Revision))
+}
+
+// loadRevision fetches a revision of an estimate, writing the appropriate error response on failure
+func (h *EstimateHandler) loadRevision(c *gin.Context, id uint, number int) (*models.EstimateRevision, bool) {
+	revision, err := h.repo.GetRevision(c.Request.Context(), id, number)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, fmt.Sprintf("Revision %d not found", number))
+		return nil, false
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to get revision %d of estimate %d: %v", number, id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get revision")
+		return nil, false
+	}
+	return revision, true
+}
@@ -0,0 +1,681 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+)
+
+// newTestDB opens an in-memory SQLite database with all migrations applied
+func newTestDB(t *testing.T) *repository.DB {
+	t.Helper()
+
+	db, err := repository.Open(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.Migrate())
+
+	return db
+}
+
+func newEstimateTestRouter(t *testing.T) *gin.Engine {
+	return newEstimateTestRouterWithCatalog(t, NewCatalogHandler(nil, nil, nil, false))
+}
+
+// newEstimateTestRouterWithCatalog is newEstimateTestRouter pricing estimates from the catalog
+func newEstimateTestRouterWithCatalog(t *testing.T, catalog *CatalogHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db := newTestDB(t)
+	numberer, err := repository.NewDocumentNumberer(db, nil)
+	require.NoError(t, err)
+	rules := repository.NewPricingRuleRepository(db)
+	h := NewEstimateHandler(repository.NewEstimateRepository(db, numberer), numberer, rules, repository.NewVehicleRepository(db), repository.NewRecyclingFeeRepository(db), catalog, "")
+	rh := NewPricingRuleHandler(rules)
+
+	router := gin.New()
+	router.GET("/estimates/", h.GetEstimates)
+	router.POST("/estimates/", h.CreateEstimate)
+	router.GET("/estimates/:id", h.GetEstimate)
+	router.PUT("/estimates/:id", h.UpdateEstimate)
+	router.DELETE("/estimates/:id", h.DeleteEstimate)
+	router.POST("/estimates/:id/transitions", h.TransitionEstimate)
+	router.PUT("/estimates/:id/recycling-tickets", h.SetRecyclingTickets)
+	router.GET("/estimates/:id/revisions", h.ListEstimateRevisions)
+	router.GET("/estimates/:id/revisions/diff", h.GetEstimateRevisionDiff)
+	router.GET("/estimates/:id/pdf", h.GetEstimatePDF)
+	router.POST("/estimates/pdf", h.CreateEstimatePDF)
+	router.POST("/estimates/pdf/preview", h.PreviewEstimatePDF)
+	router.POST("/estimates/pdf/load-plan", h.PlanEstimateLoad)
+	router.GET("/pricing-rules", rh.GetPricingRules)
+	router.PUT("/pricing-rules/:id", rh.UpdatePricingRule)
+	return router
+}
+
+func doJSON(router *gin.Engine, method, path string, body any) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestEstimateCRUD(t *testing.T) {
+	router := newEstimateTestRouter(t)
+
+	// 作成
+	w := doJSON(router, "POST", "/estimates/", gin.H{
+		"title":       "事務所片付け",
+		"total_lines": 100,
+		"hourly_rate": 5000,
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		Data struct {
+			ID     uint   `json:"id"`
+			Status string `json:"status"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotZero(t, created.Data.ID)
+	assert.Equal(t, "draft", created.Data.Status)
+
+	path := "/estimates/" + strconv.FormatUint(uint64(created.Data.ID), 10)
+
+	// 取得
+	w = doJSON(router, "GET", path, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "事務所片付け")
+
+	// 更新
+	w = doJSON(router, "PUT", path, gin.H{
+		"title":       "倉庫片付け",
+		"total_lines": 200,
+		"hourly_rate": 5000,
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "倉庫片付け")
+
+	// 一覧
+	w = doJSON(router, "GET", "/estimates/", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total":1`)
+
+	// 削除
+	w = doJSON(router, "DELETE", path, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doJSON(router, "GET", path, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeleteEstimateKeepsIssuedDocuments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newTestDB(t)
+	numberer, err := repository.NewDocumentNumberer(db, nil)
+	require.NoError(t, err)
+	estimates := repository.NewEstimateRepository(db, numberer)
+	h := NewEstimateHandler(estimates, numberer, repository.NewPricingRuleRepository(db), repository.NewVehicleRepository(db), repository.NewRecyclingFeeRepository(db), NewCatalogHandler(nil, nil, nil, false), "")
+	router := gin.New()
+	router.POST("/estimates/", h.CreateEstimate)
+	router.DELETE("/estimates/:id", h.DeleteEstimate)
+	router.POST("/estimates/:id/transitions", h.TransitionEstimate)
+
+	for i := 0; i < 2; i++ {
+		w := doJSON(router, "POST", "/estimates/", gin.H{"title": "不用品回収"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	// 送付済みの見積もりは削除できない
+	w := doJSON(router, "POST", "/estimates/1/transitions", gin.H{"status": "sent"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = doJSON(router, "DELETE", "/estimates/1", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// 請求書を発行した見積もりは削除できず、請求書も残る
+	invoices := repository.NewInvoiceRepository(db, numberer)
+	require.NoError(t, invoices.Create(context.Background(), &models.Invoice{EstimateID: 2, DueDate: time.Now()}))
+	w = doJSON(router, "DELETE", "/estimates/2", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	saved, err := invoices.ListByEstimate(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Len(t, saved, 1)
+}
+
+func TestGetEstimateInvalidID(t *testing.T) {
+	router := newEstimateTestRouter(t)
+
+	w := doJSON(router, "GET", "/estimates/abc", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTransitionEstimate(t *testing.T) {
+	router := newEstimateTestRouter(t)
+
+	w := doJSON(router, "POST", "/estimates/", gin.H{
+		"title":       "引越し不用品回収",
+		"total_lines": 10,
+		"hourly_rate": 1000,
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	path := "/estimates/1/transitions"
+
+	// draft → accepted は不正な遷移
+	w = doJSON(router, "POST", path, gin.H{"status": "accepted"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// 未知のステータス
+	w = doJSON(router, "POST", path, gin.H{"status": "completed"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	for _, status := range []string{"sent", "accepted", "scheduled"} {
+		w = doJSON(router, "POST", path, gin.H{"status": status})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = doJSON(router, "GET", "/estimates/1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var got struct {
+		Data struct {
+			Status      string `json:"status"`
+			Transitions []struct {
+				FromStatus string `json:"from_status"`
+				ToStatus   string `json:"to_status"`
+			} `json:"transitions"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "scheduled", got.Data.Status)
+	require.Len(t, got.Data.Transitions, 4)
+	assert.Equal(t, "", got.Data.Transitions[0].FromStatus)
+	assert.Equal(t, "draft", got.Data.Transitions[0].ToStatus)
+	assert.Equal(t, "accepted", got.Data.Transitions[3].FromStatus)
+
+	// 受注後は明細と金額を変更できないが、顧客情報などは変更できる
+	w = doJSON(router, "PUT", "/estimates/1", gin.H{"items": []gin.H{{"description": "追加", "quantity": 1, "unit_price": 500}}})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "[draft sent]")
+	w = doJSON(router, "PUT", "/estimates/1", gin.H{"title": "引越し不用品回収（日程確定）"})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestEstimateRevisions(t *testing.T) {
+	router := newEstimateTestRouter(t)
+
+	w := doJSON(router, "POST", "/estimates/", gin.H{
+		"title":    "不用品回収",
+		"customer": gin.H{"name": "佐藤", "phone": "090-0000-0000"},
+		"items": []gin.H{
+			{"item_id": "tv", "description": "テレビ", "quantity": 1, "unit_price": 3500},
+			{"item_id": "futon", "description": "布団", "quantity": 2, "unit_price": 1500},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"total_cost":7150`)
+
+	// 明細を変更すると第2版が作られる
+	w = doJSON(router, "PUT", "/estimates/1", gin.H{
+		"items": []gin.H{
+			{"item_id": "tv", "description": "テレビ", "quantity": 2, "unit_price": 3500},
+			{"item_id": "microwave", "description": "電子レンジ", "quantity": 1, "unit_price": 2000},
+		},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"revision":2`)
+
+	// 内容が同じ更新では版は増えない
+	w = doJSON(router, "PUT", "/estimates/1", gin.H{})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"revision":2`)
+
+	w = doJSON(router, "GET", "/estimates/1/revisions", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var revisions struct {
+		Data []struct {
+			Revision int `json:"revision"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisions))
+	assert.Len(t, revisions.Data, 2)
+
+	w = doJSON(router, "GET", "/estimates/1/revisions/diff?from=1&to=2", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var diff struct {
+		Data struct {
+			Items []struct {
+				Type string `json:"type"`
+				Item string `json:"item"`
+			} `json:"items"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	require.Len(t, diff.Data.Items, 3)
+	assert.Equal(t, "modified", diff.Data.Items[0].Type)
+	assert.Equal(t, "tv", diff.Data.Items[0].Item)
+	assert.Equal(t, "removed", diff.Data.Items[1].Type)
+	assert.Equal(t, "added", diff.Data.Items[2].Type)
+
+	w = doJSON(router, "GET", "/estimates/1/revisions/diff?from=1&to=9", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSearchEstimates(t *testing.T) {
+	router := newEstimateTestRouter(t)
+
+	customers := []struct {
+		name  string
+		phone string
+		price int
+	}{
+		{"山田太郎", "090-1111-2222", 1000},
+		{"山田花子", "090-3333-4444", 5000},
+		{"鈴木一郎", "080-1111-2222", 3000},
+		{"佐藤次郎", "070-5555-6666", 3000},
+		{"田中三郎", "090-7777-8888", 8000},
+	}
+	for _, customer := range customers {
+		w := doJSON(router, "POST", "/estimates/", gin.H{
+			"title":    "不用品回収",
+			"customer": gin.H{"name": customer.name, "phone": customer.phone},
+			"items": []gin.H{
+				{"description": "回収作業", "quantity": 1, "unit_price": customer.price},
+			},
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+	w := doJSON(router, "POST", "/estimates/2/transitions", gin.H{"status": "sent"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	type page struct {
+		Data struct {
+			Estimates []struct {
+				ID uint `json:"id"`
+			} `json:"estimates"`
+			Total      int    `json:"total"`
+			NextCursor string `json:"next_cursor"`
+			HasMore    bool   `json:"has_more"`
+		} `json:"data"`
+	}
+	list := func(query string) page {
+		t.Helper()
+		w := doJSON(router, "GET", "/estimates/?"+query, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var got page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		return got
+	}
+	ids := func(p page) []uint {
+		result := []uint{}
+		for _, e := range p.Data.Estimates {
+			result = append(result, e.ID)
+		}
+		return result
+	}
+
+	assert.Equal(t, []uint{2, 1}, ids(list("customer=山田")))
+	assert.Equal(t, []uint{3, 1}, ids(list("phone=11112222")))
+	assert.Equal(t, []uint{2}, ids(list("status=sent,accepted")))
+	assert.Equal(t, []uint{4, 3, 2}, ids(list("min_total=3000&max_total=6000")))
+	assert.Equal(t, 5, list("created_from=2000-01-01").Data.Total)
+	assert.Equal(t, 0, list("created_to=2000-01-01").Data.Total)
+
+	// 合計金額の昇順で2件ずつ取得（同額は id 順）
+	var got []uint
+	cursor := ""
+	for {
+		p := list("sort=total&order=asc&limit=2&cursor=" + cursor)
+		assert.Equal(t, 5, p.Data.Total)
+		got = append(got, ids(p)...)
+		if !p.Data.HasMore {
+			break
+		}
+		cursor = p.Data.NextCursor
+	}
+	assert.Equal(t, []uint{1, 3, 4, 2, 5}, got)
+
+	// 並び順の異なるカーソルや不正な条件は拒否する
+	first := list("sort=total&limit=2")
+	w = doJSON(router, "GET", "/estimates/?sort=customer_name&cursor="+first.Data.NextCursor, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = doJSON(router, "GET", "/estimates/?status=unknown", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = doJSON(router, "GET", "/estimates/?created_from=2026/01/01", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateAndRegenerateEstimatePDF(t *testing.T) {
+	t.Setenv("SAVE_LOCAL_PDF", "true")
+	t.Chdir(t.TempDir())
+	router := newEstimateTestRouter(t)
+
+	w := doJSON(router, "POST", "/estimates/pdf", gin.H{
+		"customer": gin.H{"name": "高橋", "phone": "03-1234-5678"},
+		"items": []gin.H{
+			{"id": "sofa-3p", "quantity": 1, "customPrice": 8000, "amount": 8000},
+		},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, "1", w.Header().Get("X-Estimate-ID"))
+	assert.Equal(t, "8800", w.Header().Get("X-Estimate-Total"))
+	original := w.Body.Bytes()
+
+	// PDF発行時に見積もりが保存されている
+	w = doJSON(router, "GET", "/estimates/1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total_cost":8800`)
+	assert.Contains(t, w.Body.String(), "高橋")
+	assert.Contains(t, w.Body.String(), `"description":"ソファー（3人掛け）","category":"椅子"`)
+
+	w = doJSON(router, "GET", "/estimates/1/pdf", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.NotEmpty(t, w.Body.Bytes())
+
+	w = doJSON(router, "GET", "/estimates/1/pdf?source=archive", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, original, w.Body.Bytes())
+
+	// 原本が保管されていない見積もり
+	w = doJSON(router, "POST", "/estimates/", gin.H{"title": "片付け", "total_lines": 1, "hourly_rate": 1000})
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = doJSON(router, "GET", "/estimates/2/pdf?source=archive", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = doJSON(router, "GET", "/estimates/2/pdf?source=drive", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateEstimatePDFKeepsEstimateWhenArchiveFails(t *testing.T) {
+	t.Setenv("SAVE_LOCAL_PDF", "true")
+	t.Chdir(t.TempDir())
+	// pdfsがファイルだと保存先のディレクトリを作れない
+	require.NoError(t, os.WriteFile("pdfs", nil, 0644))
+	router := newEstimateTestRouter(t)
+
+	w := doJSON(router, "POST", "/estimates/pdf", gin.H{
+		"customer": gin.H{"name": "高橋"},
+		"items":    []gin.H{{"id": "sofa-3p", "quantity": 1, "customPrice": 8000, "amount": 8000}},
+	})
+	require.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-Estimate-ID"))
+	assert.NotEmpty(t, w.Header().Get("X-Estimate-No"))
+
+	// 採番済みの見積もりは残し、再送せずに保存済みの見積もりからPDFを再発行する
+	w = doJSON(router, "GET", "/estimates/1/pdf", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+}
+
+func TestCreateEstimatePDFRecalculatesAmounts(t *testing.T) {
+	t.Setenv("SAVE_LOCAL_PDF", "true")
+	t.Chdir(t.TempDir())
+	router := newEstimateTestRouter(t)
+
+	request := gin.H{
+		"customer": gin.H{"name": "中村"},
+		"items": []gin.H{
+			{"id": "tv-stand", "quantity": 2, "amount": 4000},                                        // カタログ単価 2000
+			{"id": "other-custom", "name": "物置", "quantity": 3, "customPrice": 1200, "amount": 3000}, // 正しくは 3600
+		},
+	}
+
+	w := doJSON(router, "POST", "/estimates/pdf", request)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var rejected struct {
+		Data []models.PriceMismatch `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rejected))
+	assert.Equal(t, []models.PriceMismatch{
+		{Row: 2, ItemID: "other-custom", Quantity: 3, UnitPrice: 1200, Amount: 3000, Expected: 3600},
+	}, rejected.Data)
+
+	// 不一致の見積もりは保存されない
+	w = doJSON(router, "GET", "/estimates/", nil)
+	assert.Contains(t, w.Body.String(), `"total":0`)
+
+	w = doJSON(router, "POST", "/estimates/pdf/preview", request)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var preview struct {
+		Data models.PDFEstimatePreview `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Equal(t, 7600.0, preview.Data.SubTotal)
+	assert.Equal(t, 760.0, preview.Data.Tax)
+	assert.Equal(t, 8360.0, preview.Data.Total)
+	assert.Len(t, preview.Data.Mismatches, 1)
+	assert.Equal(t, 2000.0, preview.Data.Items[0].UnitPrice)
+	assert.Equal(t, "テレビ台", preview.Data.Items[0].Description)
+	assert.Equal(t, "タンス・収納", preview.Data.Items[0].Category)
+	// 自由入力の明細は品名で保存する
+	assert.Equal(t, "物置", preview.Data.Items[1].Description)
+	assert.Empty(t, preview.Data.Items[1].ItemID)
+
+	// 税込価格では合計は明細の合計のまま、消費税は内税として計算する
+	request["items"] = []gin.H{{"id": "tv-stand", "quantity": 2, "amount": 4000}}
+	request["pricing"] = gin.H{"price_basis": "inclusive"}
+	w = doJSON(router, "POST", "/estimates/pdf/preview", request)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Equal(t, 4000.0, preview.Data.Total)
+	assert.Equal(t, 363.0, preview.Data.Tax)
+
+	request["pricing"] = gin.H{"rounding": "bankers"}
+	w = doJSON(router, "POST", "/estimates/pdf/preview", request)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// カタログにないアイテムIDは受け付けない
+	w = doJSON(router, "POST", "/estimates/pdf", gin.H{
+		"customer": gin.H{"name": "中村"},
+		"items":    []gin.H{{"id": "piano", "quantity": 1, "customPrice": 20000, "amount": 20000}},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "1行目(piano)")
+}
+
+func TestEstimateDiscountsAndNegativeLines(t *testing.T) {
+	router := newEstimateTestRouter(t)
+
+	w := doJSON(router, "POST", "/estimates/", gin.H{
+		"title": "片付け",
+		"items": []gin.H{
+			{"description": "ソファ", "quantity": 1, "unit_price": 8000, "discount": gin.H{"type": "percent", "value": 10}},
+			{"description": "冷蔵庫買取", "quantity": 1, "unit_price": -3000},
+		},
+		"discount": gin.H{"type": "fixed", "value": 200, "label": "リピート割引"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Estimate `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, 7200.0, created.Data.Items[0].Amount)
+	assert.Equal(t, -3000.0, created.Data.Items[1].Amount)
+	assert.Equal(t, 200.0, created.Data.DiscountAmount)
+	assert.Equal(t, 4000.0, created.Data.SubTotal)
+	assert.Equal(t, 400.0, created.Data.Tax)
+	assert.Equal(t, 4400.0, created.Data.TotalCost)
+
+	// 値引きを保存して読み直しても同じ金額になる
+	w = doJSON(router, "GET", "/estimates/1", nil)
+	assert.Contains(t, w.Body.String(), `"discount_amount":200`)
+	assert.Contains(t, w.Body.String(), `"total_cost":4400`)
+
+	w = doJSON(router, "GET", "/estimates/1/pdf", nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = doJSON(router, "PUT", "/estimates/1", gin.H{"discount": gin.H{"type": "percent", "value": 150}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestEstimatePricingRules(t *testing.T) {
+	router := newEstimateTestRouter(t)
+
+	w := doJSON(router, "POST", "/estimates/", gin.H{
+		"title":      "2階からの搬出",
+		"items":      []gin.H{{"item_id": "sofa-3p", "description": "ソファ", "quantity": 1, "unit_price": 8000}},
+		"conditions": gin.H{"floor": 3, "parking_distance": 25, "same_day": true},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Estimate `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	// 階段2階分 2,000円、横持ち1単位 1,000円、当日対応 20% 1,600円
+	require.Len(t, created.Data.Items, 4)
+	assert.Equal(t, "rule:floor", created.Data.Items[1].ItemID)
+	assert.Equal(t, "横持ち作業費", created.Data.Items[2].Description)
+	assert.Equal(t, 1600.0, created.Data.Items[3].Amount)
+	assert.Equal(t, 12600.0, created.Data.SubTotal)
+
+	// 最低料金を有効にすると、次に明細か作業条件を更新したときに差額が加算される
+	w = doJSON(router, "GET", "/pricing-rules", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var rules struct {
+		Data []models.PricingRule `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rules))
+	require.Len(t, rules.Data, 6)
+	minimum := rules.Data[5]
+	assert.Equal(t, models.PricingRuleMinimumCharge, minimum.Kind)
+	assert.False(t, minimum.Enabled)
+
+	w = doJSON(router, "PUT", "/pricing-rules/"+strconv.FormatUint(uint64(minimum.ID), 10), gin.H{
+		"name": minimum.Name, "unit": minimum.Unit, "amount": 15000, "enabled": true, "sort_order": minimum.SortOrder,
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = doJSON(router, "PUT", "/estimates/1", gin.H{"conditions": gin.H{"floor": 1}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var updated struct {
+		Data models.Estimate `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	require.Len(t, updated.Data.Items, 2)
+	assert.Equal(t, "rule:minimum_charge", updated.Data.Items[1].ItemID)
+	assert.Equal(t, 7000.0, updated.Data.Items[1].Amount)
+	assert.Equal(t, 15000.0, updated.Data.SubTotal)
+
+	w = doJSON(router, "PUT", "/pricing-rules/99", gin.H{"name": "不明"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPlanEstimateLoad(t *testing.T) {
+	router := newEstimateTestRouter(t)
+
+	request := gin.H{
+		"customer": gin.H{"name": "中村"},
+		"items": []gin.H{
+			{"id": "refrigerator", "quantity": 2, "amount": 10000, "sizeClass": "large"},
+			{"id": "double-bed", "quantity": 1, "amount": 4500},
+			{"id": "other-custom", "name": "物置", "quantity": 1, "customPrice": 5000, "amount": 5000},
+		},
+	}
+	w := doJSON(router, "POST", "/estimates/pdf/load-plan", request)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var plan struct {
+		Data models.LoadPlan `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &plan))
+	// 容積 4.2m³ は軽トラックに載らないため2tトラック1台
+	assert.InDelta(t, 4.2, plan.Data.TotalVolume, 1e-9)
+	assert.Equal(t, 200.0, plan.Data.TotalWeight)
+	require.NotNil(t, plan.Data.Vehicle)
+	assert.Equal(t, "2t", plan.Data.Vehicle.Code)
+	assert.Equal(t, 1, plan.Data.Trips)
+	assert.Equal(t, []int{3}, plan.Data.UnmeasuredRows)
+
+	// 収集運搬費を明細に追加する
+	request["addTransportFee"] = true
+	w = doJSON(router, "POST", "/estimates/pdf/preview", request)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var preview struct {
+		Data models.PDFEstimatePreview `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	require.Len(t, preview.Data.Items, 5)
+	assert.Equal(t, "収集運搬費（2tトラック）", preview.Data.Items[4].Description)
+	assert.Equal(t, 30000.0, preview.Data.Items[4].Amount)
+	// 冷蔵庫のリサイクル料金 4,730円 × 2台を含む
+	assert.Equal(t, 58960.0, preview.Data.SubTotal)
+}
+
+func TestEstimateRecyclingLawAppliances(t *testing.T) {
+	t.Setenv("SAVE_LOCAL_PDF", "true")
+	t.Chdir(t.TempDir())
+	router := newEstimateTestRouter(t)
+
+	request := gin.H{
+		"customer": gin.H{"name": "中村"},
+		"items": []gin.H{
+			{"id": "tv", "quantity": 2, "amount": 7000, "manufacturer": "パナソニック", "sizeClass": "large"},
+			{"id": "air-conditioner", "quantity": 1, "amount": 3000},
+		},
+	}
+	w := doJSON(router, "POST", "/estimates/pdf", request)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = doJSON(router, "GET", "/estimates/1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var saved struct {
+		Data models.Estimate `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &saved))
+
+	// 収集運搬料金（課税）とリサイクル料金（不課税・標準料金）が別の明細になる
+	items := saved.Data.Items
+	require.Len(t, items, 4)
+	assert.Equal(t, "テレビ（収集運搬料金）", items[0].Description)
+	require.NotNil(t, items[0].Recycling)
+	assert.Equal(t, "パナソニック", items[0].Recycling.Manufacturer)
+	assert.Equal(t, "家電リサイクル料金（テレビ）", items[1].Description)
+	assert.Equal(t, "パナソニック・16型以上", items[1].Specification)
+	assert.Equal(t, models.TaxCategoryNonTaxable, items[1].TaxCategory)
+	assert.Equal(t, 7400.0, items[1].Amount)
+	assert.Equal(t, 990.0, items[3].Amount)
+	assert.Equal(t, 1000.0, saved.Data.Tax)
+	assert.Equal(t, 19390.0, saved.Data.TotalCost)
+
+	// リサイクル券番号は明細ごとに記録し、版は変わらない
+	w = doJSON(router, "PUT", "/estimates/1/recycling-tickets", gin.H{
+		"items": []gin.H{{"line": 1, "ticket_numbers": []string{"1234567890123", "1234567890124"}}},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &saved))
+	assert.Equal(t, []string{"1234567890123", "1234567890124"}, saved.Data.Items[0].Recycling.TicketNumbers)
+	assert.Equal(t, 1, saved.Data.Revision)
+
+	w = doJSON(router, "PUT", "/estimates/1/recycling-tickets", gin.H{
+		"items": []gin.H{{"line": 2, "ticket_numbers": []string{"1234567890125"}}},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = doJSON(router, "PUT", "/estimates/1/recycling-tickets", gin.H{
+		"items": []gin.H{{"line": 3, "ticket_numbers": []string{"1234567890125", "1234567890126"}}},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// テレビのサイズ区分は必須
+	request["items"] = []gin.H{{"id": "tv", "quantity": 1, "amount": 3500}}
+	w = doJSON(router, "POST", "/estimates/pdf/preview", request)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "サイズ区分")
+}
@@ -0,0 +1,415 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+	"line-estimate-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// defaultManifestAlertDays is how many days ahead of a return deadline alerts are raised
+const defaultManifestAlertDays = 7
+
+// manifestExportHeader is the header row of the internal manifest ledger CSV (社内台帳用).
+// The columns are our own layout, not the JWNET bulk registration format.
+var manifestExportHeader = []string{
+	"交付年月日", "交付番号", "排出事業者名称", "排出事業場名称", "排出事業場所在地",
+	"産業廃棄物の種類", "数量", "単位", "荷姿", "特別管理",
+	"収集運搬業者", "処分業者", "処分方法",
+	"運搬終了日", "処分終了日", "最終処分終了日", "備考",
+}
+
+// ManifestHandler serves the industrial waste manifest endpoints
+type ManifestHandler struct {
+	estimates    repository.EstimateRepository
+	instructions repository.InstructionRepository
+	manifests    repository.ManifestRepository
+}
+
+// NewManifestHandler creates a new ManifestHandler
+func NewManifestHandler(estimates repository.EstimateRepository, instructions repository.InstructionRepository, manifests repository.ManifestRepository) *ManifestHandler {
+	return &ManifestHandler{estimates: estimates, instructions: instructions, manifests: manifests}
+}
+
+// CreateInstructionManifest godoc
+// @Summary 作業指示書のマニフェストを登録
+// @Description 作業指示書に対して交付した産業廃棄物管理票（マニフェスト）を登録します。排出事業者・排出事業場を省略すると見積もりの顧客情報を使います
+// @Tags Manifests
+// @Accept json
+// @Produce json
+// @Param id path int true "作業指示書ID"
+// @Param manifest body models.CreateManifestRequest true "マニフェストの記載事項"
+// @Success 201 {object} utils.Response{data=models.Manifest}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/instructions/{id}/manifests [post]
+func (h *ManifestHandler) CreateInstructionManifest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid instruction ID")
+		return
+	}
+
+	var req models.CreateManifestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	instruction, err := h.instructions.Get(c.Request.Context(), uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Instruction not found")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to get instruction %d: %v", id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get instruction")
+		return
+	}
+	estimate, err := h.estimates.Get(c.Request.Context(), instruction.EstimateID)
+	if err != nil {
+		utils.Logger.Printf("Failed to get estimate %d: %v", instruction.EstimateID, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get estimate")
+		return
+	}
+
+	manifest := &models.Manifest{
+		ManifestNo:     strings.TrimSpace(req.ManifestNo),
+		InstructionID:  instruction.ID,
+		EstimateID:     instruction.EstimateID,
+		IssueDate:      req.IssueDate,
+		Discharger:     req.Discharger,
+		SiteName:       req.SiteName,
+		SiteAddress:    req.SiteAddress,
+		WasteType:      req.WasteType,
+		Quantity:       req.Quantity,
+		Unit:           req.Unit,
+		PackingForm:    req.PackingForm,
+		Transporter:    req.Transporter,
+		Disposer:       req.Disposer,
+		DisposalMethod: req.DisposalMethod,
+		SpecialControl: req.SpecialControl,
+		Note:           req.Note,
+	}
+	if manifest.IssueDate == "" {
+		manifest.IssueDate = time.Now().In(jst).Format("2006-01-02")
+	}
+	if manifest.Discharger == "" {
+		manifest.Discharger = estimate.Customer.Name
+	}
+	if manifest.SiteName == "" {
+		manifest.SiteName = manifest.Discharger
+	}
+	if manifest.SiteAddress == "" {
+		manifest.SiteAddress = estimate.Customer.Address
+	}
+
+	err = h.manifests.Create(c.Request.Context(), manifest)
+	if errors.Is(err, repository.ErrDuplicate) {
+		utils.SendErrorResponse(c, http.StatusConflict, "Manifest number already registered")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to create manifest for instruction %d: %v", id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create manifest")
+		return
+	}
+	if err := manifest.Evaluate(time.Now().In(jst)); err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Data:    manifest,
+	})
+}
+
+// GetManifests godoc
+// @Summary マニフェスト一覧を取得
+// @Description 交付したマニフェストを返送状況と返送期限つきで取得します
+// @Tags Manifests
+// @Produce json
+// @Param status query string false "進捗" Enums(issued, transported, disposed, completed)
+// @Param estimate_id query int false "見積もりID"
+// @Param issued_from query string false "交付日の開始 (YYYY-MM-DD)"
+// @Param issued_to query string false "交付日の終了 (YYYY-MM-DD, 当日を含む)"
+// @Param overdue query bool false "返送期限を過ぎた写しがあるものだけ"
+// @Success 200 {object} utils.Response{data=[]models.Manifest}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/manifests [get]
+func (h *ManifestHandler) GetManifests(c *gin.Context) {
+	manifests, ok := h.listManifests(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, manifests)
+}
+
+// GetManifestAlerts godoc
+// @Summary マニフェストの返送期限アラートを取得
+// @Description 返送期限を過ぎた写しと、期限が近い未返送の写しを期限の早い順に取得します。期限を過ぎた場合は報告期限までに都道府県知事へ報告が必要です
+// @Tags Manifests
+// @Produce json
+// @Param within query int false "期限までの日数 (既定7)"
+// @Success 200 {object} utils.Response{data=[]models.ManifestAlert}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/manifests/alerts [get]
+func (h *ManifestHandler) GetManifestAlerts(c *gin.Context) {
+	within := defaultManifestAlertDays
+	if value := c.Query("within"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			utils.SendErrorResponse(c, http.StatusBadRequest, "within must be a non-negative number of days")
+			return
+		}
+		within = days
+	}
+
+	manifests, err := h.manifests.List(c.Request.Context(), models.ManifestFilter{})
+	if err != nil {
+		utils.Logger.Printf("Failed to list manifests: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get manifests")
+		return
+	}
+
+	today := time.Now().In(jst)
+	alerts := []models.ManifestAlert{}
+	for i := range manifests {
+		if err := manifests[i].Evaluate(today); err != nil {
+			utils.Logger.Printf("Skipping manifest %d: %v", manifests[i].ID, err)
+			continue
+		}
+		alerts = append(alerts, manifests[i].Alerts(today, within)...)
+	}
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].DueDate < alerts[j].DueDate
+	})
+
+	utils.SuccessResponse(c, alerts)
+}
+
+// GetManifest godoc
+// @Summary マニフェストを取得
+// @Description マニフェストの記載事項とB2・D・E票の返送状況を取得します
+// @Tags Manifests
+// @Produce json
+// @Param id path int true "マニフェストID"
+// @Success 200 {object} utils.Response{data=models.Manifest}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/manifests/{id} [get]
+func (h *ManifestHandler) GetManifest(c *gin.Context) {
+	manifest, ok := h.loadManifest(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, manifest)
+}
+
+// RecordManifestReturn godoc
+// @Summary マニフェストの写しの返送を記録
+// @Description 収集運搬業者・処分業者から返送されたB2・D・E票の終了年月日と受領日を記録します
+// @Tags Manifests
+// @Accept json
+// @Produce json
+// @Param id path int true "マニフェストID"
+// @Param return body models.RecordManifestReturnRequest true "返送された写し"
+// @Success 200 {object} utils.Response{data=models.Manifest}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/manifests/{id}/returns [post]
+func (h *ManifestHandler) RecordManifestReturn(c *gin.Context) {
+	var req models.RecordManifestReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	manifest, ok := h.loadManifest(c)
+	if !ok {
+		return
+	}
+	if req.ReturnedOn == "" {
+		req.ReturnedOn = time.Now().In(jst).Format("2006-01-02")
+	}
+	if err := req.Validate(manifest.IssueDate); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.manifests.RecordReturn(c.Request.Context(), manifest.ID, req.Copy, req.CompletedOn, req.ReturnedOn); err != nil {
+		utils.Logger.Printf("Failed to record return of manifest %d: %v", manifest.ID, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to record manifest return")
+		return
+	}
+
+	manifest, ok = h.loadManifest(c)
+	if !ok {
+		return
+	}
+	utils.SuccessResponse(c, manifest)
+}
+
+// ExportManifests godoc
+// @Summary マニフェストをCSVで出力
+// @Description 条件に一致するマニフェストを社内台帳用のShift_JISのCSVで出力します。列構成は独自のもので、電子マニフェスト（JWNET）の一括登録には使えません
+// @Tags Manifests
+// @Produce text/csv
+// @Param status query string false "進捗" Enums(issued, transported, disposed, completed)
+// @Param estimate_id query int false "見積もりID"
+// @Param issued_from query string false "交付日の開始 (YYYY-MM-DD)"
+// @Param issued_to query string false "交付日の終了 (YYYY-MM-DD, 当日を含む)"
+// @Param overdue query bool false "返送期限を過ぎた写しがあるものだけ"
+// @Success 200 {file} binary
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/manifests/export [get]
+func (h *ManifestHandler) ExportManifests(c *gin.Context) {
+	manifests, ok := h.listManifests(c)
+	if !ok {
+		return
+	}
+
+	filename := fmt.Sprintf("manifests_%s.csv", time.Now().In(jst).Format("20060102_150405"))
+	c.Header("Content-Type", "text/csv; charset=Shift_JIS")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Status(http.StatusOK)
+
+	// 表外漢字など Shift_JIS にない文字は「?」に置き換える
+	encoder := encoding.ReplaceUnsupported(japanese.ShiftJIS.NewEncoder())
+	sjis := transform.NewWriter(c.Writer, encoder)
+	writer := csv.NewWriter(sjis)
+	writer.UseCRLF = true
+
+	rows := [][]string{manifestExportHeader}
+	for _, manifest := range manifests {
+		rows = append(rows, manifestExportRow(manifest))
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		utils.Logger.Printf("Failed to write manifest CSV: %v", err)
+		return
+	}
+	if err := sjis.Close(); err != nil {
+		utils.Logger.Printf("Failed to write manifest CSV: %v", err)
+	}
+}
+
+// listManifests lists the manifests matching the query parameters,
+// writing the appropriate error response when they cannot be loaded
+func (h *ManifestHandler) listManifests(c *gin.Context) ([]models.Manifest, bool) {
+	var query models.ManifestListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	filter, err := query.ToFilter()
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	manifests, err := h.manifests.List(c.Request.Context(), filter)
+	if err != nil {
+		utils.Logger.Printf("Failed to list manifests: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get manifests")
+		return nil, false
+	}
+
+	today := time.Now().In(jst)
+	result := []models.Manifest{}
+	for _, manifest := range manifests {
+		if err := manifest.Evaluate(today); err != nil {
+			utils.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return nil, false
+		}
+		if query.Overdue && !manifest.Overdue {
+			continue
+		}
+		result = append(result, manifest)
+	}
+	return result, true
+}
+
+// loadManifest fetches the manifest addressed by the :id path parameter and evaluates
+// its deadlines, writing the appropriate error response when it cannot be loaded
+func (h *ManifestHandler) loadManifest(c *gin.Context) (*models.Manifest, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid manifest ID")
+		return nil, false
+	}
+
+	manifest, err := h.manifests.Get(c.Request.Context(), uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Manifest not found")
+		return nil, false
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to get manifest %d: %v", id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get manifest")
+		return nil, false
+	}
+	if err := manifest.Evaluate(time.Now().In(jst)); err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return manifest, true
+}
+
+// manifestExportRow converts a manifest into a row of the ledger CSV. Dates are written as YYYY/MM/DD.
+func manifestExportRow(manifest models.Manifest) []string {
+	specialControl := ""
+	if manifest.SpecialControl {
+		specialControl = "1"
+	}
+	completedOn := map[models.ManifestCopy]string{}
+	for _, stage := range manifest.Stages {
+		completedOn[stage.Copy] = strings.ReplaceAll(stage.CompletedOn, "-", "/")
+	}
+
+	return []string{
+		strings.ReplaceAll(manifest.IssueDate, "-", "/"),
+		manifest.ManifestNo,
+		manifest.Discharger,
+		manifest.SiteName,
+		manifest.SiteAddress,
+		manifest.WasteType,
+		strconv.FormatFloat(manifest.Quantity, 'f', -1, 64),
+		manifest.Unit,
+		manifest.PackingForm,
+		specialControl,
+		manifest.Transporter,
+		manifest.Disposer,
+		manifest.DisposalMethod,
+		completedOn[models.ManifestCopyB2],
+		completedOn[models.ManifestCopyD],
+		completedOn[models.ManifestCopyE],
+		manifest.Note,
+	}
+}
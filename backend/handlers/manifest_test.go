@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/japanese"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+)
+
+func TestManifestTracking(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	numberer, err := repository.NewDocumentNumberer(db, nil)
+	require.NoError(t, err)
+	estimates := repository.NewEstimateRepository(db, numberer)
+	instructions := repository.NewInstructionRepository(db, numberer)
+
+	estimate := &models.Estimate{
+		Title:    "事務所移転に伴う廃棄物回収",
+		Customer: models.EstimateCustomer{Name: "株式会社サンプル", Address: "東京都新宿区西新宿1-2-3"},
+		Status:   models.EstimateStatusAccepted,
+	}
+	require.NoError(t, estimates.Create(ctx, estimate))
+	instruction := &models.Instruction{EstimateID: estimate.ID}
+	require.NoError(t, instructions.Create(ctx, instruction))
+
+	router := gin.New()
+	mh := NewManifestHandler(estimates, instructions, repository.NewManifestRepository(db))
+	router.POST("/instructions/:id/manifests", mh.CreateInstructionManifest)
+	router.GET("/manifests", mh.GetManifests)
+	router.GET("/manifests/alerts", mh.GetManifestAlerts)
+	router.GET("/manifests/export", mh.ExportManifests)
+	router.GET("/manifests/:id", mh.GetManifest)
+	router.POST("/manifests/:id/returns", mh.RecordManifestReturn)
+
+	// 交付から85日経過したマニフェスト（B2・D票の期限まで残り5日）
+	today := time.Now().In(jst)
+	issueDate := today.AddDate(0, 0, -85).Format("2006-01-02")
+	w := doJSON(router, "POST", "/instructions/1/manifests", gin.H{
+		"manifest_no": "12345678901",
+		"issue_date":  issueDate,
+		"waste_type":  "廃プラスチック類",
+		"quantity":    1.5,
+		"unit":        "m3",
+		"transporter": "株式会社マルキョウ",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created struct {
+		Data models.Manifest `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "株式会社サンプル", created.Data.Discharger)
+	assert.Equal(t, "東京都新宿区西新宿1-2-3", created.Data.SiteAddress)
+	assert.Equal(t, estimate.ID, created.Data.EstimateID)
+	assert.Equal(t, models.ManifestStatusIssued, created.Data.Status)
+	require.Len(t, created.Data.Stages, 3)
+
+	// 交付番号は重複登録できない
+	w = doJSON(router, "POST", "/instructions/1/manifests", gin.H{"manifest_no": "12345678901", "waste_type": "金属くず"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = doJSON(router, "POST", "/instructions/99/manifests", gin.H{"manifest_no": "99999999999", "waste_type": "金属くず"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var alerts struct {
+		Data []models.ManifestAlert `json:"data"`
+	}
+	w = doJSON(router, "GET", "/manifests/alerts", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &alerts))
+	require.Len(t, alerts.Data, 2)
+	assert.Equal(t, models.ManifestCopyB2, alerts.Data[0].Copy)
+	assert.Equal(t, 5, alerts.Data[0].DaysLeft)
+	assert.False(t, alerts.Data[0].Overdue)
+
+	// 終了年月日が交付日より前の写しは記録できない
+	w = doJSON(router, "POST", "/manifests/1/returns", gin.H{"copy": "B2", "completed_on": "2000-01-01"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doJSON(router, "POST", "/manifests/1/returns", gin.H{"copy": "B2", "completed_on": issueDate})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var returned struct {
+		Data models.Manifest `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &returned))
+	assert.Equal(t, models.ManifestStatusTransported, returned.Data.Status)
+	assert.Equal(t, today.Format("2006-01-02"), returned.Data.Stages[0].ReturnedOn)
+
+	w = doJSON(router, "GET", "/manifests?status=transported", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "12345678901")
+	w = doJSON(router, "GET", "/manifests?overdue=true", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "12345678901")
+
+	// 社内台帳用のCSVはExcelで開けるShift_JIS・CRLF
+	w = doJSON(router, "GET", "/manifests/export", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	decoded, err := japanese.ShiftJIS.NewDecoder().Bytes(w.Body.Bytes())
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(decoded), "\r\n"), "\r\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "交付年月日,交付番号,排出事業者名称"))
+	assert.Contains(t, lines[1], strings.ReplaceAll(issueDate, "-", "/")+",12345678901,株式会社サンプル")
+	assert.Contains(t, lines[1], "廃プラスチック類,1.5,m3")
+}
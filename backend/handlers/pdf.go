@@ -3,15 +3,19 @@ package handlers
 import (
 	"bytes"
 	_ "embed"
+	"errors"
 	"fmt"
 	"math"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/signintech/gopdf"
 	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
 	"line-estimate-backend/services"
 	"line-estimate-backend/utils"
 )
@@ -29,6 +33,97 @@ func loadJapaneseFont(pdf *gopdf.GoPdf) error {
 	return nil
 }
 
+// jst is the time zone used for dates printed on documents
+var jst = time.FixedZone("JST", 9*60*60)
+
+// estimateTitle is the title printed on estimate PDFs
+const estimateTitle = "廃棄物処理に関する見積書"
+
+// defaultEstimateRemarks are printed in the remarks box of estimate PDFs
+var defaultEstimateRemarks = []string{
+	"※お見積もりの有効期限は発行日より1ヶ月となります。",
+	"※実際の廃棄物量により金額が変更となる場合がございます。",
+	"※お支払い条件：作業完了後、請求書発行日より30日以内",
+}
+
+// buildPDFEstimate converts a stored estimate snapshot into PDF data
+func (h *EstimateHandler) buildPDFEstimate(estimateNo string, issueDate time.Time, revision int, snapshot models.EstimateSnapshot) *models.PDFEstimate {
+	estimate := &models.PDFEstimate{
+		EstimateNo: estimateNo,
+		Revision:   revision,
+		IssueDate:  issueDate.In(jst),
+		Customer: models.PDFCustomerInfo{
+			CompanyName: snapshot.Customer.Name,
+			PostalCode:  "000-0000", // デフォルト値
+			Address:     snapshot.Customer.Address,
+			Tel:         snapshot.Customer.Phone,
+		},
+		Recipient: snapshot.Customer.Name + " 様",
+		Title:     estimateTitle,
+		Items:     pdfLineItems(snapshot.Items, snapshot.Discount, snapshot.DiscountAmount),
+		SubTotal:  snapshot.SubTotal,
+		TaxRate:   snapshot.TaxRate,
+		Tax:       snapshot.Tax,
+		Total:     snapshot.Total,
+		Remarks:   defaultEstimateRemarks,
+		Pricing:   snapshot.Pricing.OrDefault(),
+		Issuer:    models.PDFCompanyInfo{RegistrationNumber: h.registrationNumber},
+	}
+	lines := models.WithDiscountLines(snapshot.Items, snapshot.Discount, snapshot.DiscountAmount)
+	_, estimate.TaxBreakdown, _ = models.CalculateTax(lines, snapshot.TaxRate, snapshot.Pricing)
+
+	return estimate
+}
+
+// pdfLineItems converts stored lines into PDF lines. A document discount is printed as
+// a negative line after the items.
+func pdfLineItems(items []models.EstimateItem, discount models.Discount, discountAmount float64) []models.PDFLineItem {
+	lines := []models.PDFLineItem{}
+	for _, item := range items {
+		lines = append(lines, models.PDFLineItem{
+			Description:    item.Description,
+			Category:       item.Category,
+			Specification:  item.Specification,
+			Quantity:       item.Quantity,
+			Unit:           item.Unit,
+			UnitPrice:      item.UnitPrice,
+			DiscountAmount: item.Quantity*item.UnitPrice - item.Amount,
+			Amount:         item.Amount,
+			TaxCategory:    item.TaxCategory,
+		})
+	}
+
+	if discountAmount > 0 {
+		specification := ""
+		if discount.Type == models.DiscountTypePercent {
+			specification = fmt.Sprintf("%g%%", discount.Value)
+		}
+		lines = append(lines, models.PDFLineItem{
+			Description:   discount.LabelOrDefault(),
+			Specification: specification,
+			Quantity:      1,
+			Unit:          "式",
+			UnitPrice:     -discountAmount,
+			Amount:        -discountAmount,
+		})
+	}
+	return lines
+}
+
+// writePDFResponse renders the PDF and sends it as an attachment without saving it
+func writePDFResponse(c *gin.Context, pdf *gopdf.GoPdf, filename string) {
+	var buf bytes.Buffer
+	if err := pdf.Write(&buf); err != nil {
+		utils.SendErrorResponse(c, 500, "PDFの書き込みに失敗しました: "+err.Error())
+		return
+	}
+
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Content-Length", fmt.Sprintf("%d", buf.Len()))
+	c.Data(200, "application/pdf", buf.Bytes())
+}
+
 // GenerateEstimatePDF generates an estimate PDF from the provided data
 // This is an internal function, not exposed as an API endpoint
 func GenerateEstimatePDF(estimate *models.PDFEstimate) (*gopdf.GoPdf, error) {
@@ -63,7 +158,7 @@ func GenerateEstimatePDF(estimate *models.PDFEstimate) (*gopdf.GoPdf, error) {
 	}
 
 	// Draw total amount with stamp box
-	if err := helper.DrawTotalAmount(estimate.Total); err != nil {
+	if err := helper.DrawTotalAmount(estimate); err != nil {
 		return nil, err
 	}
 
@@ -73,9 +168,10 @@ func GenerateEstimatePDF(estimate *models.PDFEstimate) (*gopdf.GoPdf, error) {
 		return nil, err
 	}
 
-	// Draw remarks
-	if len(estimate.Remarks) > 0 {
-		if err := helper.DrawRemarks(estimate.Remarks, tableEndY+20); err != nil {
+	// Draw remarks (軽減税率の注記を先頭に付ける)
+	remarks := append(utils.TaxRemarks(estimate.Items), estimate.Remarks...)
+	if len(remarks) > 0 {
+		if err := helper.DrawRemarks(remarks, tableEndY+20); err != nil {
 			return nil, err
 		}
 	}
@@ -85,68 +181,74 @@ func GenerateEstimatePDF(estimate *models.PDFEstimate) (*gopdf.GoPdf, error) {
 
 // CreateEstimatePDF godoc
 // @Summary 見積もりPDFを生成
-// @Description 見積もり情報からPDFを生成します
+// @Description 見積もり情報を保存し、保存した見積もりからPDFを生成します。PDFはローカルまたはGoogle Driveに保管されます。PDFを生成・保管できなかった場合も見積もりは保存済みのままなので、再送せずに GET /api/v1/estimates/{id}/pdf で再発行してください
 // @Tags Estimates
 // @Accept json
 // @Produce application/pdf
 // @Param estimate body models.PDFEstimateRequest true "見積もり情報"
 // @Success 200 {file} binary
+// @Header 200 {string} X-Estimate-ID "保存した見積もりのID"
+// @Header 200 {string} X-Estimate-No "見積書番号"
+// @Header 200 {number} X-Estimate-Subtotal "サーバーで計算した小計"
+// @Header 200 {number} X-Estimate-Tax "サーバーで計算した消費税"
+// @Header 200 {number} X-Estimate-Total "サーバーで計算した合計金額"
 // @Failure 400 {object} utils.ErrorResponse
-// @Failure 500 {object} utils.ErrorResponse
+// @Failure 422 {object} utils.Response{data=[]models.PriceMismatch} "金額が数量×単価と一致しない行がある"
+// @Failure 500 {object} utils.Response{data=models.Estimate} "見積もりは保存済みで、PDFの生成・保管に失敗した"
+// @Failure 503 {object} utils.ErrorResponse
 // @Router /api/v1/estimates/pdf [post]
-func CreateEstimatePDF(c *gin.Context) {
+func (h *EstimateHandler) CreateEstimatePDF(c *gin.Context) {
 	var request models.PDFEstimateRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		utils.SendErrorResponse(c, 400, "無効なリクエストデータ: "+err.Error())
 		return
 	}
 
-	// Convert request to PDFEstimate format
-	now := time.Now().In(time.FixedZone("JST", 9*60*60))
-	estimate := models.PDFEstimate{
-		EstimateNo: fmt.Sprintf("EST-%s-%03d", now.Format("20060102"), 1),
-		IssueDate:  now,
-		Customer: models.PDFCustomerInfo{
-			CompanyName: request.Customer.Name,
-			PostalCode:  "000-0000", // デフォルト値
-			Address:     request.Customer.Address,
-			Tel:         request.Customer.Phone,
-			Fax:         "", // デフォルト値
-		},
-		Recipient: request.Customer.Name + " 様",
-		Title:     "廃棄物処理に関する見積書",
-		Items:     []models.PDFLineItem{},
-		Remarks: []string{
-			"※お見積もりの有効期限は発行日より1ヶ月となります。",
-			"※実際の廃棄物量により金額が変更となる場合がございます。",
-			"※お支払い条件：作業完了後、請求書発行日より30日以内",
-		},
+	// 金額はブラウザの値を信用せず、数量と単価から計算し直す
+	catalog, _, err := h.catalog.loadCategories(c.Request.Context())
+	if err != nil {
+		utils.Logger.Printf("Failed to load catalog: %v", err)
+		utils.SendErrorResponse(c, 503, "カタログを取得できませんでした。しばらくしてから再度お試しください")
+		return
 	}
-
-	// Convert items
-	var subTotal float64
-	for _, item := range request.Items {
-		pdfItem := models.PDFLineItem{
-			Description:   item.ID,
-			Specification: item.Specification,
-			Quantity:      item.Quantity,
-			UnitPrice:     item.CustomPrice,
-			Amount:        item.Amount,
+	items := catalogItemsByID(catalog)
+	estimate, mismatches, err := estimateFromPDFRequest(&request, items)
+	if err != nil {
+		utils.SendErrorResponse(c, 400, err.Error())
+		return
+	}
+	if len(mismatches) > 0 {
+		utils.SendErrorResponseWithData(c, 422, fmt.Sprintf("%d行の金額が数量×単価と一致しません", len(mismatches)), mismatches)
+		return
+	}
+	if !h.applyRecyclingFees(c, estimate) {
+		return
+	}
+	if request.AddTransportFee {
+		if err := h.addTransportFee(c.Request.Context(), estimate, items); err != nil {
+			utils.Logger.Printf("Failed to add transport fee: %v", err)
+			utils.SendErrorResponse(c, 500, "収集運搬費の計算に失敗しました")
+			return
 		}
-		estimate.Items = append(estimate.Items, pdfItem)
-		subTotal += item.Amount
+	}
+	if err := h.applyPricingRules(c.Request.Context(), estimate); err != nil {
+		utils.Logger.Printf("Failed to apply pricing rules: %v", err)
+		utils.SendErrorResponse(c, 500, "割増料金の計算に失敗しました")
+		return
 	}
 
-	// Calculate totals
-	estimate.SubTotal = subTotal
-	estimate.TaxRate = 0.10
-	estimate.Tax = math.Floor(subTotal * estimate.TaxRate)
-	estimate.Total = subTotal + estimate.Tax
+	// Save the estimate first so that the PDF always reflects a stored record
+	estimate.UserID = uint(c.GetFloat64("userID"))
+	if err := h.repo.Create(c.Request.Context(), estimate); err != nil {
+		utils.Logger.Printf("Failed to save estimate for PDF: %v", err)
+		utils.SendErrorResponse(c, 500, "見積もりの保存に失敗しました: "+err.Error())
+		return
+	}
 
 	// Generate PDF
-	pdf, err := GenerateEstimatePDF(&estimate)
+	pdf, err := GenerateEstimatePDF(h.buildPDFEstimate(estimate.EstimateNo, estimate.CreatedAt, estimate.Revision, estimate.Snapshot()))
 	if err != nil {
-		utils.SendErrorResponse(c, 500, "PDF生成に失敗しました: "+err.Error())
+		sendSavedEstimateError(c, estimate, "PDF生成に失敗しました: "+err.Error())
 		return
 	}
 
@@ -166,45 +268,343 @@ func CreateEstimatePDF(c *gin.Context) {
 	// PDFをバイト配列に変換
 	var buf bytes.Buffer
 	if err := pdf.Write(&buf); err != nil {
-		utils.SendErrorResponse(c, 500, "PDFの書き込みに失敗しました: "+err.Error())
+		sendSavedEstimateError(c, estimate, "PDFの書き込みに失敗しました: "+err.Error())
 		return
 	}
 
-	// Check if local save mode
-	saveLocal := os.Getenv("SAVE_LOCAL_PDF")
+	storage, location, err := archivePDF(filename, buf.Bytes())
+	if err != nil {
+		sendSavedEstimateError(c, estimate, err.Error())
+		return
+	}
+	document := &models.EstimateDocument{
+		EstimateID: estimate.ID,
+		Revision:   estimate.Revision,
+		FileName:   filename,
+		Storage:    storage,
+		Location:   location,
+	}
+	if err := h.repo.AddDocument(c.Request.Context(), document); err != nil {
+		// PDF自体は保管済みなので、記録に失敗してもレスポンスは返す
+		utils.Logger.Printf("Failed to record document of estimate %d: %v", estimate.ID, err)
+	}
 
-	if saveLocal == "true" {
+	// 保存処理の後、常にPDFファイルを直接レスポンスとして返す
+	c.Header("X-Estimate-ID", fmt.Sprintf("%d", estimate.ID))
+	c.Header("X-Estimate-No", estimate.EstimateNo)
+	c.Header("X-Estimate-Subtotal", strconv.FormatFloat(estimate.SubTotal, 'f', -1, 64))
+	c.Header("X-Estimate-Tax", strconv.FormatFloat(estimate.Tax, 'f', -1, 64))
+	c.Header("X-Estimate-Total", strconv.FormatFloat(estimate.TotalCost, 'f', -1, 64))
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Content-Length", fmt.Sprintf("%d", buf.Len()))
+	c.Data(200, "application/pdf", buf.Bytes())
+}
+
+// PreviewEstimatePDF godoc
+// @Summary 見積もりPDFの金額を確認
+// @Description PDFリクエストの明細金額をサーバーで計算し直し、合計と一致しない行を返します。見積もりは保存しません
+// @Tags Estimates
+// @Accept json
+// @Produce json
+// @Param estimate body models.PDFEstimateRequest true "見積もり情報"
+// @Success 200 {object} utils.Response{data=models.PDFEstimatePreview}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 503 {object} utils.ErrorResponse
+// @Router /api/v1/estimates/pdf/preview [post]
+func (h *EstimateHandler) PreviewEstimatePDF(c *gin.Context) {
+	var request models.PDFEstimateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendErrorResponse(c, 400, "無効なリクエストデータ: "+err.Error())
+		return
+	}
+
+	catalog, _, err := h.catalog.loadCategories(c.Request.Context())
+	if err != nil {
+		utils.Logger.Printf("Failed to load catalog: %v", err)
+		utils.SendErrorResponse(c, 503, "カタログを取得できませんでした。しばらくしてから再度お試しください")
+		return
+	}
+	items := catalogItemsByID(catalog)
+	estimate, mismatches, err := estimateFromPDFRequest(&request, items)
+	if err != nil {
+		utils.SendErrorResponse(c, 400, err.Error())
+		return
+	}
+	if !h.applyRecyclingFees(c, estimate) {
+		return
+	}
+
+	plan, err := h.planLoad(c.Request.Context(), estimate.Items, items)
+	if err != nil {
+		utils.Logger.Printf("Failed to plan load: %v", err)
+		utils.SendErrorResponse(c, 500, "車両の計算に失敗しました")
+		return
+	}
+	if line, ok := plan.TransportLine(); ok && request.AddTransportFee {
+		estimate.Items = append(estimate.Items, line)
+	}
+	if err := h.applyPricingRules(c.Request.Context(), estimate); err != nil {
+		utils.Logger.Printf("Failed to apply pricing rules: %v", err)
+		utils.SendErrorResponse(c, 500, "割増料金の計算に失敗しました")
+		return
+	}
+
+	utils.SuccessResponse(c, models.PDFEstimatePreview{
+		Items:          estimate.Items,
+		DiscountAmount: estimate.DiscountAmount,
+		SubTotal:       estimate.SubTotal,
+		TaxRate:        estimate.TaxRate,
+		Tax:            estimate.Tax,
+		Total:          estimate.TotalCost,
+		TaxBreakdown:   estimate.TaxBreakdown,
+		Pricing:        estimate.Pricing,
+		Mismatches:     mismatches,
+		LoadPlan:       plan,
+	})
+}
+
+// GetEstimatePDF godoc
+// @Summary 保存済み見積もりのPDFを取得
+// @Description 保存済みの見積もりから見積書PDFを再生成します。source=archive を指定すると発行時に保管した原本を返します
+// @Tags Estimates
+// @Accept json
+// @Produce application/pdf
+// @Param id path int true "見積もりID"
+// @Param source query string false "regenerate（既定）または archive" Enums(regenerate, archive)
+// @Success 200 {file} binary
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/estimates/{id}/pdf [get]
+func (h *EstimateHandler) GetEstimatePDF(c *gin.Context) {
+	estimate, ok := h.loadEstimate(c)
+	if !ok {
+		return
+	}
+
+	switch c.DefaultQuery("source", "regenerate") {
+	case "regenerate":
+	case "archive":
+		h.sendArchivedPDF(c, estimate.ID)
+		return
+	default:
+		utils.SendErrorResponse(c, 400, "sourceにはregenerateまたはarchiveを指定してください")
+		return
+	}
+
+	// 発行日は現在の版が作成された日時とする
+	revision, ok := h.loadRevision(c, estimate.ID, estimate.Revision)
+	if !ok {
+		return
+	}
+
+	pdf, err := GenerateEstimatePDF(h.buildPDFEstimate(estimate.EstimateNo, revision.CreatedAt, estimate.Revision, estimate.Snapshot()))
+	if err != nil {
+		utils.SendErrorResponse(c, 500, "PDF生成に失敗しました: "+err.Error())
+		return
+	}
+
+	writePDFResponse(c, pdf, fmt.Sprintf("estimate_%s.pdf", estimate.EstimateNo))
+}
+
+// sendArchivedPDF streams the original PDF issued for an estimate
+func (h *EstimateHandler) sendArchivedPDF(c *gin.Context, id uint) {
+	document, err := h.repo.LatestDocument(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, 404, "保管済みのPDFがありません")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to get document of estimate %d: %v", id, err)
+		utils.SendErrorResponse(c, 500, "保管済みPDFの取得に失敗しました")
+		return
+	}
+
+	data, err := readArchivedPDF(document)
+	if err != nil {
+		utils.SendErrorResponse(c, 500, err.Error())
+		return
+	}
+
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", document.FileName))
+	c.Header("Content-Length", fmt.Sprintf("%d", len(data)))
+	c.Data(200, "application/pdf", data)
+}
+
+// amountTolerance is the largest difference between the requested and computed line amounts
+// that is not treated as a mismatch (1円未満の端数処理の違いは許容する)
+const amountTolerance = 1.0
+
+// estimateFromPDFRequest converts the PDF request from the frontend into an estimate to be saved.
+// Item IDs are resolved to catalog names; "other-custom" lines are saved as free-text lines named
+// after the requested name. Line amounts are recomputed from the quantity and the custom price, or
+// the catalog price when no custom price is given; rows whose requested amount differs are
+// returned as mismatches.
+func estimateFromPDFRequest(request *models.PDFEstimateRequest, catalog map[string]catalogItem) (*models.Estimate, []models.PriceMismatch, error) {
+	estimate := &models.Estimate{
+		Title: estimateTitle,
+		Customer: models.EstimateCustomer{
+			Name:         request.Customer.Name,
+			Address:      request.Customer.Address,
+			Phone:        request.Customer.Phone,
+			Email:        request.Customer.Email,
+			DisposalDate: request.Customer.DisposalDate,
+		},
+		Items:      []models.EstimateItem{},
+		TaxRate:    models.DefaultTaxRate,
+		Pricing:    request.Pricing.OrDefault(),
+		Discount:   request.Discount,
+		Conditions: request.Conditions,
+		Status:     models.EstimateStatusDraft,
+	}
+
+	// カタログにないアイテムIDはまとめて報告する
+	unknown := []string{}
+	for i, item := range request.Items {
+		if _, ok := catalog[item.ID]; !ok {
+			unknown = append(unknown, fmt.Sprintf("%d行目(%s)", i+1, item.ID))
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, nil, fmt.Errorf("カタログに存在しないアイテムです: %s", strings.Join(unknown, ", "))
+	}
+
+	// Convert items
+	mismatches := []models.PriceMismatch{}
+	for i, item := range request.Items {
+		if item.TaxCategory != "" && !item.TaxCategory.IsValid() {
+			return nil, nil, fmt.Errorf("%d行目の税区分が不正です: %s", i+1, item.TaxCategory)
+		}
+		if item.Quantity < 0 {
+			return nil, nil, fmt.Errorf("%d行目の数量は0以上で指定してください", i+1)
+		}
+		if item.CustomPrice < 0 && item.ID != customCatalogItemID {
+			// 買取や調整などの負の金額は自由入力の明細でのみ受け付ける
+			return nil, nil, fmt.Errorf("%d行目: 負の単価は自由入力の明細でのみ指定できます", i+1)
+		}
+		if err := item.Discount.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("%d行目の値引きが不正です: %v", i+1, err)
+		}
+
+		entry := catalog[item.ID]
+		unitPrice := item.CustomPrice
+		if unitPrice == 0 {
+			unitPrice = float64(entry.Price)
+		}
+		line := models.EstimateItem{
+			ItemID:        item.ID,
+			Description:   entry.Name,
+			Category:      entry.CategoryName,
+			Specification: item.Specification,
+			Quantity:      item.Quantity,
+			Unit:          entry.Unit,
+			UnitPrice:     unitPrice,
+			Discount:      item.Discount,
+			TaxCategory:   item.TaxCategory.OrDefault(),
+		}
+		line.Amount = line.NetAmount()
+		if math.Abs(item.Amount-line.Amount) >= amountTolerance {
+			mismatches = append(mismatches, models.PriceMismatch{
+				Row:       i + 1,
+				ItemID:    item.ID,
+				Quantity:  item.Quantity,
+				UnitPrice: unitPrice,
+				Amount:    item.Amount,
+				Expected:  line.Amount,
+			})
+		}
+
+		if entry.RecyclingClass != "" {
+			// 家電リサイクル法の品目はカタログ単価を収集運搬料金とし、リサイクル料金は別の明細にする
+			line.Description = entry.Name + "（収集運搬料金）"
+			line.Recycling = &models.ApplianceRecycling{
+				Class:         entry.RecyclingClass,
+				Manufacturer:  strings.TrimSpace(item.Manufacturer),
+				SizeClass:     item.SizeClass,
+				TicketNumbers: []string{},
+			}
+		}
+		if item.ID == customCatalogItemID {
+			// 自由入力の明細は品名で扱う（品名がなければカタログ名のまま）
+			line.ItemID = ""
+			if name := strings.TrimSpace(item.Name); name != "" {
+				line.Description = name
+			}
+		}
+		estimate.Items = append(estimate.Items, line)
+	}
+
+	if err := request.Discount.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("値引きが不正です: %v", err)
+	}
+
+	// Calculate totals (書類全体の値引きを按分し、既定では消費税は税率ごとに1回だけ端数処理する)
+	estimate.Recalculate()
+
+	return estimate, mismatches, nil
+}
+
+// sendSavedEstimateError reports a PDF failure after the estimate has been saved and numbered.
+// The estimate is kept so that the sequence has no gaps, and is returned with the error so that
+// the client reissues the PDF from it instead of posting the request again.
+func sendSavedEstimateError(c *gin.Context, estimate *models.Estimate, message string) {
+	c.Header("X-Estimate-ID", fmt.Sprintf("%d", estimate.ID))
+	c.Header("X-Estimate-No", estimate.EstimateNo)
+	utils.SendErrorResponseWithData(c, 500, message+fmt.Sprintf("（見積もりは保存済みです。GET /api/v1/estimates/%d/pdf で再発行してください）", estimate.ID), estimate)
+}
+
+// archivePDF saves an issued PDF locally when SAVE_LOCAL_PDF=true, otherwise uploads it
+// to Google Drive, and returns where it was stored
+func archivePDF(filename string, data []byte) (models.DocumentStorage, string, error) {
+	if os.Getenv("SAVE_LOCAL_PDF") == "true" {
 		// ローカル保存
 		pdfDir := "./pdfs"
 		if err := os.MkdirAll(pdfDir, 0755); err != nil {
-			utils.SendErrorResponse(c, 500, "PDFディレクトリの作成に失敗しました: "+err.Error())
-			return
+			return "", "", fmt.Errorf("PDFディレクトリの作成に失敗しました: %v", err)
 		}
 		localPath := filepath.Join(pdfDir, filename)
-		if err := pdf.WritePdf(localPath); err != nil {
-			utils.SendErrorResponse(c, 500, "PDFのローカル保存に失敗しました: "+err.Error())
-			return
+		if err := os.WriteFile(localPath, data, 0644); err != nil {
+			return "", "", fmt.Errorf("PDFのローカル保存に失敗しました: %v", err)
 		}
-	} else {
-		// Google Driveにアップロード
+		return models.DocumentStorageLocal, localPath, nil
+	}
+
+	// Google Driveにアップロード
+	driveService, err := services.NewDriveService()
+	if err != nil {
+		return "", "", fmt.Errorf("Google Driveサービスの初期化に失敗しました: %v", err)
+	}
+	file, err := driveService.UploadFile(filename, "application/pdf", data)
+	if err != nil {
+		return "", "", fmt.Errorf("Google Driveへのアップロードに失敗しました: %v", err)
+	}
+	return models.DocumentStorageGoogleDrive, file.Id, nil
+}
+
+// readArchivedPDF reads the original PDF from where archivePDF stored it
+func readArchivedPDF(document *models.EstimateDocument) ([]byte, error) {
+	switch document.Storage {
+	case models.DocumentStorageLocal:
+		data, err := os.ReadFile(document.Location)
+		if err != nil {
+			return nil, fmt.Errorf("保管済みPDFの読み込みに失敗しました: %v", err)
+		}
+		return data, nil
+	case models.DocumentStorageGoogleDrive:
 		driveService, err := services.NewDriveService()
 		if err != nil {
-			utils.SendErrorResponse(c, 500, "Google Driveサービスの初期化に失敗しました: "+err.Error())
-			return
+			return nil, fmt.Errorf("Google Driveサービスの初期化に失敗しました: %v", err)
 		}
-
-		_, err = driveService.UploadFile(filename, "application/pdf", buf.Bytes())
+		data, err := driveService.DownloadFile(document.Location)
 		if err != nil {
-			utils.SendErrorResponse(c, 500, "Google Driveへのアップロードに失敗しました: "+err.Error())
-			return
+			return nil, fmt.Errorf("Google Driveからのダウンロードに失敗しました: %v", err)
 		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("不明な保管先です: %s", document.Storage)
 	}
-
-	// 保存処理の後、常にPDFファイルを直接レスポンスとして返す
-	c.Header("Content-Type", "application/pdf")
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	c.Header("Content-Length", fmt.Sprintf("%d", buf.Len()))
-	c.Data(200, "application/pdf", buf.Bytes())
 }
 
 // CreatePDF godoc
@@ -2,18 +2,35 @@ package handlers
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/signintech/gopdf"
 	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
 	"line-estimate-backend/services"
 	"line-estimate-backend/utils"
 )
 
+// InstructionHandler serves the instruction sheet endpoints
+type InstructionHandler struct {
+	estimates    repository.EstimateRepository
+	instructions repository.InstructionRepository
+	vehicles     repository.VehicleRepository
+	catalog      *CatalogHandler
+}
+
+// NewInstructionHandler creates a new InstructionHandler
+func NewInstructionHandler(estimates repository.EstimateRepository, instructions repository.InstructionRepository, vehicles repository.VehicleRepository, catalog *CatalogHandler) *InstructionHandler {
+	return &InstructionHandler{estimates: estimates, instructions: instructions, vehicles: vehicles, catalog: catalog}
+}
+
 // GenerateInstructionPDF generates an instruction sheet PDF from the provided data
 func GenerateInstructionPDF(instruction *models.PDFInstruction) (*gopdf.GoPdf, error) {
 	// Create a new PDF document (A4 Landscape for side-by-side layout)
@@ -43,22 +60,37 @@ func GenerateInstructionPDF(instruction *models.PDFInstruction) (*gopdf.GoPdf, e
 
 // CreateInstructionPDF godoc
 // @Summary 指示書PDFを生成
-// @Description 指示書情報からPDFを生成します
+// @Description 指示書情報からPDFを生成します。指示書は保存せず採番もしないため、指示書番号は指定したものを印字します（登録済みの番号は指定できません）
 // @Tags Instructions
 // @Accept json
 // @Produce application/pdf
 // @Param instruction body models.PDFInstruction true "指示書情報"
 // @Success 200 {file} binary
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /api/v1/instructions/pdf [post]
-func CreateInstructionPDF(c *gin.Context) {
+func (h *InstructionHandler) CreateInstructionPDF(c *gin.Context) {
 	var instruction models.PDFInstruction
 	if err := c.ShouldBindJSON(&instruction); err != nil {
 		utils.SendErrorResponse(c, 400, "無効なリクエストデータ: "+err.Error())
 		return
 	}
 
+	// この指示書は保存しないので採番しない。登録済みの番号と重複する指示書は作らせない
+	if instruction.InstructionNo != "" {
+		_, err := h.instructions.GetByNumber(c.Request.Context(), instruction.InstructionNo)
+		if err == nil {
+			utils.SendErrorResponse(c, 409, "指示書番号 "+instruction.InstructionNo+" は既に登録されています")
+			return
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			utils.Logger.Printf("Failed to get instruction %s: %v", instruction.InstructionNo, err)
+			utils.SendErrorResponse(c, 500, "指示書の取得に失敗しました")
+			return
+		}
+	}
+
 	// Generate PDF
 	pdf, err := GenerateInstructionPDF(&instruction)
 	if err != nil {
@@ -114,6 +146,181 @@ func CreateInstructionPDF(c *gin.Context) {
 	c.Data(200, "application/pdf", buf.Bytes())
 }
 
+// CreateEstimateInstruction godoc
+// @Summary 見積もりから作業指示書を作成
+// @Description 受注済みの見積もりの顧客・明細・処分希望日・合計金額から作業指示書を作成して保存し、PDFを返します
+// @Tags Instructions
+// @Accept json
+// @Produce application/pdf
+// @Param id path int true "見積もりID"
+// @Param instruction body models.CreateInstructionRequest false "受付者・収集日・メモ"
+// @Success 200 {file} binary
+// @Header 200 {string} X-Instruction-ID "保存した指示書のID"
+// @Header 200 {string} X-Instruction-No "指示書番号"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/estimates/{id}/instruction [post]
+func (h *InstructionHandler) CreateEstimateInstruction(c *gin.Context) {
+	id, ok := parseEstimateID(c)
+	if !ok {
+		return
+	}
+
+	var request models.CreateInstructionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			utils.SendErrorResponse(c, 400, "無効なリクエストデータ: "+err.Error())
+			return
+		}
+	}
+
+	estimate, err := h.estimates.Get(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, 404, "見積もりが見つかりません")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to get estimate %d: %v", id, err)
+		utils.SendErrorResponse(c, 500, "見積もりの取得に失敗しました")
+		return
+	}
+	if !estimate.Status.CanIssueInstruction() {
+		utils.SendErrorResponse(c, 409, fmt.Sprintf("ステータスが%sの見積もりからは作業指示書を作成できません", estimate.Status))
+		return
+	}
+
+	vehicles, err := h.vehicles.List(c.Request.Context())
+	if err != nil {
+		utils.Logger.Printf("Failed to list vehicles: %v", err)
+		utils.SendErrorResponse(c, 500, "車両の取得に失敗しました")
+		return
+	}
+
+	instruction := models.Instruction{
+		EstimateID: estimate.ID,
+		Content:    buildPDFInstruction(estimate, &request, time.Now().In(jst)),
+	}
+	instruction.Content.Vehicle = instructionVehicle(c.Request.Context(), h.catalog, estimate, vehicles)
+	if err := h.instructions.Create(c.Request.Context(), &instruction); err != nil {
+		utils.Logger.Printf("Failed to save instruction for estimate %d: %v", estimate.ID, err)
+		utils.SendErrorResponse(c, 500, "作業指示書の保存に失敗しました: "+err.Error())
+		return
+	}
+
+	// Generate PDF
+	pdf, err := GenerateInstructionPDF(&instruction.Content)
+	if err != nil {
+		utils.SendErrorResponse(c, 500, "PDF生成に失敗しました: "+err.Error())
+		return
+	}
+
+	// Convert PDF to bytes
+	var buf bytes.Buffer
+	if err := pdf.Write(&buf); err != nil {
+		utils.SendErrorResponse(c, 500, "PDFの出力に失敗しました: "+err.Error())
+		return
+	}
+
+	timestamp := time.Now().Format("20060102_150405")
+	filename := fmt.Sprintf("instruction_%s_%s.pdf", instruction.InstructionNo, timestamp)
+
+	storage, location, err := archivePDF(filename, buf.Bytes())
+	if err != nil {
+		utils.SendErrorResponse(c, 500, err.Error())
+		return
+	}
+	if err := h.instructions.SetArchive(c.Request.Context(), instruction.ID, filename, storage, location); err != nil {
+		// PDF自体は保管済みなので、記録に失敗してもレスポンスは返す
+		utils.Logger.Printf("Failed to record archive of instruction %d: %v", instruction.ID, err)
+	}
+
+	c.Header("X-Instruction-ID", fmt.Sprintf("%d", instruction.ID))
+	c.Header("X-Instruction-No", instruction.InstructionNo)
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Content-Length", fmt.Sprintf("%d", buf.Len()))
+	c.Data(200, "application/pdf", buf.Bytes())
+}
+
+// buildPDFInstruction converts an estimate into instruction sheet data
+func buildPDFInstruction(estimate *models.Estimate, request *models.CreateInstructionRequest, issueDate time.Time) models.PDFInstruction {
+	customer := estimate.Customer
+
+	instruction := models.PDFInstruction{
+		IssueDate:      issueDate,
+		CollectionDate: request.CollectionDate,
+		AcceptedBy:     request.AcceptedBy,
+		Contractor: models.PDFContractorInfo{
+			Name:    customer.Name,
+			Address: customer.Address,
+			Tel:     customer.Phone,
+		},
+		Collector: models.PDFCollectorInfo{
+			Name:    customer.Name,
+			Address: customer.Address,
+			Tel:     customer.Phone,
+		},
+		Items: []models.PDFWorkItem{},
+		Memo:  request.Memo,
+		WorkDetails: models.PDFWorkDetails{
+			CollectionAmount: utils.FormatCurrency(estimate.TotalCost) + "円",
+		},
+	}
+
+	// 収集日の指定がなければ処分希望日を和暦で印字する
+	if instruction.CollectionDate == "" {
+		instruction.CollectionDate = customer.DisposalDate
+		if date, err := time.ParseInLocation("2006-01-02", customer.DisposalDate, jst); err == nil {
+			instruction.CollectionDate = utils.FormatJapaneseDate(date)
+		}
+	}
+	if instruction.Memo == "" {
+		instruction.Memo = estimate.Description
+	}
+
+	for _, item := range estimate.Items {
+		if item.IsRecyclingFeeLine() {
+			// リサイクル料金は家電の明細と同じ作業なので印字しない
+			continue
+		}
+		instruction.Items = append(instruction.Items, models.PDFWorkItem{Description: workItemDescription(item)})
+	}
+
+	// リサイクル券は枚数を印字し、家電リサイクル法の品目がなければ「無」とする
+	if models.HasRecyclingItems(estimate.Items) {
+		if count := models.RecyclingTicketCount(estimate.Items); count > 0 {
+			instruction.WorkDetails.RecyclingTicket = fmt.Sprintf("%d枚", count)
+		}
+	} else {
+		instruction.WorkDetails.RecyclingTicketNo = true
+	}
+
+	return instruction
+}
+
+// workItemDescription formats an estimate item as a line of the instruction sheet,
+// e.g. "冷蔵庫（400L） 2台", followed by the recycling ticket numbers if recorded
+func workItemDescription(item models.EstimateItem) string {
+	description := item.Description
+	if item.Specification != "" {
+		description += "（" + item.Specification + "）"
+	}
+
+	quantity := strconv.FormatFloat(item.Quantity, 'f', -1, 64)
+	if item.Unit != "" {
+		description += " " + quantity + item.Unit
+	} else {
+		description += " ×" + quantity
+	}
+
+	if item.Recycling != nil && len(item.Recycling.TicketNumbers) > 0 {
+		description += " 券No." + strings.Join(item.Recycling.TicketNumbers, ", ")
+	}
+	return description
+}
+
 // CreateTestInstructionPDF godoc
 // @Summary テスト指示書PDFを生成
 // @Description 開発用のテスト指示書PDFを生成します
@@ -154,7 +361,8 @@ func CreateTestInstructionPDF(c *gin.Context) {
 			{Description: "プリンター 3台"},
 			{Description: "その他事務用品一式"},
 		},
-		Memo: "14時頃到着予定。駐車場は建物裏側を利用してください。",
+		Vehicle: "4tトラック × 1台",
+		Memo:    "14時頃到着予定。駐車場は建物裏側を利用してください。",
 		WorkDetails: models.PDFWorkDetails{
 			WorkSlip:          "WS-2025-0430",
 			CollectionAmount:  "55,000円",
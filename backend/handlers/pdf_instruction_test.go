@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+)
+
+func TestCreateEstimateInstruction(t *testing.T) {
+	t.Setenv("SAVE_LOCAL_PDF", "true")
+	t.Chdir(t.TempDir())
+	gin.SetMode(gin.TestMode)
+
+	db := newTestDB(t)
+	numberer, err := repository.NewDocumentNumberer(db, nil)
+	require.NoError(t, err)
+	estimates := repository.NewEstimateRepository(db, numberer)
+	instructions := repository.NewInstructionRepository(db, numberer)
+
+	router := gin.New()
+	eh := NewEstimateHandler(estimates, numberer, repository.NewPricingRuleRepository(db), repository.NewVehicleRepository(db), repository.NewRecyclingFeeRepository(db), NewCatalogHandler(nil, nil, nil, false), "")
+	ih := NewInstructionHandler(estimates, instructions, repository.NewVehicleRepository(db), NewCatalogHandler(nil, nil, nil, false))
+	router.POST("/estimates/", eh.CreateEstimate)
+	router.POST("/estimates/:id/transitions", eh.TransitionEstimate)
+	router.POST("/estimates/:id/instruction", ih.CreateEstimateInstruction)
+	router.POST("/instructions/pdf", ih.CreateInstructionPDF)
+
+	w := doJSON(router, "POST", "/estimates/", gin.H{
+		"title":       "不用品回収",
+		"description": "駐車場は建物裏側",
+		"customer":    gin.H{"name": "伊藤", "address": "東京都新宿区西新宿1-2-3", "phone": "03-1234-5678", "disposal_date": "2025-04-30"},
+		"items": []gin.H{
+			{"item_id": "refrigerator", "description": "冷蔵庫", "specification": "400L", "quantity": 1, "unit": "台", "unit_price": 6000},
+			{"description": "段ボール", "quantity": 5, "unit_price": 200},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// 受注前の見積もりからは作成できない
+	w = doJSON(router, "POST", "/estimates/1/instruction", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	for _, status := range []string{"sent", "accepted"} {
+		w = doJSON(router, "POST", "/estimates/1/transitions", gin.H{"status": status})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = doJSON(router, "POST", "/estimates/1/instruction", gin.H{"accepted_by": "田中"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.NotEmpty(t, w.Header().Get("X-Instruction-No"))
+
+	saved, err := instructions.ListByEstimate(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	content := saved[0].Content
+	assert.Equal(t, w.Header().Get("X-Instruction-No"), content.InstructionNo)
+	assert.Equal(t, "田中", content.AcceptedBy)
+	assert.Equal(t, "令和7年4月30日（水）", content.CollectionDate)
+	assert.Equal(t, "伊藤", content.Contractor.Name)
+	assert.Equal(t, "03-1234-5678", content.Collector.Tel)
+	assert.Equal(t, "7,700円", content.WorkDetails.CollectionAmount)
+	assert.Equal(t, "駐車場は建物裏側", content.Memo)
+	assert.Equal(t, []models.PDFWorkItem{
+		{Description: "冷蔵庫（400L） 1台"},
+		{Description: "段ボール ×5"},
+	}, content.Items)
+	// カタログの容積 1.2m³・重量 70kg は軽トラック1台に積める
+	assert.Equal(t, "軽トラック × 1台", content.Vehicle)
+	// 家電リサイクル法の品目がなければリサイクル券は「無」
+	assert.True(t, content.WorkDetails.RecyclingTicketNo)
+	assert.Equal(t, models.DocumentStorageLocal, saved[0].Storage)
+	assert.FileExists(t, saved[0].Location)
+
+	w = doJSON(router, "POST", "/estimates/9/instruction", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 保存しない指示書PDFは採番せず、登録済みの番号も使えない
+	w = doJSON(router, "POST", "/instructions/pdf", gin.H{"instruction_no": content.InstructionNo})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = doJSON(router, "POST", "/instructions/pdf", gin.H{"accepted_by": "田中"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = doJSON(router, "POST", "/estimates/1/instruction", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, strings.Replace(content.InstructionNo, "-001", "-002", 1), w.Header().Get("X-Instruction-No"))
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/signintech/gopdf"
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+	"line-estimate-backend/utils"
+)
+
+// defaultInvoiceRemarks are printed below the bank transfer details of invoices
+var defaultInvoiceRemarks = []string{
+	"※お支払期限までに上記口座へお振込みください。",
+	"※振込手数料はお客様のご負担にてお願いいたします。",
+}
+
+// InvoiceHandler serves the invoice endpoints
+type InvoiceHandler struct {
+	estimates repository.EstimateRepository
+	invoices  repository.InvoiceRepository
+	settings  models.InvoiceSettings
+}
+
+// NewInvoiceHandler creates a new InvoiceHandler
+func NewInvoiceHandler(estimates repository.EstimateRepository, invoices repository.InvoiceRepository, settings models.InvoiceSettings) *InvoiceHandler {
+	if settings.PaymentTermDays <= 0 {
+		settings.PaymentTermDays = models.DefaultPaymentTermDays
+	}
+	return &InvoiceHandler{estimates: estimates, invoices: invoices, settings: settings}
+}
+
+// GenerateInvoicePDF generates an invoice PDF from the provided data
+// This is an internal function, not exposed as an API endpoint
+func GenerateInvoicePDF(invoice *models.PDFInvoice) (*gopdf.GoPdf, error) {
+	pdf := &gopdf.GoPdf{}
+	pdf.Start(gopdf.Config{PageSize: *gopdf.PageSizeA4})
+
+	if err := loadJapaneseFont(pdf); err != nil {
+		return nil, err
+	}
+
+	pdf.AddPage()
+
+	helper := utils.NewPDFInvoiceHelper(pdf)
+
+	if err := helper.DrawHeader(invoice); err != nil {
+		return nil, err
+	}
+
+	if err := helper.DrawCustomerInfo(invoice); err != nil {
+		return nil, err
+	}
+
+	if err := helper.DrawInvoiceInfo(invoice); err != nil {
+		return nil, err
+	}
+
+	if err := helper.DrawBillingAmount(invoice.Total); err != nil {
+		return nil, err
+	}
+
+	tableEndY, err := helper.DrawTable(invoice, 340)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := helper.DrawPaymentInfo(invoice, tableEndY+20); err != nil {
+		return nil, err
+	}
+
+	return pdf, nil
+}
+
+// CreateEstimateInvoice godoc
+// @Summary 見積もりから請求書を作成
+// @Description 回収済みの見積もりと現場の実績（計量・追加明細）から請求書を作成して保存し、PDFを返します。見積もりは請求済みに遷移します
+// @Tags Invoices
+// @Accept json
+// @Produce application/pdf
+// @Param id path int true "見積もりID"
+// @Param invoice body models.CreateInvoiceRequest false "作業実績"
+// @Success 200 {file} binary
+// @Header 200 {string} X-Invoice-ID "保存した請求書のID"
+// @Header 200 {string} X-Invoice-No "請求書番号"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/estimates/{id}/invoice [post]
+func (h *InvoiceHandler) CreateEstimateInvoice(c *gin.Context) {
+	id, ok := parseEstimateID(c)
+	if !ok {
+		return
+	}
+
+	var request models.CreateInvoiceRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			utils.SendErrorResponse(c, 400, "無効なリクエストデータ: "+err.Error())
+			return
+		}
+	}
+
+	estimate, err := h.estimates.Get(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, 404, "見積もりが見つかりません")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to get estimate %d: %v", id, err)
+		utils.SendErrorResponse(c, 500, "見積もりの取得に失敗しました")
+		return
+	}
+	if !estimate.Status.CanIssueInvoice() {
+		utils.SendErrorResponse(c, 409, fmt.Sprintf("ステータスが%sの見積もりからは請求書を作成できません", estimate.Status))
+		return
+	}
+
+	invoice, err := h.buildInvoice(estimate, &request, time.Now().In(jst))
+	if err != nil {
+		utils.SendErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err := h.invoices.Create(c.Request.Context(), invoice); err != nil {
+		utils.Logger.Printf("Failed to save invoice for estimate %d: %v", estimate.ID, err)
+		utils.SendErrorResponse(c, 500, "請求書の保存に失敗しました: "+err.Error())
+		return
+	}
+
+	// 請求書を発行したら見積もりを請求済みにする（再発行時は遷移済み）
+	if estimate.Status == models.EstimateStatusCollected {
+		if _, err := h.estimates.Transition(c.Request.Context(), estimate.ID, models.EstimateStatusInvoiced, "請求書 "+invoice.InvoiceNo); err != nil {
+			utils.Logger.Printf("Failed to mark estimate %d as invoiced: %v", estimate.ID, err)
+		}
+	}
+
+	pdf, err := GenerateInvoicePDF(buildPDFInvoice(invoice))
+	if err != nil {
+		utils.SendErrorResponse(c, 500, "PDF生成に失敗しました: "+err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Write(&buf); err != nil {
+		utils.SendErrorResponse(c, 500, "PDFの書き込みに失敗しました: "+err.Error())
+		return
+	}
+
+	timestamp := time.Now().Format("20060102_150405")
+	filename := fmt.Sprintf("invoice_%s_%s.pdf", invoice.InvoiceNo, timestamp)
+
+	storage, location, err := archivePDF(filename, buf.Bytes())
+	if err != nil {
+		utils.SendErrorResponse(c, 500, err.Error())
+		return
+	}
+	if err := h.invoices.SetArchive(c.Request.Context(), invoice.ID, filename, storage, location); err != nil {
+		// PDF自体は保管済みなので、記録に失敗してもレスポンスは返す
+		utils.Logger.Printf("Failed to record archive of invoice %d: %v", invoice.ID, err)
+	}
+
+	c.Header("X-Invoice-ID", fmt.Sprintf("%d", invoice.ID))
+	c.Header("X-Invoice-No", invoice.InvoiceNo)
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Content-Length", fmt.Sprintf("%d", buf.Len()))
+	c.Data(200, "application/pdf", buf.Bytes())
+}
+
+// buildInvoice creates an invoice from an estimate and the actual field results
+func (h *InvoiceHandler) buildInvoice(estimate *models.Estimate, request *models.CreateInvoiceRequest, issueDate time.Time) (*models.Invoice, error) {
+	invoice := &models.Invoice{
+		EstimateID:   estimate.ID,
+		EstimateNo:   estimate.EstimateNo,
+		IssueDate:    issueDate,
+		DueDate:      issueDate.AddDate(0, 0, h.settings.PaymentTermDays),
+		WorkDate:     request.WorkDate,
+		Customer:     estimate.Customer,
+		Items:        append(append([]models.EstimateItem{}, estimate.Items...), request.ExtraItems...),
+		ActualWeight: request.ActualWeight,
+		TaxRate:      estimate.TaxRate,
+		Pricing:      estimate.Pricing.OrDefault(),
+		Discount:     estimate.Discount,
+		Bank:         h.settings.Bank,
+		Remarks:      defaultInvoiceRemarks,
+
+		RegistrationNumber: h.settings.RegistrationNumber,
+	}
+
+	if err := invoice.Pricing.ValidateForIssuer(invoice.RegistrationNumber); err != nil {
+		return nil, fmt.Errorf("行ごとに端数処理した見積もりからは適格請求書を発行できません。端数処理を書類ごとに変更してください: %v", err)
+	}
+	if err := models.ValidateDiscounts(request.ExtraItems, models.Discount{}); err != nil {
+		return nil, fmt.Errorf("追加明細の値引きが不正です: %v", err)
+	}
+	if request.DueDate != "" {
+		dueDate, err := time.ParseInLocation("2006-01-02", request.DueDate, jst)
+		if err != nil {
+			return nil, fmt.Errorf("お支払期限はYYYY-MM-DD形式で指定してください")
+		}
+		year, month, day := issueDate.In(jst).Date()
+		if dueDate.Before(time.Date(year, month, day, 0, 0, 0, 0, jst)) {
+			return nil, fmt.Errorf("お支払期限は発行日以降の日付を指定してください")
+		}
+		invoice.DueDate = dueDate
+	}
+	if invoice.WorkDate == "" {
+		invoice.WorkDate = estimate.Customer.DisposalDate
+	}
+	if invoice.TaxRate == 0 {
+		invoice.TaxRate = models.DefaultTaxRate
+	}
+
+	if len(invoice.Items) == 0 {
+		// 明細のない見積もりは合計金額を1行で請求する
+		invoice.Items = []models.EstimateItem{{
+			Description: estimate.Title,
+			Quantity:    1,
+			Unit:        "式",
+			UnitPrice:   estimate.TotalCost,
+		}}
+	}
+	invoice.Recalculate()
+
+	return invoice, nil
+}
+
+// buildPDFInvoice converts a stored invoice into PDF data
+func buildPDFInvoice(invoice *models.Invoice) *models.PDFInvoice {
+	pdfInvoice := &models.PDFInvoice{
+		InvoiceNo:  invoice.InvoiceNo,
+		EstimateNo: invoice.EstimateNo,
+		IssueDate:  invoice.IssueDate.In(jst),
+		DueDate:    invoice.DueDate.In(jst),
+		WorkDate:   invoice.WorkDate,
+		Customer: models.PDFCustomerInfo{
+			CompanyName: invoice.Customer.Name,
+			Address:     invoice.Customer.Address,
+			Tel:         invoice.Customer.Phone,
+		},
+		Recipient:    invoice.Customer.Name + " 様",
+		ActualWeight: invoice.ActualWeight,
+		Items:        pdfLineItems(invoice.Items, invoice.Discount, invoice.DiscountAmount),
+		SubTotal:     invoice.SubTotal,
+		TaxRate:      invoice.TaxRate,
+		Tax:          invoice.Tax,
+		Total:        invoice.Total,
+		TaxBreakdown: invoice.TaxBreakdown,
+		Pricing:      invoice.Pricing.OrDefault(),
+		Issuer:       models.PDFCompanyInfo{RegistrationNumber: invoice.RegistrationNumber},
+		Bank:         invoice.Bank,
+		Remarks:      invoice.Remarks,
+	}
+
+	return pdfInvoice
+}
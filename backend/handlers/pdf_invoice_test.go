@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+)
+
+func TestCreateEstimateInvoice(t *testing.T) {
+	t.Setenv("SAVE_LOCAL_PDF", "true")
+	t.Chdir(t.TempDir())
+	gin.SetMode(gin.TestMode)
+
+	db := newTestDB(t)
+	numberer, err := repository.NewDocumentNumberer(db, nil)
+	require.NoError(t, err)
+	estimates := repository.NewEstimateRepository(db, numberer)
+	invoices := repository.NewInvoiceRepository(db, numberer)
+
+	router := gin.New()
+	eh := NewEstimateHandler(estimates, numberer, repository.NewPricingRuleRepository(db), repository.NewVehicleRepository(db), repository.NewRecyclingFeeRepository(db), NewCatalogHandler(nil, nil, nil, false), "")
+	ih := NewInvoiceHandler(estimates, invoices, models.InvoiceSettings{
+		RegistrationNumber: "T7000012050002",
+		Bank:               models.BankAccount{BankName: "○○銀行", BranchName: "本店", AccountType: "普通", AccountNumber: "1234567", AccountHolder: "カ）マルキョウ"},
+	})
+	router.POST("/estimates/", eh.CreateEstimate)
+	router.GET("/estimates/:id", eh.GetEstimate)
+	router.POST("/estimates/:id/transitions", eh.TransitionEstimate)
+	router.POST("/estimates/:id/invoice", ih.CreateEstimateInvoice)
+
+	w := doJSON(router, "POST", "/estimates/", gin.H{
+		"title":    "不用品回収",
+		"customer": gin.H{"name": "渡辺", "disposal_date": "2026-10-10"},
+		"items": []gin.H{
+			{"description": "ソファ", "quantity": 1, "unit": "台", "unit_price": 8000},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// 回収前の見積もりには請求書を発行できない
+	w = doJSON(router, "POST", "/estimates/1/invoice", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	for _, status := range []string{"sent", "accepted", "scheduled", "collected"} {
+		w = doJSON(router, "POST", "/estimates/1/transitions", gin.H{"status": status})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = doJSON(router, "POST", "/estimates/1/invoice", gin.H{"due_date": "2026/11/30"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doJSON(router, "POST", "/estimates/1/invoice", gin.H{
+		"actual_weight": 120.5,
+		"extra_items": []gin.H{
+			{"description": "階段作業費", "quantity": 1, "unit_price": 2000},
+			{"description": "お茶（作業員差し入れ分）", "quantity": 3, "unit_price": 111, "tax_category": "reduced"},
+			{"description": "家電リサイクル料金", "quantity": 1, "unit_price": 3740, "tax_category": "non_taxable"},
+		},
+		"due_date": "2099-11-30",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	invoiceNo := w.Header().Get("X-Invoice-No")
+	assert.Regexp(t, `^INV-\d{6}-0001$`, invoiceNo)
+
+	saved, err := invoices.ListByEstimate(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	invoice := saved[0]
+	assert.Equal(t, invoiceNo, invoice.InvoiceNo)
+	assert.Len(t, invoice.Items, 4)
+	assert.Equal(t, 14073.0, invoice.SubTotal)
+	// 消費税は税率ごとに1回だけ切り捨てる: 10000×10% + floor(333×8%)。不課税分には課税しない
+	assert.Equal(t, []models.TaxBreakdown{
+		{Category: models.TaxCategoryStandard, Rate: 0.10, Taxable: 10000, Tax: 1000},
+		{Category: models.TaxCategoryReduced, Rate: 0.08, Taxable: 333, Tax: 26},
+		{Category: models.TaxCategoryNonTaxable, Rate: 0, Taxable: 3740, Tax: 0},
+	}, invoice.TaxBreakdown)
+	assert.Equal(t, 1026.0, invoice.Tax)
+	assert.Equal(t, 15099.0, invoice.Total)
+	assert.Equal(t, "T7000012050002", invoice.RegistrationNumber)
+	assert.Equal(t, 120.5, invoice.ActualWeight)
+	assert.Equal(t, "2026-10-10", invoice.WorkDate)
+	assert.Equal(t, "2099-11-30", invoice.DueDate.In(jst).Format("2006-01-02"))
+	assert.Equal(t, "1234567", invoice.Bank.AccountNumber)
+	assert.FileExists(t, invoice.Location)
+
+	// 見積もりは請求済みになる
+	w = doJSON(router, "GET", "/estimates/1", nil)
+	assert.Contains(t, w.Body.String(), `"status":"invoiced"`)
+}
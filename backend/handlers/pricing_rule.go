@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+	"line-estimate-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PricingRuleHandler serves the endpoints for editing the job condition pricing rules
+type PricingRuleHandler struct {
+	rules repository.PricingRuleRepository
+}
+
+// NewPricingRuleHandler creates a new PricingRuleHandler
+func NewPricingRuleHandler(rules repository.PricingRuleRepository) *PricingRuleHandler {
+	return &PricingRuleHandler{rules: rules}
+}
+
+// GetPricingRules godoc
+// @Summary 割増ルール一覧を取得
+// @Description 階段作業・横持ち・解体・当日対応・夜間・最低料金の割増ルールを適用順に取得します
+// @Tags PricingRules
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.PricingRule}
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/pricing-rules [get]
+func (h *PricingRuleHandler) GetPricingRules(c *gin.Context) {
+	rules, err := h.rules.List(c.Request.Context())
+	if err != nil {
+		utils.Logger.Printf("Failed to list pricing rules: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get pricing rules")
+		return
+	}
+
+	utils.SuccessResponse(c, rules)
+}
+
+// UpdatePricingRule godoc
+// @Summary 割増ルールを更新
+// @Description 割増ルールの名称・金額・割増率・基準値などを変更します。変更後に作成・更新した見積もりから適用されます
+// @Tags PricingRules
+// @Accept json
+// @Produce json
+// @Param id path int true "ルールID"
+// @Param rule body models.UpdatePricingRuleRequest true "ルールの内容"
+// @Success 200 {object} utils.Response{data=models.PricingRule}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/pricing-rules/{id} [put]
+func (h *PricingRuleHandler) UpdatePricingRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid pricing rule ID")
+		return
+	}
+
+	var req models.UpdatePricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule, err := h.rules.Get(c.Request.Context(), uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Pricing rule not found")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to get pricing rule %d: %v", id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get pricing rule")
+		return
+	}
+
+	rule.Name = req.Name
+	rule.Unit = req.Unit
+	rule.Amount = req.Amount
+	rule.Percent = req.Percent
+	rule.Threshold = req.Threshold
+	rule.Step = req.Step
+	rule.TaxCategory = req.TaxCategory
+	rule.Enabled = req.Enabled
+	rule.SortOrder = req.SortOrder
+	if err := h.rules.Update(c.Request.Context(), rule); err != nil {
+		utils.Logger.Printf("Failed to update pricing rule %d: %v", id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to update pricing rule")
+		return
+	}
+
+	utils.SuccessResponse(c, rule)
+}
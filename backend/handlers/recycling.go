@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
+	"line-estimate-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recyclingTicketPattern matches the inquiry number of a recycling ticket (お問合せ管理票番号)
+var recyclingTicketPattern = regexp.MustCompile(`^[0-9][0-9-]*[0-9]$`)
+
+// RecyclingFeeHandler serves the endpoints for editing the recycling fee table
+type RecyclingFeeHandler struct {
+	fees repository.RecyclingFeeRepository
+}
+
+// NewRecyclingFeeHandler creates a new RecyclingFeeHandler
+func NewRecyclingFeeHandler(fees repository.RecyclingFeeRepository) *RecyclingFeeHandler {
+	return &RecyclingFeeHandler{fees: fees}
+}
+
+// GetRecyclingFees godoc
+// @Summary 家電リサイクル料金表を取得
+// @Description 家電リサイクル法の品目ごとのリサイクル料金をメーカー・サイズ区分別に取得します。メーカーが空の行は標準料金です
+// @Tags RecyclingFees
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.RecyclingFee}
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/recycling-fees [get]
+func (h *RecyclingFeeHandler) GetRecyclingFees(c *gin.Context) {
+	fees, err := h.fees.List(c.Request.Context())
+	if err != nil {
+		utils.Logger.Printf("Failed to list recycling fees: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get recycling fees")
+		return
+	}
+
+	utils.SuccessResponse(c, fees)
+}
+
+// CreateRecyclingFee godoc
+// @Summary 家電リサイクル料金を登録
+// @Description メーカー・サイズ区分ごとのリサイクル料金を料金表に追加します
+// @Tags RecyclingFees
+// @Accept json
+// @Produce json
+// @Param fee body models.RecyclingFeeRequest true "リサイクル料金"
+// @Success 201 {object} utils.Response{data=models.RecyclingFee}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/recycling-fees [post]
+func (h *RecyclingFeeHandler) CreateRecyclingFee(c *gin.Context) {
+	fee, ok := bindRecyclingFee(c)
+	if !ok {
+		return
+	}
+
+	err := h.fees.Create(c.Request.Context(), fee)
+	if errors.Is(err, repository.ErrDuplicate) {
+		utils.SendErrorResponse(c, http.StatusConflict, "Recycling fee already exists")
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Failed to create recycling fee: %v", err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create recycling fee")
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Data:    fee,
+	})
+}
+
+// UpdateRecyclingFee godoc
+// @Summary 家電リサイクル料金を更新
+// @Description 料金表の行を更新します。変更後に作成する見積もりから適用されます
+// @Tags RecyclingFees
+// @Accept json
+// @Produce json
+// @Param id path int true "料金表の行ID"
+// @Param fee body models.RecyclingFeeRequest true "リサイクル料金"
+// @Success 200 {object} utils.Response{data=models.RecyclingFee}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/recycling-fees/{id} [put]
+func (h *RecyclingFeeHandler) UpdateRecyclingFee(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid recycling fee ID")
+		return
+	}
+	fee, ok := bindRecyclingFee(c)
+	if !ok {
+		return
+	}
+	fee.ID = uint(id)
+
+	err = h.fees.Update(c.Request.Context(), fee)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		utils.SendErrorResponse(c, http.StatusNotFound, "Recycling fee not found")
+	case errors.Is(err, repository.ErrDuplicate):
+		utils.SendErrorResponse(c, http.StatusConflict, "Recycling fee already exists")
+	case err != nil:
+		utils.Logger.Printf("Failed to update recycling fee %d: %v", id, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to update recycling fee")
+	default:
+		utils.SuccessResponse(c, fee)
+	}
+}
+
+// bindRecyclingFee binds and validates a fee table row, writing a 400 response on failure
+func bindRecyclingFee(c *gin.Context) (*models.RecyclingFee, bool) {
+	var req models.RecyclingFeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if req.Class.HasSizeClasses() != (req.SizeClass != "") {
+		utils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("size_class is required only for tv and refrigerator: %s", req.Class))
+		return nil, false
+	}
+
+	return &models.RecyclingFee{
+		Class:        req.Class,
+		Manufacturer: req.Manufacturer,
+		SizeClass:    req.SizeClass,
+		Fee:          req.Fee,
+	}, true
+}
+
+// SetRecyclingTickets godoc
+// @Summary リサイクル券番号を記録
+// @Description 家電リサイクル法の品目の明細ごとにリサイクル券のお問合せ管理票番号を記録します。見積もりの版は変わりません
+// @Tags Estimates
+// @Accept json
+// @Produce json
+// @Param id path int true "見積もりID"
+// @Param tickets body models.RecyclingTicketsRequest true "明細ごとのリサイクル券番号"
+// @Success 200 {object} utils.Response{data=models.Estimate}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/v1/estimates/{id}/recycling-tickets [put]
+func (h *EstimateHandler) SetRecyclingTickets(c *gin.Context) {
+	var req models.RecyclingTicketsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	estimate, ok := h.loadEstimate(c)
+	if !ok {
+		return
+	}
+
+	tickets := map[int][]string{}
+	seen := map[string]bool{}
+	for _, entry := range req.Items {
+		if entry.Line > len(estimate.Items) || estimate.Items[entry.Line-1].Recycling == nil {
+			utils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Line %d is not a recycling law appliance", entry.Line))
+			return
+		}
+		item := estimate.Items[entry.Line-1]
+		if float64(len(entry.TicketNumbers)) > item.Quantity {
+			utils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Line %d has more tickets than its quantity", entry.Line))
+			return
+		}
+		for _, number := range entry.TicketNumbers {
+			if !recyclingTicketPattern.MatchString(number) {
+				utils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid recycling ticket number: %s", number))
+				return
+			}
+			if seen[number] {
+				utils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Duplicate recycling ticket number: %s", number))
+				return
+			}
+			seen[number] = true
+		}
+		tickets[entry.Line-1] = entry.TicketNumbers
+	}
+
+	if err := h.repo.SetRecyclingTickets(c.Request.Context(), estimate.ID, tickets); err != nil {
+		utils.Logger.Printf("Failed to set recycling tickets of estimate %d: %v", estimate.ID, err)
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to set recycling tickets")
+		return
+	}
+
+	estimate, ok = h.loadEstimate(c)
+	if !ok {
+		return
+	}
+	utils.SuccessResponse(c, estimate)
+}
+
+// applyRecyclingFees inserts the recycling fee line after each recycling law appliance,
+// writing the error response and returning false when a fee cannot be determined
+func (h *EstimateHandler) applyRecyclingFees(c *gin.Context, estimate *models.Estimate) bool {
+	if !models.HasRecyclingItems(estimate.Items) {
+		return true
+	}
+
+	fees, err := h.recyclingFees.List(c.Request.Context())
+	if err != nil {
+		utils.Logger.Printf("Failed to list recycling fees: %v", err)
+		utils.SendErrorResponse(c, 500, "リサイクル料金の取得に失敗しました")
+		return false
+	}
+	items, err := models.WithRecyclingFeeLines(estimate.Items, fees)
+	if err != nil {
+		utils.SendErrorResponse(c, 400, err.Error())
+		return false
+	}
+	estimate.Items = items
+	estimate.Recalculate()
+	return true
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"line-estimate-backend/models"
+	"line-estimate-backend/utils"
+)
+
+// PlanEstimateLoad godoc
+// @Summary 積載量と推奨車両を計算
+// @Description PDFリクエストの明細からカタログの容積・重量を合計し、収集運搬費が最も安くなる車両と台数を返します。見積もりは保存しません
+// @Tags Estimates
+// @Accept json
+// @Produce json
+// @Param estimate body models.PDFEstimateRequest true "見積もり情報"
+// @Success 200 {object} utils.Response{data=models.LoadPlan}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Failure 503 {object} utils.ErrorResponse
+// @Router /api/v1/estimates/pdf/load-plan [post]
+func (h *EstimateHandler) PlanEstimateLoad(c *gin.Context) {
+	var request models.PDFEstimateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendErrorResponse(c, 400, "無効なリクエストデータ: "+err.Error())
+		return
+	}
+
+	catalog, _, err := h.catalog.loadCategories(c.Request.Context())
+	if err != nil {
+		utils.Logger.Printf("Failed to load catalog: %v", err)
+		utils.SendErrorResponse(c, 503, "カタログを取得できませんでした。しばらくしてから再度お試しください")
+		return
+	}
+	items := catalogItemsByID(catalog)
+	estimate, _, err := estimateFromPDFRequest(&request, items)
+	if err != nil {
+		utils.SendErrorResponse(c, 400, err.Error())
+		return
+	}
+
+	plan, err := h.planLoad(c.Request.Context(), estimate.Items, items)
+	if err != nil {
+		utils.Logger.Printf("Failed to plan load: %v", err)
+		utils.SendErrorResponse(c, 500, "車両の計算に失敗しました")
+		return
+	}
+
+	utils.SuccessResponse(c, plan)
+}
+
+// planLoad sums the catalog volume and weight of the items and recommends a vehicle
+func (h *EstimateHandler) planLoad(ctx context.Context, items []models.EstimateItem, catalog map[string]catalogItem) (models.LoadPlan, error) {
+	vehicles, err := h.vehicles.List(ctx)
+	if err != nil {
+		return models.LoadPlan{}, err
+	}
+	return planItemsLoad(items, catalog, vehicles), nil
+}
+
+// addTransportFee appends the transport fee line of the recommended vehicle to the estimate
+func (h *EstimateHandler) addTransportFee(ctx context.Context, estimate *models.Estimate, catalog map[string]catalogItem) error {
+	plan, err := h.planLoad(ctx, estimate.Items, catalog)
+	if err != nil {
+		return err
+	}
+	if line, ok := plan.TransportLine(); ok {
+		estimate.Items = append(estimate.Items, line)
+	}
+	return nil
+}
+
+// planItemsLoad sums the catalog volume and weight of the items and recommends a vehicle.
+// Surcharge, transport and recycling fee lines are not loads; other lines without a registered volume
+// or weight, such as free-text lines, are reported as unmeasured.
+func planItemsLoad(items []models.EstimateItem, catalog map[string]catalogItem, vehicles []models.Vehicle) models.LoadPlan {
+	var volume, weight float64
+	unmeasured := []int{}
+	for i, item := range items {
+		if item.IsPricingRuleLine() || item.IsTransportLine() || item.IsRecyclingFeeLine() {
+			continue
+		}
+		entry, ok := catalog[item.ItemID]
+		if !ok || (entry.Volume <= 0 && entry.Weight <= 0) {
+			unmeasured = append(unmeasured, i+1)
+			continue
+		}
+		volume += entry.Volume * item.Quantity
+		weight += entry.Weight * item.Quantity
+	}
+
+	plan := models.PlanLoad(volume, weight, vehicles)
+	plan.UnmeasuredRows = unmeasured
+	return plan
+}
+
+// instructionVehicle returns the vehicle printed on the instruction sheet of an estimate:
+// the vehicle of its transport fee line, or the vehicle recommended for its items
+func instructionVehicle(ctx context.Context, catalog *CatalogHandler, estimate *models.Estimate, vehicles []models.Vehicle) string {
+	for _, item := range estimate.Items {
+		if !item.IsTransportLine() {
+			continue
+		}
+		for i := range vehicles {
+			if item.ItemID == models.TransportItemID(vehicles[i].Code) {
+				return models.LoadPlan{Vehicle: &vehicles[i], Trips: int(item.Quantity)}.VehicleLabel()
+			}
+		}
+	}
+
+	categories, _, err := catalog.loadCategories(ctx)
+	if err != nil {
+		// 車両は参考表示なので、カタログがなくても指示書は作成する
+		utils.Logger.Printf("Failed to load catalog for vehicle recommendation: %v", err)
+	}
+	return planItemsLoad(estimate.Items, catalogItemsByID(categories), vehicles).VehicleLabel()
+}
@@ -10,8 +10,11 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	"line-estimate-backend/config"
 	_ "line-estimate-backend/docs"
 	"line-estimate-backend/handlers"
+	"line-estimate-backend/models"
+	"line-estimate-backend/repository"
 )
 
 // @title Line Estimate API
@@ -43,6 +46,49 @@ func main() {
 		}
 	}
 
+	cfg := config.GetConfig()
+
+	// データベース接続とマイグレーション
+	db, err := repository.Open(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("Failed to connect database:", err)
+	}
+	defer db.Close()
+
+	if err := db.Migrate(); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	numberer, err := repository.NewDocumentNumberer(db, cfg.NumberingRules)
+	if err != nil {
+		log.Fatal("Failed to configure document numbering:", err)
+	}
+
+	if number := cfg.Invoice.RegistrationNumber; number != "" {
+		if err := models.ValidateRegistrationNumber(number); err != nil {
+			log.Fatal("Invalid ISSUER_REGISTRATION_NUMBER:", err)
+		}
+	}
+
+	estimateRepo := repository.NewEstimateRepository(db, numberer)
+	pricingRuleRepo := repository.NewPricingRuleRepository(db)
+	vehicleRepo := repository.NewVehicleRepository(db)
+	recyclingFeeRepo := repository.NewRecyclingFeeRepository(db)
+	catalogRepo := repository.NewCatalogRepository(db)
+	// 本番環境ではGoogle Sheetsのカタログ、それ以外はモックデータを使う
+	var catalogCache *handlers.CatalogCache
+	if os.Getenv("GO_ENV") == "production" {
+		catalogCache = handlers.NewCatalogCache(cfg.CatalogCacheTTL, cfg.CatalogCacheFile)
+	}
+	catalogHandler := handlers.NewCatalogHandler(catalogRepo, estimateRepo, catalogCache, cfg.CatalogSheetSync)
+	estimateHandler := handlers.NewEstimateHandler(estimateRepo, numberer, pricingRuleRepo, vehicleRepo, recyclingFeeRepo, catalogHandler, cfg.Invoice.RegistrationNumber)
+	recyclingFeeHandler := handlers.NewRecyclingFeeHandler(recyclingFeeRepo)
+	pricingRuleHandler := handlers.NewPricingRuleHandler(pricingRuleRepo)
+	instructionRepo := repository.NewInstructionRepository(db, numberer)
+	instructionHandler := handlers.NewInstructionHandler(estimateRepo, instructionRepo, vehicleRepo, catalogHandler)
+	manifestHandler := handlers.NewManifestHandler(estimateRepo, instructionRepo, repository.NewManifestRepository(db))
+	invoiceHandler := handlers.NewInvoiceHandler(estimateRepo, repository.NewInvoiceRepository(db, numberer), cfg.Invoice)
+
 	// Ginエンジンの初期化
 	r := gin.Default()
 
@@ -77,23 +123,80 @@ func main() {
 	v1 := r.Group("/api/v1")
 	{
 		// カテゴリー関連
-		v1.GET("/categories", handlers.GetCategories)
+		v1.GET("/categories", catalogHandler.GetCategories)
+		v1.GET("/categories/search", catalogHandler.SearchCategories)
+		v1.GET("/categories/export", catalogHandler.ExportCategories)
+
+		// 品目マスタ管理
+		catalog := v1.Group("/catalog")
+		{
+			catalog.GET("/categories", catalogHandler.GetCatalog)
+			catalog.POST("/categories", catalogHandler.CreateCatalogCategory)
+			catalog.PUT("/categories/:id", catalogHandler.UpdateCatalogCategory)
+			catalog.DELETE("/categories/:id", catalogHandler.DeleteCatalogCategory)
+			catalog.POST("/items", catalogHandler.CreateCatalogItem)
+			catalog.PUT("/items/:id", catalogHandler.UpdateCatalogItem)
+			catalog.DELETE("/items/:id", catalogHandler.DeleteCatalogItem)
+			catalog.GET("/items/:id/prices", catalogHandler.GetCatalogItemPrices)
+			catalog.POST("/items/:id/prices", catalogHandler.ScheduleCatalogItemPrice)
+			catalog.DELETE("/items/:id/prices/:effective_from", catalogHandler.CancelCatalogItemPrice)
+			catalog.POST("/sync", catalogHandler.SyncCatalog)
+			catalog.GET("/validate", catalogHandler.ValidateCatalogSheet)
+			catalog.POST("/import/preview", catalogHandler.PreviewCatalogImport)
+			catalog.POST("/import", catalogHandler.ImportCatalog)
+		}
 
 		// 見積もり関連
 		estimates := v1.Group("/estimates")
 		{
-			estimates.GET("/", handlers.GetEstimates)
-			estimates.POST("/", handlers.CreateEstimate)
-			estimates.GET("/:id", handlers.GetEstimate)
-			estimates.PUT("/:id", handlers.UpdateEstimate)
-			estimates.DELETE("/:id", handlers.DeleteEstimate)
-			estimates.POST("/pdf", handlers.CreateEstimatePDF)
+			estimates.GET("/", estimateHandler.GetEstimates)
+			estimates.POST("/", estimateHandler.CreateEstimate)
+			estimates.GET("/:id", estimateHandler.GetEstimate)
+			estimates.PUT("/:id", estimateHandler.UpdateEstimate)
+			estimates.DELETE("/:id", estimateHandler.DeleteEstimate)
+			estimates.POST("/:id/transitions", estimateHandler.TransitionEstimate)
+			estimates.PUT("/:id/recycling-tickets", estimateHandler.SetRecyclingTickets)
+			estimates.GET("/:id/revisions", estimateHandler.ListEstimateRevisions)
+			estimates.GET("/:id/revisions/diff", estimateHandler.GetEstimateRevisionDiff)
+			estimates.GET("/:id/revisions/:revision/pdf", estimateHandler.GetEstimateRevisionPDF)
+			estimates.GET("/:id/pdf", estimateHandler.GetEstimatePDF)
+			estimates.POST("/pdf", estimateHandler.CreateEstimatePDF)
+			estimates.POST("/pdf/preview", estimateHandler.PreviewEstimatePDF)
+			estimates.POST("/pdf/load-plan", estimateHandler.PlanEstimateLoad)
+			estimates.POST("/:id/instruction", instructionHandler.CreateEstimateInstruction)
+			estimates.POST("/:id/invoice", invoiceHandler.CreateEstimateInvoice)
+		}
+
+		// 割増ルール関連
+		pricingRules := v1.Group("/pricing-rules")
+		{
+			pricingRules.GET("", pricingRuleHandler.GetPricingRules)
+			pricingRules.PUT("/:id", pricingRuleHandler.UpdatePricingRule)
+		}
+
+		// 家電リサイクル料金関連
+		recyclingFees := v1.Group("/recycling-fees")
+		{
+			recyclingFees.GET("", recyclingFeeHandler.GetRecyclingFees)
+			recyclingFees.POST("", recyclingFeeHandler.CreateRecyclingFee)
+			recyclingFees.PUT("/:id", recyclingFeeHandler.UpdateRecyclingFee)
 		}
 
 		// 指示書関連
 		instructions := v1.Group("/instructions")
 		{
-			instructions.POST("/pdf", handlers.CreateInstructionPDF)
+			instructions.POST("/pdf", instructionHandler.CreateInstructionPDF)
+			instructions.POST("/:id/manifests", manifestHandler.CreateInstructionManifest)
+		}
+
+		// マニフェスト関連
+		manifests := v1.Group("/manifests")
+		{
+			manifests.GET("", manifestHandler.GetManifests)
+			manifests.GET("/alerts", manifestHandler.GetManifestAlerts)
+			manifests.GET("/export", manifestHandler.ExportManifests)
+			manifests.GET("/:id", manifestHandler.GetManifest)
+			manifests.POST("/:id/returns", manifestHandler.RecordManifestReturn)
 		}
 
 		// ユーザー関連
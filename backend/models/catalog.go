@@ -0,0 +1,136 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// catalogIDPattern matches category and item IDs (e.g. pipe-chair). IDs are referenced by
+// estimate lines, so they cannot be changed after creation.
+var catalogIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// CatalogCategory is a category of the item master managed from the admin API
+type CatalogCategory struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Hiragana  string        `json:"hiragana"`   // 読み（ひらがな）
+	SortOrder int           `json:"sort_order"` // 表示順
+	Active    bool          `json:"active"`     // 無効のカテゴリーは見積もりに表示しない
+	Items     []CatalogItem `json:"items"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// CatalogItem is an item of the item master
+type CatalogItem struct {
+	ID             string         `json:"id"`
+	CategoryID     string         `json:"category_id"`
+	Name           string         `json:"name"`
+	Hiragana       string         `json:"hiragana"` // 読み（ひらがな）
+	Price          int            `json:"price"`    // 税抜単価
+	Unit           string         `json:"unit"`     // 単位（例: 台、点）
+	Volume         float64        `json:"volume"`   // 1点あたりの容積 (m³)
+	Weight         float64        `json:"weight"`   // 1点あたりの重量 (kg)
+	RecyclingClass ApplianceClass `json:"recycling_class"`
+	SortOrder      int            `json:"sort_order"`
+	Active         bool           `json:"active"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	// EffectiveFrom and EffectiveTo are the period of the price (YYYY-MM-DD). When saving,
+	// a price different from the one effective on EffectiveFrom is recorded in the history.
+	EffectiveFrom string `json:"effective_from,omitempty"`
+	EffectiveTo   string `json:"effective_to,omitempty"`
+}
+
+// CatalogCategoryRequest is the body of the category create and update endpoints.
+// The ID is only read on creation.
+type CatalogCategoryRequest struct {
+	ID        string `json:"id"`
+	Name      string `json:"name" binding:"required"`
+	Hiragana  string `json:"hiragana"`
+	SortOrder int    `json:"sort_order"`
+	Active    *bool  `json:"active"` // 省略時は有効
+}
+
+// CatalogItemRequest is the body of the item create and update endpoints.
+// The ID is only read on creation.
+type CatalogItemRequest struct {
+	ID             string         `json:"id"`
+	CategoryID     string         `json:"category_id" binding:"required"`
+	Name           string         `json:"name" binding:"required"`
+	Hiragana       string         `json:"hiragana" binding:"required"`
+	Price          int            `json:"price" binding:"min=0"`
+	Unit           string         `json:"unit"`
+	Volume         float64        `json:"volume" binding:"min=0"`
+	Weight         float64        `json:"weight" binding:"min=0"`
+	RecyclingClass ApplianceClass `json:"recycling_class"`
+	SortOrder      int            `json:"sort_order"`
+	Active         *bool          `json:"active"` // 省略時は有効
+}
+
+// CatalogSyncDirection is the direction of a synchronization with the categories sheet
+type CatalogSyncDirection string
+
+const (
+	CatalogSyncPull CatalogSyncDirection = "pull" // シート → データベース
+	CatalogSyncPush CatalogSyncDirection = "push" // データベース → シート
+)
+
+// CatalogSyncRequest is the body of the sheet synchronization endpoint
+type CatalogSyncRequest struct {
+	Direction CatalogSyncDirection `json:"direction" binding:"required,oneof=pull push"`
+}
+
+// CatalogSyncResult reports the outcome of a sheet synchronization
+type CatalogSyncResult struct {
+	Direction  CatalogSyncDirection `json:"direction"`
+	Source     string               `json:"source"` // 取り込み元 (google_sheets / mock_data)
+	Categories int                  `json:"categories"`
+	Items      int                  `json:"items"`
+}
+
+// ValidateCatalogID checks the format of a category or item ID
+func ValidateCatalogID(id string) error {
+	if !catalogIDPattern.MatchString(id) {
+		return fmt.Errorf("id must consist of lowercase letters, digits and hyphens: %q", id)
+	}
+	return nil
+}
+
+// ToCategory converts the request into a category
+func (r CatalogCategoryRequest) ToCategory() CatalogCategory {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return CatalogCategory{
+		ID:        r.ID,
+		Name:      r.Name,
+		Hiragana:  r.Hiragana,
+		SortOrder: r.SortOrder,
+		Active:    active,
+	}
+}
+
+// ToItem converts the request into an item
+func (r CatalogItemRequest) ToItem() (CatalogItem, error) {
+	if r.RecyclingClass != "" && !r.RecyclingClass.IsValid() {
+		return CatalogItem{}, fmt.Errorf("unknown recycling_class: %s", r.RecyclingClass)
+	}
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return CatalogItem{
+		ID:             r.ID,
+		CategoryID:     r.CategoryID,
+		Name:           r.Name,
+		Hiragana:       r.Hiragana,
+		Price:          r.Price,
+		Unit:           r.Unit,
+		Volume:         r.Volume,
+		Weight:         r.Weight,
+		RecyclingClass: r.RecyclingClass,
+		SortOrder:      r.SortOrder,
+		Active:         active,
+	}, nil
+}
@@ -0,0 +1,116 @@
+package models
+
+// CatalogFileFormat is the file format of a catalog import or export
+type CatalogFileFormat string
+
+const (
+	CatalogFileCSV  CatalogFileFormat = "csv"
+	CatalogFileXLSX CatalogFileFormat = "xlsx"
+)
+
+// IsValid reports whether the format is supported
+func (f CatalogFileFormat) IsValid() bool {
+	return f == CatalogFileCSV || f == CatalogFileXLSX
+}
+
+// CatalogImportItem summarizes an item added, removed or updated by an import
+type CatalogImportItem struct {
+	ItemID     string `json:"item_id"`
+	CategoryID string `json:"category_id"`
+	Name       string `json:"name"`
+	Hiragana   string `json:"hiragana"`
+	Price      int    `json:"price"`
+}
+
+// CatalogPriceChange is an item whose price is changed by an import
+type CatalogPriceChange struct {
+	ItemID   string `json:"item_id"`
+	Name     string `json:"name"`
+	OldPrice int    `json:"old_price"`
+	NewPrice int    `json:"new_price"`
+}
+
+// CatalogImportDiff lists the changes an import makes to the current catalog
+type CatalogImportDiff struct {
+	Added        []CatalogImportItem  `json:"added"`
+	Removed      []CatalogImportItem  `json:"removed"`
+	PriceChanged []CatalogPriceChange `json:"price_changed"`
+	Updated      []CatalogImportItem  `json:"updated"` // 価格以外（名前・読み・カテゴリーなど）が変わるアイテム
+	Unchanged    int                  `json:"unchanged"`
+}
+
+// CatalogImportResult is the preview of an import, or its outcome once applied
+type CatalogImportResult struct {
+	Format  CatalogFileFormat       `json:"format"`
+	Applied bool                    `json:"applied"`
+	Report  CatalogValidationReport `json:"report"`
+	Diff    CatalogImportDiff       `json:"diff"`
+}
+
+// DiffCatalog compares the current catalog with the one to be imported, item by item.
+// Items are listed in the order of the imported catalog, removed items in the current order.
+func DiffCatalog(current, imported []CatalogCategory) CatalogImportDiff {
+	diff := CatalogImportDiff{
+		Added:        []CatalogImportItem{},
+		Removed:      []CatalogImportItem{},
+		PriceChanged: []CatalogPriceChange{},
+		Updated:      []CatalogImportItem{},
+	}
+
+	existing := map[string]CatalogItem{}
+	for _, category := range current {
+		for _, item := range category.Items {
+			existing[item.ID] = item
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, category := range imported {
+		for _, item := range category.Items {
+			seen[item.ID] = true
+			old, ok := existing[item.ID]
+			switch {
+			case !ok:
+				diff.Added = append(diff.Added, importItem(item))
+				continue
+			case old.Price != item.Price:
+				diff.PriceChanged = append(diff.PriceChanged, CatalogPriceChange{ItemID: item.ID, Name: item.Name, OldPrice: old.Price, NewPrice: item.Price})
+			}
+			if catalogItemChanged(old, item) {
+				diff.Updated = append(diff.Updated, importItem(item))
+			} else if old.Price == item.Price {
+				diff.Unchanged++
+			}
+		}
+	}
+
+	for _, category := range current {
+		for _, item := range category.Items {
+			if !seen[item.ID] {
+				diff.Removed = append(diff.Removed, importItem(item))
+			}
+		}
+	}
+	return diff
+}
+
+// catalogItemChanged reports whether an item changes other than in price and display order
+func catalogItemChanged(old, item CatalogItem) bool {
+	return old.CategoryID != item.CategoryID ||
+		old.Name != item.Name ||
+		old.Hiragana != item.Hiragana ||
+		old.Unit != item.Unit ||
+		old.Volume != item.Volume ||
+		old.Weight != item.Weight ||
+		old.RecyclingClass != item.RecyclingClass
+}
+
+func importItem(item CatalogItem) CatalogImportItem {
+	return CatalogImportItem{
+		ItemID:     item.ID,
+		CategoryID: item.CategoryID,
+		Name:       item.Name,
+		Hiragana:   item.Hiragana,
+		Price:      item.Price,
+	}
+}
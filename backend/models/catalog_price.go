@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// CatalogItemPrice is a price of a catalog item effective from a date (価格改定の履歴).
+// Each price applies until the day before the next one takes effect.
+type CatalogItemPrice struct {
+	ItemID        string    `json:"item_id"`
+	Price         int       `json:"price"`          // 税抜単価
+	EffectiveFrom string    `json:"effective_from"` // 適用開始日 (YYYY-MM-DD)
+	EffectiveTo   string    `json:"effective_to"`   // 適用終了日。空は次の改定が未定
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CatalogItemPriceRequest is the body of the endpoint scheduling a price change
+type CatalogItemPriceRequest struct {
+	Price         int    `json:"price" binding:"min=0"`
+	EffectiveFrom string `json:"effective_from" binding:"required"` // YYYY-MM-DD
+	Note          string `json:"note"`
+}
+
+// Validate checks the effective date. Prices cannot be changed retroactively, so the
+// date must not be before today (YYYY-MM-DD in JST).
+func (r CatalogItemPriceRequest) Validate(today string) error {
+	if !isValidDate(r.EffectiveFrom) {
+		return fmt.Errorf("effective_from must be YYYY-MM-DD")
+	}
+	if r.EffectiveFrom < today {
+		return fmt.Errorf("effective_from must not be in the past")
+	}
+	return nil
+}
+
+// SetPriceEffectiveTo fills in the last effective day of prices sorted by effective date
+func SetPriceEffectiveTo(prices []CatalogItemPrice) {
+	for i := range prices {
+		prices[i].EffectiveTo = ""
+		if i+1 == len(prices) {
+			break
+		}
+		next, err := time.Parse("2006-01-02", prices[i+1].EffectiveFrom)
+		if err != nil {
+			continue
+		}
+		prices[i].EffectiveTo = next.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+}
+
+// PriceAsOf returns the price effective on the date from prices sorted by effective date.
+// It returns false when no price was in effect, e.g. before the item was added.
+func PriceAsOf(prices []CatalogItemPrice, date string) (CatalogItemPrice, bool) {
+	var price CatalogItemPrice
+	found := false
+	for _, p := range prices {
+		if p.EffectiveFrom > date {
+			break
+		}
+		price, found = p, true
+	}
+	return price, found
+}
+
+// CatalogItemPriceHistory is the price history of an item with the price effective today
+type CatalogItemPriceHistory struct {
+	ItemID  string             `json:"item_id"`
+	Current *CatalogItemPrice  `json:"current,omitempty"` // 本日適用される価格。未適用の改定しかなければ空
+	Prices  []CatalogItemPrice `json:"prices"`            // 適用開始日の順。予定されている改定を含む
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceAsOf(t *testing.T) {
+	prices := []CatalogItemPrice{
+		{Price: 3000, EffectiveFrom: "2000-01-01"},
+		{Price: 3500, EffectiveFrom: "2026-03-01"},
+		{Price: 4000, EffectiveFrom: "2026-04-01"},
+	}
+	SetPriceEffectiveTo(prices)
+	assert.Equal(t, "2026-02-28", prices[0].EffectiveTo)
+	assert.Equal(t, "2026-03-31", prices[1].EffectiveTo)
+	assert.Empty(t, prices[2].EffectiveTo)
+
+	for date, want := range map[string]int{"2000-01-01": 3000, "2026-03-31": 3500, "2026-04-01": 4000} {
+		price, ok := PriceAsOf(prices, date)
+		assert.True(t, ok)
+		assert.Equal(t, want, price.Price, date)
+	}
+	// 最初の価格より前の日付には適用される価格がない
+	_, ok := PriceAsOf(prices, "1999-12-31")
+	assert.False(t, ok)
+	_, ok = PriceAsOf(nil, "2026-04-01")
+	assert.False(t, ok)
+
+	assert.Error(t, CatalogItemPriceRequest{EffectiveFrom: "2026/04/01"}.Validate("2026-03-01"))
+	assert.Error(t, CatalogItemPriceRequest{EffectiveFrom: "2026-02-28"}.Validate("2026-03-01"))
+	assert.NoError(t, CatalogItemPriceRequest{EffectiveFrom: "2026-03-01"}.Validate("2026-03-01"))
+}
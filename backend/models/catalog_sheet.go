@@ -0,0 +1,317 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Columns of the categories sheet (カテゴリーID, カテゴリー名, アイテムID, アイテム名, ひらがな,
+// 価格, 容積, 重量, 家電リサイクル, 単位, カテゴリー表示順, アイテム表示順). The first six are required.
+const (
+	CatalogColumnCategoryID     = "category_id"
+	CatalogColumnCategoryName   = "category_name"
+	CatalogColumnItemID         = "item_id"
+	CatalogColumnItemName       = "item_name"
+	CatalogColumnHiragana       = "hiragana"
+	CatalogColumnPrice          = "price"
+	CatalogColumnVolume         = "volume"
+	CatalogColumnWeight         = "weight"
+	CatalogColumnRecyclingClass = "recycling_class"
+	CatalogColumnUnit           = "unit"
+	CatalogColumnCategoryOrder  = "category_sort_order"
+	CatalogColumnItemOrder      = "item_sort_order"
+
+	catalogRequiredColumns = 6
+)
+
+// CatalogSheetColumns are the columns of the categories sheet in order
+var CatalogSheetColumns = []string{
+	CatalogColumnCategoryID, CatalogColumnCategoryName, CatalogColumnItemID, CatalogColumnItemName, CatalogColumnHiragana,
+	CatalogColumnPrice, CatalogColumnVolume, CatalogColumnWeight, CatalogColumnRecyclingClass, CatalogColumnUnit,
+	CatalogColumnCategoryOrder, CatalogColumnItemOrder,
+}
+
+// catalogSheetLabels are the Japanese headings accepted in place of CatalogSheetColumns
+var catalogSheetLabels = []string{
+	"カテゴリーID", "カテゴリー名", "アイテムID", "アイテム名", "ひらがな",
+	"価格", "容積", "重量", "家電リサイクル", "単位",
+	"カテゴリー表示順", "アイテム表示順",
+}
+
+// CatalogIssueSeverity tells whether an issue blocks publishing the catalog
+type CatalogIssueSeverity string
+
+const (
+	CatalogIssueError   CatalogIssueSeverity = "error"   // 公開を止める
+	CatalogIssueWarning CatalogIssueSeverity = "warning" // 公開はするが確認が必要
+)
+
+// CatalogSheetIssue is a problem found in a row of the categories sheet
+type CatalogSheetIssue struct {
+	Row      int                  `json:"row"`    // シートの行番号（見出し行が1）
+	Column   string               `json:"column"` // 列名（行全体の問題では空）
+	Value    string               `json:"value"`
+	Severity CatalogIssueSeverity `json:"severity"`
+	Message  string               `json:"message"`
+}
+
+// CatalogSheetRow is a row of the categories sheet that passed validation
+type CatalogSheetRow struct {
+	Row            int
+	CategoryID     string
+	CategoryName   string
+	ItemID         string
+	ItemName       string
+	Hiragana       string
+	Price          int
+	Volume         float64
+	Weight         float64
+	RecyclingClass ApplianceClass
+	Unit           string
+	CategoryOrder  int // カテゴリーの表示順（0は未指定）
+	ItemOrder      int // カテゴリー内のアイテムの表示順（0は未指定）
+}
+
+// CatalogValidationReport is the row-by-row result of validating the categories sheet
+type CatalogValidationReport struct {
+	Rows        int                 `json:"rows"`       // 見出しと空行を除いた行数
+	ValidRows   int                 `json:"valid_rows"` // エラーのない行数
+	Errors      int                 `json:"errors"`
+	Warnings    int                 `json:"warnings"`
+	Publishable bool                `json:"publishable"` // エラーがなく1件以上のアイテムがある
+	Issues      []CatalogSheetIssue `json:"issues"`
+}
+
+// CatalogSheetError is returned when the categories sheet has errors and must not be published
+type CatalogSheetError struct {
+	Report CatalogValidationReport
+}
+
+func (e *CatalogSheetError) Error() string {
+	if e.Report.Errors == 0 {
+		return "catalog sheet has no items"
+	}
+	first := e.Report.Issues[0]
+	for _, issue := range e.Report.Issues {
+		if issue.Severity == CatalogIssueError {
+			first = issue
+			break
+		}
+	}
+	return fmt.Sprintf("catalog sheet has %d errors (row %d: %s)", e.Report.Errors, first.Row, first.Message)
+}
+
+// ParseCatalogSheet validates the rows of the categories sheet, including the header row,
+// and returns the valid rows with a report. Blank rows are skipped. Rows with errors are
+// left out of the result; callers must not publish the catalog unless the report is publishable.
+func ParseCatalogSheet(sheet [][]string) ([]CatalogSheetRow, CatalogValidationReport) {
+	report := CatalogValidationReport{Issues: []CatalogSheetIssue{}}
+	rows := []CatalogSheetRow{}
+	itemRows := map[string]int{}
+	categoryNames := map[string]string{}
+
+	if len(sheet) > 0 {
+		// 列は位置で読むため、見出しが違う（列がずれた）シートは行ごとの検証より前に止める
+		report.Issues = append(report.Issues, checkCatalogSheetHeader(sheet[0])...)
+		report.Errors = len(report.Issues)
+	}
+
+	for i, values := range sheet {
+		if i == 0 || isBlankRow(values) {
+			continue // 見出し行
+		}
+		report.Rows++
+
+		row, issues := parseCatalogSheetRow(i+1, values)
+		if row.ItemID != "" {
+			if first, ok := itemRows[row.ItemID]; ok {
+				issues = append(issues, catalogError(row.Row, CatalogColumnItemID, row.ItemID, fmt.Sprintf("duplicate item_id, first used on row %d", first)))
+			} else {
+				itemRows[row.ItemID] = row.Row
+			}
+		}
+		if row.CategoryID != "" && row.CategoryName != "" {
+			if name, ok := categoryNames[row.CategoryID]; !ok {
+				categoryNames[row.CategoryID] = row.CategoryName
+			} else if name != row.CategoryName {
+				issues = append(issues, catalogWarning(row.Row, CatalogColumnCategoryName, row.CategoryName, fmt.Sprintf("category_name differs from %q used earlier for the same category_id", name)))
+			}
+		}
+
+		valid := true
+		for _, issue := range issues {
+			if issue.Severity == CatalogIssueError {
+				report.Errors++
+				valid = false
+			} else {
+				report.Warnings++
+			}
+		}
+		report.Issues = append(report.Issues, issues...)
+		if valid {
+			report.ValidRows++
+			rows = append(rows, row)
+		}
+	}
+
+	report.Publishable = report.Errors == 0 && report.ValidRows > 0
+	return rows, report
+}
+
+// checkCatalogSheetHeader checks that the header row names the columns in the expected order.
+// The required columns must be present; the optional ones are checked only when named.
+func checkCatalogSheetHeader(header []string) []CatalogSheetIssue {
+	issues := []CatalogSheetIssue{}
+	for i, column := range CatalogSheetColumns {
+		value := ""
+		if i < len(header) {
+			value = strings.TrimSpace(header[i])
+		}
+		if value == "" && i >= catalogRequiredColumns {
+			continue
+		}
+		if !strings.EqualFold(value, column) && value != catalogSheetLabels[i] {
+			issues = append(issues, catalogError(1, column, value, fmt.Sprintf("header of column %c must be %q", rune('A'+i), column)))
+		}
+	}
+	return issues
+}
+
+// parseCatalogSheetRow parses a data row, returning the issues found in it
+func parseCatalogSheetRow(rowNumber int, values []string) (CatalogSheetRow, []CatalogSheetIssue) {
+	cell := func(index int) string {
+		if index < len(values) {
+			return strings.TrimSpace(values[index])
+		}
+		return ""
+	}
+	row := CatalogSheetRow{
+		Row:          rowNumber,
+		CategoryID:   cell(0),
+		CategoryName: cell(1),
+		ItemID:       cell(2),
+		ItemName:     cell(3),
+		Hiragana:     cell(4),
+		Unit:         cell(9),
+	}
+	issues := []CatalogSheetIssue{}
+
+	if len(values) < catalogRequiredColumns {
+		issues = append(issues, catalogError(rowNumber, "", "", fmt.Sprintf("row has %d columns, at least %d are required", len(values), catalogRequiredColumns)))
+	}
+	for _, required := range []struct {
+		column, value, message string
+	}{
+		{CatalogColumnCategoryID, row.CategoryID, "category_id is blank"},
+		{CatalogColumnCategoryName, row.CategoryName, "category_name is blank"},
+		{CatalogColumnItemID, row.ItemID, "item_id is blank"},
+		{CatalogColumnItemName, row.ItemName, "item_name is blank"},
+		{CatalogColumnHiragana, row.Hiragana, "hiragana reading is missing"},
+	} {
+		if required.value == "" {
+			issues = append(issues, catalogError(rowNumber, required.column, "", required.message))
+		}
+	}
+	for _, id := range []struct{ column, value string }{{CatalogColumnCategoryID, row.CategoryID}, {CatalogColumnItemID, row.ItemID}} {
+		if id.value != "" && ValidateCatalogID(id.value) != nil {
+			issues = append(issues, catalogWarning(rowNumber, id.column, id.value, "id should consist of lowercase letters, digits and hyphens"))
+		}
+	}
+	if row.Hiragana != "" && !isHiraganaReading(row.Hiragana) {
+		issues = append(issues, catalogWarning(rowNumber, CatalogColumnHiragana, row.Hiragana, "reading contains characters other than hiragana"))
+	}
+
+	// 価格は必須。表示形式の桁区切り（1,500）だけは許容する
+	if price := cell(5); price != "" {
+		value, err := strconv.Atoi(strings.ReplaceAll(price, ",", ""))
+		switch {
+		case err != nil:
+			issues = append(issues, catalogError(rowNumber, CatalogColumnPrice, price, "price is not a number"))
+		case value < 0:
+			issues = append(issues, catalogError(rowNumber, CatalogColumnPrice, price, "price must not be negative"))
+		default:
+			row.Price = value
+		}
+	} else {
+		issues = append(issues, catalogError(rowNumber, CatalogColumnPrice, "", "price is blank"))
+	}
+
+	// 容積・重量は省略可能だが、入力されていれば0以上の数値であること
+	for _, measure := range []struct {
+		column string
+		value  string
+		target *float64
+	}{
+		{CatalogColumnVolume, cell(6), &row.Volume},
+		{CatalogColumnWeight, cell(7), &row.Weight},
+	} {
+		if measure.value == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.ReplaceAll(measure.value, ",", ""), 64)
+		if err != nil || value < 0 {
+			issues = append(issues, catalogError(rowNumber, measure.column, measure.value, "must be a non-negative number"))
+			continue
+		}
+		*measure.target = value
+	}
+
+	if class := ApplianceClass(cell(8)); class != "" {
+		if class.IsValid() {
+			row.RecyclingClass = class
+		} else {
+			issues = append(issues, catalogError(rowNumber, CatalogColumnRecyclingClass, string(class), "recycling_class must be one of tv, refrigerator, washing_machine, air_conditioner"))
+		}
+	}
+
+	// 表示順は省略可能。未指定のものは指定されたものの後にシートの順で並ぶ
+	for _, order := range []struct {
+		column string
+		value  string
+		target *int
+	}{
+		{CatalogColumnCategoryOrder, cell(10), &row.CategoryOrder},
+		{CatalogColumnItemOrder, cell(11), &row.ItemOrder},
+	} {
+		if order.value == "" {
+			continue
+		}
+		value, err := strconv.Atoi(order.value)
+		if err != nil || value < 0 {
+			issues = append(issues, catalogError(rowNumber, order.column, order.value, "must be a non-negative integer"))
+			continue
+		}
+		*order.target = value
+	}
+
+	return row, issues
+}
+
+func catalogError(row int, column, value, message string) CatalogSheetIssue {
+	return CatalogSheetIssue{Row: row, Column: column, Value: value, Severity: CatalogIssueError, Message: message}
+}
+
+func catalogWarning(row int, column, value, message string) CatalogSheetIssue {
+	return CatalogSheetIssue{Row: row, Column: column, Value: value, Severity: CatalogIssueWarning, Message: message}
+}
+
+func isBlankRow(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// isHiraganaReading reports whether a reading consists of hiragana, ー, digits and spaces
+func isHiraganaReading(reading string) bool {
+	for _, r := range reading {
+		if !unicode.Is(unicode.Hiragana, r) && r != 'ー' && !unicode.IsDigit(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCatalogSheet(t *testing.T) {
+	sheet := [][]string{
+		CatalogSheetColumns,
+		{"chairs", "椅子", "pipe-chair", "パイプ椅子", "ぱいぷいす", "1,500", "0.1", "4"},
+		{"chairs", "椅子", "pipe-chair", "パイプ椅子", "ぱいぷいす", "500"},
+		{},
+		{"chairs", "椅子", "stool", "スツール", "", "abc"},
+		{"", "", "desk", "机", "つくえ", "-100"},
+		{"appliances", "家電", "tv", "テレビ", "テレビ", "3500", "", "x", "radio"},
+		{"tables", "テーブル", "table"},
+		{"chairs", "いす", "sofa", "ソファー", "そふぁー", "3000", "", "", "", "台"},
+	}
+
+	rows, report := ParseCatalogSheet(sheet)
+	assert.False(t, report.Publishable)
+	assert.Equal(t, 7, report.Rows, "header and blank rows are not counted")
+	assert.Equal(t, 2, report.ValidRows)
+	require.Len(t, rows, 2)
+	assert.Equal(t, 1500, rows[0].Price)
+	assert.Equal(t, 2, rows[0].Row)
+	assert.Equal(t, "台", rows[1].Unit)
+
+	byRow := map[int][]string{}
+	for _, issue := range report.Issues {
+		byRow[issue.Row] = append(byRow[issue.Row], string(issue.Severity)+":"+issue.Column)
+	}
+	assert.Equal(t, []string{"error:item_id"}, byRow[3])
+	assert.Equal(t, []string{"error:hiragana", "error:price"}, byRow[5])
+	assert.Equal(t, []string{"error:category_id", "error:category_name", "error:price"}, byRow[6])
+	assert.Equal(t, []string{"warning:hiragana", "error:weight", "error:recycling_class"}, byRow[7])
+	assert.Contains(t, byRow[8], "error:")
+	assert.Equal(t, []string{"warning:category_name"}, byRow[9])
+	assert.Equal(t, 2, report.Warnings)
+
+	err := &CatalogSheetError{Report: report}
+	assert.Contains(t, err.Error(), "row 3: duplicate item_id")
+
+	_, report = ParseCatalogSheet(sheet[:2])
+	assert.True(t, report.Publishable)
+	assert.Empty(t, report.Issues)
+
+	japanese := [][]string{catalogSheetLabels[:catalogRequiredColumns], sheet[1]}
+	_, report = ParseCatalogSheet(japanese)
+	assert.True(t, report.Publishable)
+
+	swapped := [][]string{{"category_id", "category_name", "item_id", "item_name", "price", "hiragana"}, sheet[1]}
+	_, report = ParseCatalogSheet(swapped)
+	assert.False(t, report.Publishable)
+	require.Len(t, report.Issues, 2)
+	assert.Equal(t, 1, report.Issues[0].Row)
+	assert.Equal(t, CatalogColumnHiragana, report.Issues[0].Column)
+}
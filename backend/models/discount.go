@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// DiscountType is how a discount is specified
+type DiscountType string
+
+const (
+	DiscountTypeFixed   DiscountType = "fixed"   // 金額指定
+	DiscountTypePercent DiscountType = "percent" // 率指定 (%)
+)
+
+// DefaultDiscountLabel is printed for document discounts without a label
+const DefaultDiscountLabel = "値引き"
+
+// Discount is a 値引き applied to a line or to the whole document
+type Discount struct {
+	Type  DiscountType `json:"type" binding:"omitempty,oneof=fixed percent"`
+	Value float64      `json:"value" binding:"min=0"` // 円、または%
+	Label string       `json:"label,omitempty"`       // 書類全体の値引きの印字名（省略時は「値引き」）
+}
+
+// IsZero reports whether no discount is set
+func (d Discount) IsZero() bool {
+	return d.Type == "" || d.Value == 0
+}
+
+// Validate checks that the discount can be applied
+func (d Discount) Validate() error {
+	if d.Type != "" && d.Type != DiscountTypeFixed && d.Type != DiscountTypePercent {
+		return fmt.Errorf("unknown discount type: %s", d.Type)
+	}
+	if d.Value < 0 {
+		return fmt.Errorf("discount must not be negative")
+	}
+	if d.Type == DiscountTypePercent && d.Value > 100 {
+		return fmt.Errorf("discount percentage must be 100 or less")
+	}
+	return nil
+}
+
+// Amount returns the yen taken off base. Percentage discounts are rounded down to a whole yen
+// and no discount exceeds base; nothing is taken off zero or negative amounts.
+func (d Discount) Amount(base float64) float64 {
+	if d.IsZero() || base <= 0 {
+		return 0
+	}
+
+	amount := d.Value
+	if d.Type == DiscountTypePercent {
+		amount = math.Floor(base * d.Value / 100)
+	}
+	return math.Min(amount, base)
+}
+
+// LabelOrDefault returns the label printed for the discount
+func (d Discount) LabelOrDefault() string {
+	if d.Label == "" {
+		return DefaultDiscountLabel
+	}
+	return d.Label
+}
+
+// DiscountBase returns the amount a document discount applies to: the sum of the lines
+// except non-taxable pass-through charges such as recycling fees
+func DiscountBase(items []EstimateItem) float64 {
+	base := 0.0
+	for _, item := range items {
+		if item.TaxCategory.OrDefault() != TaxCategoryNonTaxable {
+			base += item.Amount
+		}
+	}
+	return base
+}
+
+// DiscountLines allocates a document discount to the tax categories of the items in proportion
+// to their amounts (値引きの税率ごとの按分) and returns it as negative lines, so that the tax
+// is calculated on the discounted amounts. The last category takes the rounding remainder.
+func DiscountLines(items []EstimateItem, amount float64, label string) []EstimateItem {
+	if amount <= 0 {
+		return nil
+	}
+
+	amounts := map[TaxCategory]float64{}
+	for _, item := range items {
+		amounts[item.TaxCategory.OrDefault()] += item.Amount
+	}
+
+	categories := []TaxCategory{}
+	base := 0.0
+	for _, category := range TaxCategories {
+		if category != TaxCategoryNonTaxable && amounts[category] > 0 {
+			categories = append(categories, category)
+			base += amounts[category]
+		}
+	}
+	if base <= 0 {
+		return nil
+	}
+
+	lines := []EstimateItem{}
+	remaining := amount
+	for i, category := range categories {
+		allocated := remaining
+		if i < len(categories)-1 {
+			allocated = math.Floor(amount * amounts[category] / base)
+		}
+		remaining -= allocated
+		lines = append(lines, EstimateItem{
+			Description: label,
+			Quantity:    1,
+			UnitPrice:   -allocated,
+			Amount:      -allocated,
+			TaxCategory: category,
+		})
+	}
+	return lines
+}
+
+// WithDiscountLines returns the items followed by the document discount allocated to each
+// tax category, i.e. the lines the tax is calculated on
+func WithDiscountLines(items []EstimateItem, discount Discount, amount float64) []EstimateItem {
+	lines := append([]EstimateItem{}, items...)
+	return append(lines, DiscountLines(items, amount, discount.LabelOrDefault())...)
+}
+
+// ValidateDiscounts checks the line discounts and the document discount
+func ValidateDiscounts(items []EstimateItem, document Discount) error {
+	for i, item := range items {
+		if err := item.Discount.Validate(); err != nil {
+			return fmt.Errorf("item %d: %v", i+1, err)
+		}
+	}
+	return document.Validate()
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentDiscountIsAllocatedPerTaxCategory(t *testing.T) {
+	estimate := Estimate{
+		TaxRate: DefaultTaxRate,
+		Items: []EstimateItem{
+			{Description: "片付け作業", Quantity: 1, UnitPrice: 10000},
+			{Description: "飲料", Quantity: 1, UnitPrice: 5000, TaxCategory: TaxCategoryReduced},
+			{Description: "家電リサイクル料金", Quantity: 1, UnitPrice: 3000, TaxCategory: TaxCategoryNonTaxable},
+		},
+		Discount: Discount{Type: DiscountTypeFixed, Value: 1500},
+	}
+	estimate.Recalculate()
+
+	// 値引きは不課税分を除いた金額の比で按分する: 10% 対象 1,000円、8% 対象 500円
+	assert.Equal(t, 1500.0, estimate.DiscountAmount)
+	assert.Equal(t, 16500.0, estimate.SubTotal)
+	assert.Equal(t, []TaxBreakdown{
+		{Category: TaxCategoryStandard, Rate: 0.10, Taxable: 9000, Tax: 900},
+		{Category: TaxCategoryReduced, Rate: 0.08, Taxable: 4500, Tax: 360},
+		{Category: TaxCategoryNonTaxable, Rate: 0, Taxable: 3000, Tax: 0},
+	}, estimate.TaxBreakdown)
+	assert.Equal(t, 1260.0, estimate.Tax)
+	assert.Equal(t, 17760.0, estimate.TotalCost)
+}
+
+func TestDiscountAmount(t *testing.T) {
+	assert.Equal(t, 333.0, Discount{Type: DiscountTypePercent, Value: 10}.Amount(3335))
+	assert.Equal(t, 500.0, Discount{Type: DiscountTypeFixed, Value: 800}.Amount(500))
+	assert.Equal(t, 0.0, Discount{Type: DiscountTypeFixed, Value: 800}.Amount(-3000))
+	assert.Error(t, Discount{Type: DiscountTypePercent, Value: 120}.Validate())
+}
@@ -4,31 +4,110 @@ import (
 	"time"
 )
 
+// DefaultTaxRate is the consumption tax rate applied to new estimates
+const DefaultTaxRate = 0.10
+
 type Estimate struct {
-	ID          uint      `json:"id" gorm:"primaryKey"`
-	UserID      uint      `json:"user_id" gorm:"not null"`
-	Title       string    `json:"title" gorm:"not null"`
-	Description string    `json:"description"`
-	TotalLines  int       `json:"total_lines"`
-	HourlyRate  float64   `json:"hourly_rate"`
-	TotalCost   float64   `json:"total_cost"`
-	Status      string    `json:"status" gorm:"default:'draft'"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	User        User      `json:"user" gorm:"foreignKey:UserID"`
+	ID             uint                 `json:"id" gorm:"primaryKey"`
+	EstimateNo     string               `json:"estimate_no" gorm:"uniqueIndex"`
+	UserID         uint                 `json:"user_id" gorm:"not null"`
+	Title          string               `json:"title" gorm:"not null"`
+	Description    string               `json:"description"`
+	TotalLines     int                  `json:"total_lines"`
+	HourlyRate     float64              `json:"hourly_rate"`
+	Customer       EstimateCustomer     `json:"customer" gorm:"embedded;embeddedPrefix:customer_"`
+	Items          []EstimateItem       `json:"items" gorm:"foreignKey:EstimateID"`
+	SubTotal       float64              `json:"sub_total"`
+	TaxRate        float64              `json:"tax_rate"`
+	Tax            float64              `json:"tax"`
+	TotalCost      float64              `json:"total_cost"`
+	TaxBreakdown   []TaxBreakdown       `json:"tax_breakdown,omitempty" gorm:"-"` // 税区分ごとの合計
+	Pricing        PricingMode          `json:"pricing"`                          // 税込/税抜と端数処理
+	Discount       Discount             `json:"discount"`                         // 書類全体の値引き
+	DiscountAmount float64              `json:"discount_amount"`                  // 書類全体の値引き額（小計から差し引き済み）
+	Conditions     JobConditions        `json:"conditions"`                       // 階数や駐車距離などの作業条件
+	Revision       int                  `json:"revision" gorm:"default:1"`
+	Status         EstimateStatus       `json:"status" gorm:"default:'draft'"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+	User           User                 `json:"user" gorm:"foreignKey:UserID"`
+	Transitions    []EstimateTransition `json:"transitions,omitempty" gorm:"-"`
+}
+
+// EstimateCustomer represents the customer an estimate is addressed to
+type EstimateCustomer struct {
+	Name         string `json:"name"`
+	Address      string `json:"address"`
+	Phone        string `json:"phone"`
+	Email        string `json:"email"`
+	DisposalDate string `json:"disposal_date"` // 処分希望日
+}
+
+// EstimateItem represents each line of an estimate
+type EstimateItem struct {
+	ItemID        string      `json:"item_id"` // カタログのアイテムID（自由入力の場合は空）
+	Description   string      `json:"description" binding:"required"`
+	Category      string      `json:"category"` // カタログのカテゴリー名
+	Specification string      `json:"specification"`
+	Quantity      float64     `json:"quantity" binding:"min=0"`
+	Unit          string      `json:"unit"`
+	UnitPrice     float64     `json:"unit_price"` // 買取や調整は負の単価で入力する
+	Discount      Discount    `json:"discount"`   // 明細の値引き
+	Amount        float64     `json:"amount"`
+	TaxCategory   TaxCategory `json:"tax_category" binding:"omitempty,oneof=standard reduced exempt non_taxable"` // 税区分（省略時は標準税率）
+	// Recycling is set on appliances covered by the recycling law (家電リサイクル法)
+	Recycling *ApplianceRecycling `json:"recycling,omitempty"`
+}
+
+// NetAmount returns quantity × unit price less the line discount
+func (item EstimateItem) NetAmount() float64 {
+	gross := item.Quantity * item.UnitPrice
+	return gross - item.Discount.Amount(gross)
+}
+
+// Recalculate recomputes line amounts and totals from the items.
+// Estimates without items keep their legacy TotalCost.
+func (e *Estimate) Recalculate() {
+	if len(e.Items) == 0 {
+		return
+	}
+
+	for i := range e.Items {
+		e.Items[i].Amount = e.Items[i].NetAmount()
+		e.Items[i].TaxCategory = e.Items[i].TaxCategory.OrDefault()
+	}
+
+	// 書類全体の値引きは税区分ごとに按分してから消費税を計算する
+	e.DiscountAmount = e.Discount.Amount(DiscountBase(e.Items))
+	e.SubTotal, e.TaxBreakdown, e.Tax = CalculateTax(WithDiscountLines(e.Items, e.Discount, e.DiscountAmount), e.TaxRate, e.Pricing)
+	e.TotalCost = e.Pricing.Total(e.SubTotal, e.Tax)
 }
 
 type CreateEstimateRequest struct {
-	Title       string  `json:"title" binding:"required"`
-	Description string  `json:"description"`
-	TotalLines  int     `json:"total_lines" binding:"required,min=1"`
-	HourlyRate  float64 `json:"hourly_rate" binding:"required,min=0"`
+	Title       string           `json:"title" binding:"required"`
+	Description string           `json:"description"`
+	TotalLines  int              `json:"total_lines" binding:"omitempty,min=1"`
+	HourlyRate  float64          `json:"hourly_rate" binding:"min=0"`
+	Customer    EstimateCustomer `json:"customer"`
+	Items       []EstimateItem   `json:"items" binding:"dive"`
+	Pricing     PricingMode      `json:"pricing"`    // 省略時は税抜・税率ごとに切り捨て
+	Discount    Discount         `json:"discount"`   // 書類全体の値引き
+	Conditions  JobConditions    `json:"conditions"` // 作業条件（割増ルールで明細を追加する）
 }
 
 type UpdateEstimateRequest struct {
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	TotalLines  int     `json:"total_lines" binding:"min=1"`
-	HourlyRate  float64 `json:"hourly_rate" binding:"min=0"`
-	Status      string  `json:"status"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	TotalLines  int               `json:"total_lines" binding:"omitempty,min=1"`
+	HourlyRate  float64           `json:"hourly_rate" binding:"min=0"`
+	Customer    *EstimateCustomer `json:"customer"`
+	Items       []EstimateItem    `json:"items" binding:"omitempty,dive"` // 指定した場合は明細を置き換える
+	Pricing     *PricingMode      `json:"pricing"`                        // 指定した場合は計算方法を変更する
+	Discount    *Discount         `json:"discount"`                       // 指定した場合は書類全体の値引きを置き換える
+	Conditions  *JobConditions    `json:"conditions"`                     // 指定した場合は作業条件を置き換えて割増を計算し直す
+}
+
+// ChangesAmounts reports whether the request changes the items or the amounts of the estimate
+func (r UpdateEstimateRequest) ChangesAmounts() bool {
+	return r.Items != nil || r.Pricing != nil || r.Discount != nil || r.Conditions != nil || r.TotalLines > 0
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// DocumentStorage is where an issued PDF is archived
+type DocumentStorage string
+
+const (
+	DocumentStorageLocal       DocumentStorage = "local"
+	DocumentStorageGoogleDrive DocumentStorage = "google_drive"
+)
+
+// EstimateDocument records an issued estimate PDF and where the original is archived
+type EstimateDocument struct {
+	ID         uint            `json:"id"`
+	EstimateID uint            `json:"estimate_id"`
+	Revision   int             `json:"revision"`
+	FileName   string          `json:"file_name"`
+	Storage    DocumentStorage `json:"storage"`
+	Location   string          `json:"location"` // ローカルのパスまたはDriveのファイルID
+	CreatedAt  time.Time       `json:"created_at"`
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Estimate list sort keys
+const (
+	EstimateSortCreatedAt  = "created_at"
+	EstimateSortTotal      = "total"
+	EstimateSortCustomer   = "customer_name"
+	EstimateSortEstimateNo = "estimate_no"
+)
+
+// Estimate list page sizes
+const (
+	DefaultEstimatePageSize = 20
+	MaxEstimatePageSize     = 100
+)
+
+// EstimateListQuery represents the query parameters of GET /api/v1/estimates/
+type EstimateListQuery struct {
+	Status      string   `form:"status"`       // カンマ区切りで複数指定可 (例: sent,accepted)
+	CreatedFrom string   `form:"created_from"` // 作成日の開始 (YYYY-MM-DD, JST)
+	CreatedTo   string   `form:"created_to"`   // 作成日の終了 (YYYY-MM-DD, JST, 当日を含む)
+	Customer    string   `form:"customer"`     // 顧客名の部分一致
+	Phone       string   `form:"phone"`        // 電話番号の部分一致（ハイフン無視）
+	UserID      uint     `form:"user_id"`      // 担当者
+	MinTotal    *float64 `form:"min_total"`
+	MaxTotal    *float64 `form:"max_total"`
+	Sort        string   `form:"sort"`  // created_at / total / customer_name / estimate_no
+	Order       string   `form:"order"` // asc / desc
+	Cursor      string   `form:"cursor"`
+	Limit       int      `form:"limit"`
+}
+
+// EstimateFilter is the validated form of EstimateListQuery used by the repository
+type EstimateFilter struct {
+	Statuses    []EstimateStatus
+	CreatedFrom *time.Time // inclusive
+	CreatedTo   *time.Time // exclusive
+	Customer    string
+	Phone       string
+	UserID      uint
+	MinTotal    *float64
+	MaxTotal    *float64
+	Sort        string
+	Descending  bool
+	Cursor      string
+	Limit       int
+}
+
+// EstimatePage is a page of estimates returned by a cursor query
+type EstimatePage struct {
+	Estimates  []Estimate `json:"estimates"`
+	Total      int        `json:"total"` // 条件に一致する件数
+	NextCursor string     `json:"next_cursor,omitempty"`
+	HasMore    bool       `json:"has_more"`
+}
+
+// ToFilter validates the query parameters and converts them into an EstimateFilter
+func (q EstimateListQuery) ToFilter(loc *time.Location) (EstimateFilter, error) {
+	filter := EstimateFilter{
+		Customer: strings.TrimSpace(q.Customer),
+		Phone:    strings.ReplaceAll(strings.TrimSpace(q.Phone), "-", ""),
+		UserID:   q.UserID,
+		MinTotal: q.MinTotal,
+		MaxTotal: q.MaxTotal,
+		Cursor:   q.Cursor,
+		Limit:    q.Limit,
+	}
+
+	if q.Status != "" {
+		for _, s := range strings.Split(q.Status, ",") {
+			status := EstimateStatus(strings.TrimSpace(s))
+			if !status.IsValid() {
+				return filter, fmt.Errorf("unknown status: %s", status)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	if q.CreatedFrom != "" {
+		from, err := time.ParseInLocation("2006-01-02", q.CreatedFrom, loc)
+		if err != nil {
+			return filter, fmt.Errorf("created_from must be YYYY-MM-DD")
+		}
+		filter.CreatedFrom = &from
+	}
+	if q.CreatedTo != "" {
+		to, err := time.ParseInLocation("2006-01-02", q.CreatedTo, loc)
+		if err != nil {
+			return filter, fmt.Errorf("created_to must be YYYY-MM-DD")
+		}
+		to = to.AddDate(0, 0, 1)
+		filter.CreatedTo = &to
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return filter, fmt.Errorf("created_from must not be after created_to")
+	}
+
+	if filter.MinTotal != nil && filter.MaxTotal != nil && *filter.MinTotal > *filter.MaxTotal {
+		return filter, fmt.Errorf("min_total must not be greater than max_total")
+	}
+
+	switch q.Sort {
+	case "":
+		filter.Sort = EstimateSortCreatedAt
+	case EstimateSortCreatedAt, EstimateSortTotal, EstimateSortCustomer, EstimateSortEstimateNo:
+		filter.Sort = q.Sort
+	default:
+		return filter, fmt.Errorf("unknown sort: %s", q.Sort)
+	}
+
+	switch strings.ToLower(q.Order) {
+	case "", "desc":
+		filter.Descending = true
+	case "asc":
+		filter.Descending = false
+	default:
+		return filter, fmt.Errorf("order must be asc or desc")
+	}
+
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = DefaultEstimatePageSize
+	case filter.Limit > MaxEstimatePageSize:
+		filter.Limit = MaxEstimatePageSize
+	}
+
+	return filter, nil
+}
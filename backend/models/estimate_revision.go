@@ -0,0 +1,204 @@
+package models
+
+import (
+	"time"
+)
+
+// EstimateSnapshot is the immutable content of an estimate at a given revision
+type EstimateSnapshot struct {
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	Customer       EstimateCustomer `json:"customer"`
+	Items          []EstimateItem   `json:"items"`
+	SubTotal       float64          `json:"sub_total"`
+	TaxRate        float64          `json:"tax_rate"`
+	Tax            float64          `json:"tax"`
+	Total          float64          `json:"total"`
+	Pricing        PricingMode      `json:"pricing"`
+	Discount       Discount         `json:"discount"`
+	DiscountAmount float64          `json:"discount_amount"`
+	Conditions     JobConditions    `json:"conditions"`
+}
+
+// Snapshot captures the current content of the estimate
+func (e *Estimate) Snapshot() EstimateSnapshot {
+	items := make([]EstimateItem, len(e.Items))
+	copy(items, e.Items)
+
+	return EstimateSnapshot{
+		Title:          e.Title,
+		Description:    e.Description,
+		Customer:       e.Customer,
+		Items:          items,
+		SubTotal:       e.SubTotal,
+		TaxRate:        e.TaxRate,
+		Tax:            e.Tax,
+		Total:          e.TotalCost,
+		Pricing:        e.Pricing,
+		Discount:       e.Discount,
+		DiscountAmount: e.DiscountAmount,
+		Conditions:     e.Conditions,
+	}
+}
+
+// EstimateRevision is a stored revision of an estimate (第N版)
+type EstimateRevision struct {
+	ID         uint             `json:"id"`
+	EstimateID uint             `json:"estimate_id"`
+	Revision   int              `json:"revision"`
+	Snapshot   EstimateSnapshot `json:"snapshot"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// Item change types used in EstimateItemChange
+const (
+	ItemChangeAdded    = "added"
+	ItemChangeRemoved  = "removed"
+	ItemChangeModified = "modified"
+)
+
+// FieldChange describes a single changed field between two revisions
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// EstimateItemChange describes an added, removed or modified line between two revisions
+type EstimateItemChange struct {
+	Type    string        `json:"type"`
+	Item    string        `json:"item"`
+	From    *EstimateItem `json:"from,omitempty"`
+	To      *EstimateItem `json:"to,omitempty"`
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// EstimateRevisionDiff is the structured difference between two revisions
+type EstimateRevisionDiff struct {
+	EstimateID   uint                 `json:"estimate_id"`
+	FromRevision int                  `json:"from_revision"`
+	ToRevision   int                  `json:"to_revision"`
+	Fields       []FieldChange        `json:"fields"`
+	Items        []EstimateItemChange `json:"items"`
+}
+
+// DiffRevisions compares two revisions of the same estimate
+func DiffRevisions(from, to *EstimateRevision) EstimateRevisionDiff {
+	return EstimateRevisionDiff{
+		EstimateID:   to.EstimateID,
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		Fields:       diffSnapshotFields(from.Snapshot, to.Snapshot),
+		Items:        diffItems(from.Snapshot.Items, to.Snapshot.Items),
+	}
+}
+
+func diffSnapshotFields(from, to EstimateSnapshot) []FieldChange {
+	changes := []FieldChange{}
+	changes = appendFieldChange(changes, "title", from.Title, to.Title)
+	changes = appendFieldChange(changes, "description", from.Description, to.Description)
+	changes = appendFieldChange(changes, "customer.name", from.Customer.Name, to.Customer.Name)
+	changes = appendFieldChange(changes, "customer.address", from.Customer.Address, to.Customer.Address)
+	changes = appendFieldChange(changes, "customer.phone", from.Customer.Phone, to.Customer.Phone)
+	changes = appendFieldChange(changes, "customer.email", from.Customer.Email, to.Customer.Email)
+	changes = appendFieldChange(changes, "customer.disposal_date", from.Customer.DisposalDate, to.Customer.DisposalDate)
+	changes = appendFieldChange(changes, "sub_total", from.SubTotal, to.SubTotal)
+	changes = appendFieldChange(changes, "tax_rate", from.TaxRate, to.TaxRate)
+	changes = appendFieldChange(changes, "tax", from.Tax, to.Tax)
+	changes = appendFieldChange(changes, "total", from.Total, to.Total)
+	changes = appendFieldChange(changes, "pricing", from.Pricing.OrDefault(), to.Pricing.OrDefault())
+	changes = appendFieldChange(changes, "discount", from.Discount, to.Discount)
+	changes = appendFieldChange(changes, "discount_amount", from.DiscountAmount, to.DiscountAmount)
+	changes = appendFieldChange(changes, "conditions", from.Conditions, to.Conditions)
+
+	return changes
+}
+
+func diffItemFields(from, to EstimateItem) []FieldChange {
+	changes := []FieldChange{}
+	changes = appendFieldChange(changes, "description", from.Description, to.Description)
+	changes = appendFieldChange(changes, "category", from.Category, to.Category)
+	changes = appendFieldChange(changes, "specification", from.Specification, to.Specification)
+	changes = appendFieldChange(changes, "quantity", from.Quantity, to.Quantity)
+	changes = appendFieldChange(changes, "unit", from.Unit, to.Unit)
+	changes = appendFieldChange(changes, "unit_price", from.UnitPrice, to.UnitPrice)
+	changes = appendFieldChange(changes, "discount", from.Discount, to.Discount)
+	changes = appendFieldChange(changes, "amount", from.Amount, to.Amount)
+	changes = appendFieldChange(changes, "tax_category", from.TaxCategory.OrDefault(), to.TaxCategory.OrDefault())
+	changes = appendFieldChange(changes, "recycling", recyclingFields(from.Recycling), recyclingFields(to.Recycling))
+
+	return changes
+}
+
+// recyclingContent is the part of ApplianceRecycling that belongs to the estimate content.
+// Ticket numbers are field records and do not create revisions.
+type recyclingContent struct {
+	Class        ApplianceClass     `json:"class"`
+	Manufacturer string             `json:"manufacturer"`
+	SizeClass    ApplianceSizeClass `json:"size_class"`
+}
+
+// recyclingFields returns the comparable recycling content of a line, or nil for other lines
+func recyclingFields(recycling *ApplianceRecycling) interface{} {
+	if recycling == nil {
+		return nil
+	}
+	return recyclingContent{Class: recycling.Class, Manufacturer: recycling.Manufacturer, SizeClass: recycling.SizeClass}
+}
+
+// appendFieldChange appends a FieldChange when the two values differ
+func appendFieldChange(changes []FieldChange, field string, from, to interface{}) []FieldChange {
+	if from == to {
+		return changes
+	}
+	return append(changes, FieldChange{Field: field, From: from, To: to})
+}
+
+// itemKey identifies a line across revisions: catalog ID, or the description for free-text lines
+func itemKey(item EstimateItem) string {
+	if item.ItemID != "" {
+		return item.ItemID
+	}
+	return item.Description
+}
+
+// diffItems matches lines by itemKey in order of appearance and reports the differences
+func diffItems(from, to []EstimateItem) []EstimateItemChange {
+	changes := []EstimateItemChange{}
+	matched := make([]bool, len(to))
+
+	for i := range from {
+		old := from[i]
+		found := -1
+		for j := range to {
+			if !matched[j] && itemKey(to[j]) == itemKey(old) {
+				found = j
+				break
+			}
+		}
+
+		if found < 0 {
+			changes = append(changes, EstimateItemChange{Type: ItemChangeRemoved, Item: itemKey(old), From: &from[i]})
+			continue
+		}
+
+		matched[found] = true
+		if fields := diffItemFields(old, to[found]); len(fields) > 0 {
+			changes = append(changes, EstimateItemChange{
+				Type:    ItemChangeModified,
+				Item:    itemKey(old),
+				From:    &from[i],
+				To:      &to[found],
+				Changes: fields,
+			})
+		}
+	}
+
+	for j := range to {
+		if !matched[j] {
+			changes = append(changes, EstimateItemChange{Type: ItemChangeAdded, Item: itemKey(to[j]), To: &to[j]})
+		}
+	}
+
+	return changes
+}
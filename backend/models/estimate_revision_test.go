@@ -0,0 +1,31 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffRevisionsItemTaxAndRecycling(t *testing.T) {
+	tv := EstimateItem{ItemID: "tv", Description: "テレビ", Quantity: 1, UnitPrice: 3000,
+		Recycling: &ApplianceRecycling{Class: ApplianceTV, SizeClass: ApplianceSizeLarge, TicketNumbers: []string{"1000000000001"}}}
+	rice := EstimateItem{Description: "お米", Quantity: 1, UnitPrice: 1000}
+	from := &EstimateRevision{Revision: 1, Snapshot: EstimateSnapshot{Items: []EstimateItem{tv, rice}}}
+
+	// 税区分の既定値とリサイクル券番号の違いは差分にならない
+	tv.Recycling = &ApplianceRecycling{Class: ApplianceTV, SizeClass: ApplianceSizeLarge}
+	rice.TaxCategory = TaxCategoryStandard
+	to := &EstimateRevision{Revision: 2, Snapshot: EstimateSnapshot{Items: []EstimateItem{tv, rice}}}
+	assert.Empty(t, DiffRevisions(from, to).Items)
+
+	tv.Recycling = &ApplianceRecycling{Class: ApplianceTV, Manufacturer: "シャープ", SizeClass: ApplianceSizeSmall}
+	rice.TaxCategory = TaxCategoryReduced
+	to = &EstimateRevision{Revision: 3, Snapshot: EstimateSnapshot{Items: []EstimateItem{tv, rice}}}
+	diff := DiffRevisions(from, to)
+	require.Len(t, diff.Items, 2)
+	require.Len(t, diff.Items[0].Changes, 1)
+	assert.Equal(t, "recycling", diff.Items[0].Changes[0].Field)
+	assert.Equal(t, recyclingContent{Class: ApplianceTV, Manufacturer: "シャープ", SizeClass: ApplianceSizeSmall}, diff.Items[0].Changes[0].To)
+	assert.Equal(t, []FieldChange{{Field: "tax_category", From: TaxCategoryStandard, To: TaxCategoryReduced}}, diff.Items[1].Changes)
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// EstimateStatus represents a stage in the estimate lifecycle
+type EstimateStatus string
+
+const (
+	EstimateStatusDraft     EstimateStatus = "draft"     // 下書き
+	EstimateStatusSent      EstimateStatus = "sent"      // 提出済み
+	EstimateStatusAccepted  EstimateStatus = "accepted"  // 受注
+	EstimateStatusDeclined  EstimateStatus = "declined"  // 失注
+	EstimateStatusScheduled EstimateStatus = "scheduled" // 日程確定
+	EstimateStatusCollected EstimateStatus = "collected" // 回収済み
+	EstimateStatusInvoiced  EstimateStatus = "invoiced"  // 請求済み
+	EstimateStatusClosed    EstimateStatus = "closed"    // 完了
+	EstimateStatusCancelled EstimateStatus = "cancelled" // キャンセル
+	EstimateStatusExpired   EstimateStatus = "expired"   // 期限切れ
+)
+
+// estimateTransitions defines the allowed moves from each status
+var estimateTransitions = map[EstimateStatus][]EstimateStatus{
+	EstimateStatusDraft:     {EstimateStatusSent, EstimateStatusCancelled},
+	EstimateStatusSent:      {EstimateStatusAccepted, EstimateStatusDeclined, EstimateStatusExpired, EstimateStatusCancelled},
+	EstimateStatusAccepted:  {EstimateStatusScheduled, EstimateStatusCancelled},
+	EstimateStatusDeclined:  {EstimateStatusDraft},
+	EstimateStatusScheduled: {EstimateStatusCollected, EstimateStatusCancelled},
+	EstimateStatusCollected: {EstimateStatusInvoiced},
+	EstimateStatusInvoiced:  {EstimateStatusClosed},
+	EstimateStatusClosed:    {},
+	EstimateStatusCancelled: {},
+	EstimateStatusExpired:   {EstimateStatusDraft},
+}
+
+// IsValid reports whether s is a known status
+func (s EstimateStatus) IsValid() bool {
+	_, ok := estimateTransitions[s]
+	return ok
+}
+
+// NextStatuses returns the statuses reachable from s
+func (s EstimateStatus) NextStatuses() []EstimateStatus {
+	return estimateTransitions[s]
+}
+
+// CanTransitionTo reports whether moving from s to next is allowed
+func (s EstimateStatus) CanTransitionTo(next EstimateStatus) bool {
+	for _, allowed := range estimateTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ItemEditableStatuses are the statuses in which the items and amounts of an estimate may be changed.
+// 受注後は顧客が承諾した金額を変えず、追加分は請求書の追加明細で扱う
+var ItemEditableStatuses = []EstimateStatus{EstimateStatusDraft, EstimateStatusSent}
+
+// CanEditItems reports whether the items and amounts of an estimate may be changed in status s
+func (s EstimateStatus) CanEditItems() bool {
+	for _, status := range ItemEditableStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanIssueInstruction reports whether a work instruction sheet may be issued in status s.
+// 受注後から回収完了までの見積もりのみ作業指示書を発行できる
+func (s EstimateStatus) CanIssueInstruction() bool {
+	switch s {
+	case EstimateStatusAccepted, EstimateStatusScheduled, EstimateStatusCollected:
+		return true
+	}
+	return false
+}
+
+// CanIssueInvoice reports whether an invoice may be issued in status s.
+// 回収済みの見積もりに発行し、請求済みの見積もりには再発行できる
+func (s EstimateStatus) CanIssueInvoice() bool {
+	return s == EstimateStatusCollected || s == EstimateStatusInvoiced
+}
+
+// TransitionError is returned when an illegal status move is requested
+type TransitionError struct {
+	From EstimateStatus
+	To   EstimateStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot transition estimate from %q to %q", e.From, e.To)
+}
+
+// EstimateTransition records a single status change of an estimate
+type EstimateTransition struct {
+	ID             uint           `json:"id"`
+	EstimateID     uint           `json:"estimate_id"`
+	FromStatus     EstimateStatus `json:"from_status"`
+	ToStatus       EstimateStatus `json:"to_status"`
+	Note           string         `json:"note,omitempty"`
+	TransitionedAt time.Time      `json:"transitioned_at"`
+}
+
+// TransitionEstimateRequest represents the request structure for changing an estimate status
+type TransitionEstimateRequest struct {
+	Status EstimateStatus `json:"status" binding:"required"`
+	Note   string         `json:"note"`
+}
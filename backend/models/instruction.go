@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Instruction is a stored work instruction sheet (作業指示書) issued from an estimate
+type Instruction struct {
+	ID            uint            `json:"id"`
+	InstructionNo string          `json:"instruction_no"`
+	EstimateID    uint            `json:"estimate_id"`
+	Content       PDFInstruction  `json:"content"`
+	FileName      string          `json:"file_name"`
+	Storage       DocumentStorage `json:"storage"`
+	Location      string          `json:"location"` // ローカルのパスまたはDriveのファイルID
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// CreateInstructionRequest holds the fields filled in by office staff when
+// converting an estimate into an instruction sheet. All fields are optional.
+type CreateInstructionRequest struct {
+	AcceptedBy     string `json:"accepted_by"`     // 受付者
+	CollectionDate string `json:"collection_date"` // 収集日（省略時は見積もりの処分希望日）
+	Memo           string `json:"memo"`            // メモ（省略時は見積もりの説明）
+}
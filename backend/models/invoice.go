@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// DefaultPaymentTermDays is the number of days from the issue date to the payment due date
+const DefaultPaymentTermDays = 30
+
+// BankAccount is the transfer destination printed on invoices (お振込先)
+type BankAccount struct {
+	BankName      string `json:"bank_name"`      // 銀行名
+	BranchName    string `json:"branch_name"`    // 支店名
+	AccountType   string `json:"account_type"`   // 普通 / 当座
+	AccountNumber string `json:"account_number"` // 口座番号
+	AccountHolder string `json:"account_holder"` // 口座名義（カナ）
+}
+
+// InvoiceSettings holds the issuer settings used for every invoice
+type InvoiceSettings struct {
+	RegistrationNumber string // 適格請求書発行事業者登録番号 (T + 13桁)
+	Bank               BankAccount
+	PaymentTermDays    int
+}
+
+// Invoice is a stored invoice (請求書) issued for a completed job
+type Invoice struct {
+	ID                 uint             `json:"id"`
+	InvoiceNo          string           `json:"invoice_no"`
+	EstimateID         uint             `json:"estimate_id"`
+	EstimateNo         string           `json:"estimate_no"`
+	IssueDate          time.Time        `json:"issue_date"`
+	DueDate            time.Time        `json:"due_date"`      // お支払期限
+	WorkDate           string           `json:"work_date"`     // 作業日
+	Customer           EstimateCustomer `json:"customer"`      // 請求先
+	Items              []EstimateItem   `json:"items"`         // 見積明細＋追加明細
+	ActualWeight       float64          `json:"actual_weight"` // 実績重量 (kg)
+	SubTotal           float64          `json:"sub_total"`
+	TaxRate            float64          `json:"tax_rate"`
+	Tax                float64          `json:"tax"`
+	Total              float64          `json:"total"`
+	TaxBreakdown       []TaxBreakdown   `json:"tax_breakdown"` // 税区分ごとの対価の額と消費税額
+	Pricing            PricingMode      `json:"pricing"`       // 見積もりから引き継いだ計算方法
+	Discount           Discount         `json:"discount"`      // 見積もりから引き継いだ書類全体の値引き
+	DiscountAmount     float64          `json:"discount_amount"`
+	RegistrationNumber string           `json:"registration_number"`
+	Bank               BankAccount      `json:"bank"`
+	Remarks            []string         `json:"remarks"`
+	FileName           string           `json:"file_name"`
+	Storage            DocumentStorage  `json:"storage"`
+	Location           string           `json:"location"` // ローカルのパスまたはDriveのファイルID
+	CreatedAt          time.Time        `json:"created_at"`
+}
+
+// Recalculate recomputes line amounts and totals from the items
+func (inv *Invoice) Recalculate() {
+	for i := range inv.Items {
+		inv.Items[i].Amount = inv.Items[i].NetAmount()
+		inv.Items[i].TaxCategory = inv.Items[i].TaxCategory.OrDefault()
+	}
+
+	inv.DiscountAmount = inv.Discount.Amount(DiscountBase(inv.Items))
+	inv.SubTotal, inv.TaxBreakdown, inv.Tax = CalculateTax(WithDiscountLines(inv.Items, inv.Discount, inv.DiscountAmount), inv.TaxRate, inv.Pricing)
+	inv.Total = inv.Pricing.Total(inv.SubTotal, inv.Tax)
+}
+
+// CreateInvoiceRequest holds the actual field results of a completed job
+type CreateInvoiceRequest struct {
+	ActualWeight float64        `json:"actual_weight" binding:"min=0"` // 実績重量 (kg)
+	WorkDate     string         `json:"work_date"`                     // 作業日（省略時は見積もりの処分希望日）
+	ExtraItems   []EstimateItem `json:"extra_items" binding:"dive"`    // 現場で追加になった明細
+	DueDate      string         `json:"due_date"`                      // お支払期限 YYYY-MM-DD（省略時は発行日から規定日数後）
+}
+
+// PDFInvoice represents the invoice data structure for PDF generation
+type PDFInvoice struct {
+	InvoiceNo    string          `json:"invoice_no"`
+	EstimateNo   string          `json:"estimate_no"`
+	IssueDate    time.Time       `json:"issue_date"`
+	DueDate      time.Time       `json:"due_date"`
+	WorkDate     string          `json:"work_date"`
+	Customer     PDFCustomerInfo `json:"customer"`
+	Recipient    string          `json:"recipient"` // 佐藤 様
+	ActualWeight float64         `json:"actual_weight"`
+	Items        []PDFLineItem   `json:"items"`
+	SubTotal     float64         `json:"sub_total"`
+	TaxRate      float64         `json:"tax_rate"`
+	Tax          float64         `json:"tax"`
+	Total        float64         `json:"total"`
+	TaxBreakdown []TaxBreakdown  `json:"tax_breakdown"`
+	Pricing      PricingMode     `json:"pricing"`
+	Issuer       PDFCompanyInfo  `json:"issuer"`
+	Bank         BankAccount     `json:"bank"`
+	Remarks      []string        `json:"remarks"`
+}
@@ -0,0 +1,292 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ManifestCopy is a copy of the industrial waste manifest (産業廃棄物管理票) returned to the discharger
+type ManifestCopy string
+
+const (
+	ManifestCopyB2 ManifestCopy = "B2" // 運搬終了の写し
+	ManifestCopyD  ManifestCopy = "D"  // 処分終了の写し
+	ManifestCopyE  ManifestCopy = "E"  // 最終処分終了の写し
+)
+
+// ManifestCopies lists the returned copies in the order the stages complete
+var ManifestCopies = []ManifestCopy{ManifestCopyB2, ManifestCopyD, ManifestCopyE}
+
+// ManifestReportDays is the number of days after a missed return deadline within which
+// the discharger must report to the prefecture (措置内容等報告書)
+const ManifestReportDays = 30
+
+// IsValid reports whether c is a returned copy
+func (c ManifestCopy) IsValid() bool {
+	for _, copy := range ManifestCopies {
+		if c == copy {
+			return true
+		}
+	}
+	return false
+}
+
+// Label returns the name of the copy, e.g. B2票（運搬終了）
+func (c ManifestCopy) Label() string {
+	switch c {
+	case ManifestCopyB2:
+		return "B2票（運搬終了）"
+	case ManifestCopyD:
+		return "D票（処分終了）"
+	case ManifestCopyE:
+		return "E票（最終処分終了）"
+	}
+	return string(c)
+}
+
+// ReturnDays returns the number of days from the issue date within which the copy must
+// be returned. B2 and D are shortened for specially controlled industrial waste.
+func (c ManifestCopy) ReturnDays(specialControl bool) int {
+	switch c {
+	case ManifestCopyB2, ManifestCopyD:
+		if specialControl {
+			return 60
+		}
+		return 90
+	}
+	return 180
+}
+
+// ManifestStatus is the progress of a manifest derived from the returned copies
+type ManifestStatus string
+
+const (
+	ManifestStatusIssued      ManifestStatus = "issued"      // 交付済み
+	ManifestStatusTransported ManifestStatus = "transported" // 運搬終了（B2票受領）
+	ManifestStatusDisposed    ManifestStatus = "disposed"    // 処分終了（D票受領）
+	ManifestStatusCompleted   ManifestStatus = "completed"   // 最終処分終了（E票受領）
+)
+
+// ManifestStage is the return of one copy of a manifest
+type ManifestStage struct {
+	Copy        ManifestCopy `json:"copy"`
+	DueDate     string       `json:"due_date"`     // 返送期限 YYYY-MM-DD
+	CompletedOn string       `json:"completed_on"` // 写しに記載された終了年月日
+	ReturnedOn  string       `json:"returned_on"`  // 写しを受け取った日（未返送は空）
+	Overdue     bool         `json:"overdue"`      // 期限を過ぎても返送されていない
+}
+
+// Manifest is an industrial waste manifest issued for a job of a corporate customer
+type Manifest struct {
+	ID             uint            `json:"id"`
+	ManifestNo     string          `json:"manifest_no"` // 交付番号
+	InstructionID  uint            `json:"instruction_id"`
+	EstimateID     uint            `json:"estimate_id"`
+	IssueDate      string          `json:"issue_date"`      // 交付年月日 YYYY-MM-DD
+	Discharger     string          `json:"discharger"`      // 排出事業者の氏名または名称
+	SiteName       string          `json:"site_name"`       // 排出事業場の名称
+	SiteAddress    string          `json:"site_address"`    // 排出事業場の所在地
+	WasteType      string          `json:"waste_type"`      // 産業廃棄物の種類（例: 廃プラスチック類）
+	Quantity       float64         `json:"quantity"`        // 数量
+	Unit           string          `json:"unit"`            // t / m3 / kg
+	PackingForm    string          `json:"packing_form"`    // 荷姿
+	Transporter    string          `json:"transporter"`     // 収集運搬業者
+	Disposer       string          `json:"disposer"`        // 処分業者
+	DisposalMethod string          `json:"disposal_method"` // 処分方法
+	SpecialControl bool            `json:"special_control"` // 特別管理産業廃棄物
+	Stages         []ManifestStage `json:"stages"`          // B2・D・E票の返送状況
+	Status         ManifestStatus  `json:"status"`
+	Overdue        bool            `json:"overdue"` // 返送期限を過ぎた写しがある
+	Note           string          `json:"note"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// CreateManifestRequest holds the entries of a manifest issued for an instruction sheet.
+// Omitted discharger and site fields are taken from the customer of the estimate.
+type CreateManifestRequest struct {
+	ManifestNo     string  `json:"manifest_no" binding:"required"`
+	IssueDate      string  `json:"issue_date"` // 省略時は当日
+	Discharger     string  `json:"discharger"`
+	SiteName       string  `json:"site_name"`
+	SiteAddress    string  `json:"site_address"`
+	WasteType      string  `json:"waste_type" binding:"required"`
+	Quantity       float64 `json:"quantity" binding:"min=0"`
+	Unit           string  `json:"unit"`
+	PackingForm    string  `json:"packing_form"`
+	Transporter    string  `json:"transporter"`
+	Disposer       string  `json:"disposer"`
+	DisposalMethod string  `json:"disposal_method"`
+	SpecialControl bool    `json:"special_control"`
+	Note           string  `json:"note"`
+}
+
+// RecordManifestReturnRequest records the return of a copy
+type RecordManifestReturnRequest struct {
+	Copy        ManifestCopy `json:"copy" binding:"required,oneof=B2 D E"`
+	CompletedOn string       `json:"completed_on" binding:"required"` // 写しに記載された終了年月日
+	ReturnedOn  string       `json:"returned_on"`                     // 省略時は当日
+}
+
+// ManifestAlert is a copy that is overdue or due soon
+type ManifestAlert struct {
+	ManifestID   uint         `json:"manifest_id"`
+	ManifestNo   string       `json:"manifest_no"`
+	EstimateID   uint         `json:"estimate_id"`
+	Discharger   string       `json:"discharger"`
+	Copy         ManifestCopy `json:"copy"`
+	Label        string       `json:"label"`
+	DueDate      string       `json:"due_date"`
+	DaysLeft     int          `json:"days_left"` // 期限までの日数（超過は負数）
+	Overdue      bool         `json:"overdue"`
+	ReportByDate string       `json:"report_by_date"` // 都道府県知事への報告期限
+}
+
+// ManifestListQuery holds the query parameters of the manifest list and export
+type ManifestListQuery struct {
+	Status     string `form:"status"`      // issued / transported / disposed / completed
+	EstimateID uint   `form:"estimate_id"` // 見積もりID
+	IssuedFrom string `form:"issued_from"` // 交付日の開始 (YYYY-MM-DD)
+	IssuedTo   string `form:"issued_to"`   // 交付日の終了 (YYYY-MM-DD, 当日を含む)
+	Overdue    bool   `form:"overdue"`     // 返送期限を過ぎた写しがあるものだけ
+}
+
+// ManifestFilter narrows the manifests to list
+type ManifestFilter struct {
+	Status     ManifestStatus
+	EstimateID uint
+	IssuedFrom string // YYYY-MM-DD
+	IssuedTo   string // YYYY-MM-DD, 当日を含む
+}
+
+// ToFilter validates the query parameters and converts them into a ManifestFilter
+func (q ManifestListQuery) ToFilter() (ManifestFilter, error) {
+	filter := ManifestFilter{
+		Status:     ManifestStatus(q.Status),
+		EstimateID: q.EstimateID,
+		IssuedFrom: q.IssuedFrom,
+		IssuedTo:   q.IssuedTo,
+	}
+
+	switch filter.Status {
+	case "", ManifestStatusIssued, ManifestStatusTransported, ManifestStatusDisposed, ManifestStatusCompleted:
+	default:
+		return filter, fmt.Errorf("unknown status: %s", q.Status)
+	}
+	if q.IssuedFrom != "" && !isValidDate(q.IssuedFrom) {
+		return filter, fmt.Errorf("issued_from must be YYYY-MM-DD")
+	}
+	if q.IssuedTo != "" && !isValidDate(q.IssuedTo) {
+		return filter, fmt.Errorf("issued_to must be YYYY-MM-DD")
+	}
+	if q.IssuedFrom != "" && q.IssuedTo != "" && q.IssuedFrom > q.IssuedTo {
+		return filter, fmt.Errorf("issued_from must not be after issued_to")
+	}
+	return filter, nil
+}
+
+// isValidDate reports whether s is a date in YYYY-MM-DD format
+func isValidDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
+// Validate checks the date format of the request
+func (r CreateManifestRequest) Validate() error {
+	if r.IssueDate != "" && !isValidDate(r.IssueDate) {
+		return fmt.Errorf("issue_date must be YYYY-MM-DD")
+	}
+	return nil
+}
+
+// Validate checks the dates of a returned copy against the issue date of the manifest
+func (r RecordManifestReturnRequest) Validate(issueDate string) error {
+	if !isValidDate(r.CompletedOn) {
+		return fmt.Errorf("completed_on must be YYYY-MM-DD")
+	}
+	if r.ReturnedOn != "" && !isValidDate(r.ReturnedOn) {
+		return fmt.Errorf("returned_on must be YYYY-MM-DD")
+	}
+	if r.CompletedOn < issueDate {
+		return fmt.Errorf("completed_on must not be before the issue date %s", issueDate)
+	}
+	if r.ReturnedOn != "" && r.ReturnedOn < r.CompletedOn {
+		return fmt.Errorf("returned_on must not be before completed_on")
+	}
+	return nil
+}
+
+// Evaluate fills the due dates, overdue flags and status as of today (JST date)
+func (m *Manifest) Evaluate(today time.Time) error {
+	issued, err := time.ParseInLocation("2006-01-02", m.IssueDate, today.Location())
+	if err != nil {
+		return fmt.Errorf("invalid issue date of manifest %s: %v", m.ManifestNo, err)
+	}
+	year, month, day := today.Date()
+	todayDate := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+
+	m.Status = ManifestStatusIssued
+	m.Overdue = false
+	for i := range m.Stages {
+		stage := &m.Stages[i]
+		due := issued.AddDate(0, 0, stage.Copy.ReturnDays(m.SpecialControl))
+		stage.DueDate = due.Format("2006-01-02")
+		stage.Overdue = stage.ReturnedOn == "" && todayDate.After(due)
+		m.Overdue = m.Overdue || stage.Overdue
+
+		if stage.ReturnedOn != "" {
+			switch stage.Copy {
+			case ManifestCopyB2:
+				m.Status = ManifestStatusTransported
+			case ManifestCopyD:
+				m.Status = ManifestStatusDisposed
+			case ManifestCopyE:
+				m.Status = ManifestStatusCompleted
+			}
+		}
+	}
+	return nil
+}
+
+// Alerts returns the unreturned copies that are overdue or due within the given days.
+// Evaluate must have been called first.
+func (m *Manifest) Alerts(today time.Time, withinDays int) []ManifestAlert {
+	alerts := []ManifestAlert{}
+	year, month, day := today.Date()
+	todayDate := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	for _, stage := range m.Stages {
+		if stage.ReturnedOn != "" {
+			continue
+		}
+		due, err := time.ParseInLocation("2006-01-02", stage.DueDate, today.Location())
+		if err != nil {
+			continue
+		}
+		daysLeft := int(due.Sub(todayDate).Hours() / 24)
+		if daysLeft > withinDays {
+			continue
+		}
+		alerts = append(alerts, ManifestAlert{
+			ManifestID:   m.ID,
+			ManifestNo:   m.ManifestNo,
+			EstimateID:   m.EstimateID,
+			Discharger:   m.Discharger,
+			Copy:         stage.Copy,
+			Label:        stage.Copy.Label(),
+			DueDate:      stage.DueDate,
+			DaysLeft:     daysLeft,
+			Overdue:      stage.Overdue,
+			ReportByDate: due.AddDate(0, 0, ManifestReportDays).Format("2006-01-02"),
+		})
+	}
+	return alerts
+}
+
+// NewManifestStages returns the stages of a newly issued manifest
+func NewManifestStages() []ManifestStage {
+	stages := make([]ManifestStage, len(ManifestCopies))
+	for i, copy := range ManifestCopies {
+		stages[i] = ManifestStage{Copy: copy}
+	}
+	return stages
+}
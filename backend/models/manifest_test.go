@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifestEvaluate(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	manifest := Manifest{ManifestNo: "12345678901", IssueDate: "2025-04-01", Stages: NewManifestStages()}
+
+	// 交付から90日目までは期限内
+	require.NoError(t, manifest.Evaluate(time.Date(2025, 6, 30, 23, 0, 0, 0, jst)))
+	assert.Equal(t, "2025-06-30", manifest.Stages[0].DueDate)
+	assert.Equal(t, "2025-06-30", manifest.Stages[1].DueDate)
+	assert.Equal(t, "2025-09-28", manifest.Stages[2].DueDate)
+	assert.Equal(t, ManifestStatusIssued, manifest.Status)
+	assert.False(t, manifest.Overdue)
+
+	manifest.Stages[0].CompletedOn, manifest.Stages[0].ReturnedOn = "2025-04-01", "2025-04-10"
+	today := time.Date(2025, 7, 1, 9, 0, 0, 0, jst)
+	require.NoError(t, manifest.Evaluate(today))
+	assert.Equal(t, ManifestStatusTransported, manifest.Status)
+	assert.False(t, manifest.Stages[0].Overdue)
+	assert.True(t, manifest.Stages[1].Overdue)
+	assert.True(t, manifest.Overdue)
+
+	alerts := manifest.Alerts(today, 7)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, ManifestCopyD, alerts[0].Copy)
+	assert.Equal(t, -1, alerts[0].DaysLeft)
+	assert.Equal(t, "2025-07-30", alerts[0].ReportByDate)
+	assert.Len(t, manifest.Alerts(today, 90), 2)
+
+	// 特別管理産業廃棄物はB2・D票の期限が60日
+	manifest.SpecialControl = true
+	require.NoError(t, manifest.Evaluate(today))
+	assert.Equal(t, "2025-05-31", manifest.Stages[1].DueDate)
+	assert.Equal(t, "2025-09-28", manifest.Stages[2].DueDate)
+}
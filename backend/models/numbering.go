@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// DocumentType identifies a kind of numbered document
+type DocumentType string
+
+const (
+	DocumentTypeEstimate    DocumentType = "estimate"    // 見積書
+	DocumentTypeInstruction DocumentType = "instruction" // 作業指示書
+	DocumentTypeInvoice     DocumentType = "invoice"     // 請求書
+)
+
+// ResetPeriod determines when a document sequence starts again from 1
+type ResetPeriod string
+
+const (
+	ResetDaily   ResetPeriod = "daily"
+	ResetMonthly ResetPeriod = "monthly"
+	ResetYearly  ResetPeriod = "yearly"
+	ResetNever   ResetPeriod = "never"
+)
+
+// NumberingRule describes how document numbers are formatted.
+//
+// Format supports the placeholders {yyyy}, {yy}, {mm}, {dd}, {date} (yyyymmdd)
+// and {seq} / {seq:N} (sequence zero-padded to N digits).
+type NumberingRule struct {
+	Format string      `json:"format"`
+	Reset  ResetPeriod `json:"reset"`
+}
+
+// DefaultNumberingRules are used when no rule is configured for a document type
+var DefaultNumberingRules = map[DocumentType]NumberingRule{
+	DocumentTypeEstimate:    {Format: "EST-{date}-{seq:3}", Reset: ResetDaily},
+	DocumentTypeInstruction: {Format: "INS-{date}-{seq:3}", Reset: ResetDaily},
+	DocumentTypeInvoice:     {Format: "INV-{yyyy}{mm}-{seq:4}", Reset: ResetMonthly},
+}
+
+var numberingPlaceholder = regexp.MustCompile(`\{(yyyy|yy|mm|dd|date|seq)(?::(\d+))?\}`)
+
+// Validate checks that the rule has a known reset period, a sequence placeholder and the
+// date placeholders of its reset period, so that numbers of different periods never collide:
+// the year for yearly, the year and month for monthly, and the full date for daily.
+func (r NumberingRule) Validate() error {
+	switch r.Reset {
+	case ResetDaily, ResetMonthly, ResetYearly, ResetNever:
+	default:
+		return fmt.Errorf("unknown numbering reset period: %q", r.Reset)
+	}
+
+	placeholders := map[string]bool{}
+	for _, match := range numberingPlaceholder.FindAllStringSubmatch(r.Format, -1) {
+		placeholders[match[1]] = true
+	}
+	if !placeholders["seq"] {
+		return fmt.Errorf("numbering format %q has no {seq} placeholder", r.Format)
+	}
+
+	hasYear := placeholders["yyyy"] || placeholders["yy"] || placeholders["date"]
+	hasMonth := hasYear && (placeholders["mm"] || placeholders["date"])
+	hasDay := hasMonth && (placeholders["dd"] || placeholders["date"])
+	switch {
+	case r.Reset == ResetYearly && !hasYear:
+		return fmt.Errorf("numbering format %q reset yearly needs {yyyy}, {yy} or {date}", r.Format)
+	case r.Reset == ResetMonthly && !hasMonth:
+		return fmt.Errorf("numbering format %q reset monthly needs {yyyy}{mm} or {date}", r.Format)
+	case r.Reset == ResetDaily && !hasDay:
+		return fmt.Errorf("numbering format %q reset daily needs {yyyy}{mm}{dd} or {date}", r.Format)
+	}
+	return nil
+}
+
+// PeriodKey returns the key of the sequence period that contains at
+func (r NumberingRule) PeriodKey(at time.Time) string {
+	switch r.Reset {
+	case ResetDaily:
+		return at.Format("20060102")
+	case ResetMonthly:
+		return at.Format("200601")
+	case ResetYearly:
+		return at.Format("2006")
+	default:
+		return "all"
+	}
+}
+
+// FormatNumber renders the document number for the given sequence value and date
+func (r NumberingRule) FormatNumber(seq int64, at time.Time) string {
+	return numberingPlaceholder.ReplaceAllStringFunc(r.Format, func(token string) string {
+		match := numberingPlaceholder.FindStringSubmatch(token)
+		switch match[1] {
+		case "yyyy":
+			return at.Format("2006")
+		case "yy":
+			return at.Format("06")
+		case "mm":
+			return at.Format("01")
+		case "dd":
+			return at.Format("02")
+		case "date":
+			return at.Format("20060102")
+		default:
+			width, _ := strconv.Atoi(match[2])
+			return fmt.Sprintf("%0*d", width, seq)
+		}
+	})
+}
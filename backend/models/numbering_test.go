@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberingRuleValidate(t *testing.T) {
+	tests := []struct {
+		format string
+		reset  ResetPeriod
+		valid  bool
+	}{
+		{"EST-{date}-{seq:3}", ResetDaily, true},
+		{"EST-{yyyy}{mm}{dd}-{seq:3}", ResetDaily, true},
+		{"EST-{yy}{mm}{dd}-{seq}", ResetDaily, true},
+		{"EST-{yyyy}{mm}-{seq:3}", ResetDaily, false},
+		{"EST-{mm}{dd}-{seq:3}", ResetDaily, false},
+		{"INV-{yyyy}{mm}-{seq:4}", ResetMonthly, true},
+		{"INV-{date}-{seq:4}", ResetMonthly, true},
+		{"INV-{mm}-{seq:4}", ResetMonthly, false},
+		{"INV-{yyyy}-{seq:4}", ResetMonthly, false},
+		{"INS-{yyyy}-{seq:5}", ResetYearly, true},
+		{"INS-{seq:5}", ResetYearly, false},
+		{"INS-{seq:8}", ResetNever, true},
+		{"INS-{date}", ResetNever, false},
+		{"INS-{date}-{seq}", "weekly", false},
+	}
+	for _, tt := range tests {
+		err := NumberingRule{Format: tt.format, Reset: tt.reset}.Validate()
+		if tt.valid {
+			assert.NoError(t, err, "%s (%s)", tt.format, tt.reset)
+		} else {
+			assert.Error(t, err, "%s (%s)", tt.format, tt.reset)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import "time"
 // PDFEstimate represents the estimate/quotation data structure for PDF generation
 type PDFEstimate struct {
 	EstimateNo   string          `json:"estimate_no"`
+	Revision     int             `json:"revision"` // 版数（2以上で「第N版」を表示）
 	IssueDate    time.Time       `json:"issue_date"`
 	Customer     PDFCustomerInfo `json:"customer"`
 	Recipient    string          `json:"recipient"` // 佐藤 様
@@ -14,6 +15,8 @@ type PDFEstimate struct {
 	TaxRate      float64         `json:"tax_rate"`
 	Tax          float64         `json:"tax"`
 	Total        float64         `json:"total"`
+	TaxBreakdown []TaxBreakdown  `json:"tax_breakdown"` // 税区分ごとの対価の額と消費税額
+	Pricing      PricingMode     `json:"pricing"`       // 税込/税抜の表示
 	Remarks      []string        `json:"remarks"`
 	ValidPeriod  int             `json:"valid_period"` // days
 	PaymentTerms string          `json:"payment_terms"`
@@ -31,20 +34,24 @@ type PDFCustomerInfo struct {
 
 // PDFLineItem represents each item in the estimate PDF
 type PDFLineItem struct {
-	Description   string  `json:"description"`
-	Specification string  `json:"specification"`
-	Quantity      float64 `json:"quantity"`
-	Unit          string  `json:"unit"`
-	UnitPrice     float64 `json:"unit_price"`
-	Amount        float64 `json:"amount"`
+	Description    string      `json:"description"`
+	Category       string      `json:"category"` // カタログのカテゴリー名（備考欄に印字）
+	Specification  string      `json:"specification"`
+	Quantity       float64     `json:"quantity"`
+	Unit           string      `json:"unit"`
+	UnitPrice      float64     `json:"unit_price"`
+	DiscountAmount float64     `json:"discount_amount"` // 明細の値引き額（備考欄に印字）
+	Amount         float64     `json:"amount"`
+	TaxCategory    TaxCategory `json:"tax_category"` // 標準税率以外は区分の記号を付けて印字
 }
 
 // PDFCompanyInfo represents the issuing company information
 type PDFCompanyInfo struct {
-	CompanyName string `json:"company_name"`
-	PostalCode  string `json:"postal_code"`
-	Address     string `json:"address"`
-	Tel         string `json:"tel"`
-	Fax         string `json:"fax"`
-	Seal        string `json:"seal"` // path to seal image
+	CompanyName        string `json:"company_name"`
+	PostalCode         string `json:"postal_code"`
+	Address            string `json:"address"`
+	Tel                string `json:"tel"`
+	Fax                string `json:"fax"`
+	Seal               string `json:"seal"`                // path to seal image
+	RegistrationNumber string `json:"registration_number"` // 適格請求書発行事業者登録番号
 }
@@ -12,6 +12,7 @@ type PDFInstruction struct {
 	Contractor      PDFContractorInfo `json:"contractor"`       // 作業指示書 - 収集先
 	Collector       PDFCollectorInfo  `json:"collector"`        // 控 - 収集先
 	Items           []PDFWorkItem     `json:"items"`            // 作業内容
+	Vehicle         string            `json:"vehicle"`          // 車両（例: 2tトラック × 1台）
 	Memo            string            `json:"memo"`             // メモ（印刷されません）
 	WorkDetails     PDFWorkDetails    `json:"work_details"`     // 作業詳細
 }
@@ -2,9 +2,14 @@ package models
 
 // PDFEstimateRequest represents the request structure from frontend
 type PDFEstimateRequest struct {
-	Customer PDFRequestCustomer `json:"customer"`
-	Items    []PDFRequestItem   `json:"items"`
-	Images   []PDFImage         `json:"images"`
+	Customer   PDFRequestCustomer `json:"customer"`
+	Items      []PDFRequestItem   `json:"items"`
+	Images     []PDFImage         `json:"images"`
+	Pricing    PricingMode        `json:"pricing"`    // 省略時は税抜・税率ごとに切り捨て
+	Discount   Discount           `json:"discount"`   // 書類全体の値引き
+	Conditions JobConditions      `json:"conditions"` // 作業条件（割増ルールで明細を追加する）
+	// AddTransportFee adds the transport fee of the recommended vehicle as a line (収集運搬費)
+	AddTransportFee bool `json:"addTransportFee"`
 }
 
 // PDFRequestCustomer represents customer information from frontend
@@ -18,11 +23,41 @@ type PDFRequestCustomer struct {
 
 // PDFRequestItem represents each item from frontend
 type PDFRequestItem struct {
-	ID            string  `json:"id"`
-	Specification string  `json:"specification"`
-	Quantity      float64 `json:"quantity"`
-	CustomPrice   float64 `json:"customPrice"`
-	Amount        float64 `json:"amount"`
+	ID            string      `json:"id"`
+	Name          string      `json:"name"` // 自由入力の品名（other-custom のとき）
+	Specification string      `json:"specification"`
+	Quantity      float64     `json:"quantity"`
+	CustomPrice   float64     `json:"customPrice"` // 負の単価は other-custom（買取・調整）のみ
+	Discount      Discount    `json:"discount"`    // 明細の値引き
+	Amount        float64     `json:"amount"`
+	TaxCategory   TaxCategory `json:"taxCategory"` // standard / reduced / exempt / non_taxable（省略時は標準税率）
+	// Manufacturer and SizeClass select the recycling fee of recycling law appliances
+	Manufacturer string             `json:"manufacturer"`
+	SizeClass    ApplianceSizeClass `json:"sizeClass"` // small / large（テレビ・冷蔵庫のみ）
+}
+
+// PriceMismatch describes a request row whose amount differs from quantity × unit price
+type PriceMismatch struct {
+	Row       int     `json:"row"` // 1始まりの行番号
+	ItemID    string  `json:"item_id"`
+	Quantity  float64 `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"` // 指定単価、または未指定時のカタログ単価
+	Amount    float64 `json:"amount"`     // リクエストの金額
+	Expected  float64 `json:"expected"`   // サーバーで計算した金額
+}
+
+// PDFEstimatePreview is the server-side calculation of a PDF request
+type PDFEstimatePreview struct {
+	Items          []EstimateItem  `json:"items"`
+	DiscountAmount float64         `json:"discount_amount"` // 書類全体の値引き額
+	SubTotal       float64         `json:"sub_total"`
+	TaxRate        float64         `json:"tax_rate"`
+	Tax            float64         `json:"tax"`
+	Total          float64         `json:"total"`
+	TaxBreakdown   []TaxBreakdown  `json:"tax_breakdown"`
+	Pricing        PricingMode     `json:"pricing"`
+	Mismatches     []PriceMismatch `json:"mismatches"` // 金額が一致しない行（なければ空）
+	LoadPlan       LoadPlan        `json:"load_plan"`  // 合計容積・重量と推奨車両
 }
 
 // PDFImage represents image data from frontend
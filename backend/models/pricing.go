@@ -0,0 +1,98 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// PriceBasis is whether the unit prices of an estimate include consumption tax
+type PriceBasis string
+
+const (
+	PriceBasisExclusive PriceBasis = "exclusive" // 税抜価格（外税）
+	PriceBasisInclusive PriceBasis = "inclusive" // 税込価格（内税・総額表示）
+)
+
+// TaxRounding is how fractions of a yen are rounded when computing tax (端数処理)
+type TaxRounding string
+
+const (
+	TaxRoundingFloor TaxRounding = "floor" // 切り捨て
+	TaxRoundingRound TaxRounding = "round" // 四捨五入
+	TaxRoundingCeil  TaxRounding = "ceil"  // 切り上げ
+)
+
+// TaxRoundingUnit is the unit the tax is rounded on
+type TaxRoundingUnit string
+
+const (
+	TaxRoundingPerDocument TaxRoundingUnit = "document" // 書類ごと・税率ごとに1回（適格請求書の原則）
+	TaxRoundingPerLine     TaxRoundingUnit = "line"     // 明細行ごと
+)
+
+// PricingMode determines how the tax and totals of a document are calculated and labelled.
+// The zero value is tax-exclusive prices with the tax rounded down once per rate.
+type PricingMode struct {
+	PriceBasis   PriceBasis      `json:"price_basis" binding:"omitempty,oneof=exclusive inclusive"`
+	Rounding     TaxRounding     `json:"rounding" binding:"omitempty,oneof=floor round ceil"`
+	RoundingUnit TaxRoundingUnit `json:"rounding_unit" binding:"omitempty,oneof=document line"`
+}
+
+// OrDefault returns m with unset fields filled with the defaults
+func (m PricingMode) OrDefault() PricingMode {
+	if m.PriceBasis == "" {
+		m.PriceBasis = PriceBasisExclusive
+	}
+	if m.Rounding == "" {
+		m.Rounding = TaxRoundingFloor
+	}
+	if m.RoundingUnit == "" {
+		m.RoundingUnit = TaxRoundingPerDocument
+	}
+	return m
+}
+
+// ValidateForIssuer rejects a mode a registered qualified invoice issuer may not use.
+// 適格請求書では税率ごとに1回だけ端数処理するため、登録番号があるときは行ごとの丸めを認めない
+func (m PricingMode) ValidateForIssuer(registrationNumber string) error {
+	if registrationNumber != "" && m.OrDefault().RoundingUnit == TaxRoundingPerLine {
+		return fmt.Errorf("rounding_unit %q cannot be used with a registration number; tax must be rounded once per rate", TaxRoundingPerLine)
+	}
+	return nil
+}
+
+// IsInclusive reports whether the prices include consumption tax
+func (m PricingMode) IsInclusive() bool {
+	return m.PriceBasis == PriceBasisInclusive
+}
+
+// Total returns the amount to be paid for the given subtotal and tax
+func (m PricingMode) Total(subTotal, tax float64) float64 {
+	if m.IsInclusive() {
+		return subTotal
+	}
+	return subTotal + tax
+}
+
+// tax returns the tax on amount before rounding. Tax-inclusive amounts contain the tax
+// (内税), so it is extracted as amount × rate / (1 + rate).
+func (m PricingMode) tax(amount, rate float64) float64 {
+	if m.IsInclusive() {
+		return amount * rate / (1 + rate)
+	}
+	return amount * rate
+}
+
+// Apply rounds v to a whole yen
+func (r TaxRounding) Apply(v float64) float64 {
+	// 浮動小数点の誤差（999.9999… など）で端数処理が変わらないようにする
+	v = math.Round(v*1e6) / 1e6
+
+	switch r {
+	case TaxRoundingRound:
+		return math.Round(v)
+	case TaxRoundingCeil:
+		return math.Ceil(v)
+	}
+	return math.Floor(v)
+}
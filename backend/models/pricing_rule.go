@@ -0,0 +1,169 @@
+package models
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// JobConditions are the site conditions of a disposal job that affect the price
+type JobConditions struct {
+	Floor            int     `json:"floor" binding:"min=0"`             // 搬出する階（1階は1）
+	HasElevator      bool    `json:"has_elevator"`                      // エレベーターの有無
+	ParkingDistance  float64 `json:"parking_distance" binding:"min=0"`  // 駐車位置から搬出場所までの距離 (m)
+	DisassemblyCount int     `json:"disassembly_count" binding:"min=0"` // 解体が必要な点数
+	SameDay          bool    `json:"same_day"`                          // 当日対応
+	Night            bool    `json:"night"`                             // 夜間作業
+}
+
+// PricingRuleKind is the job condition a pricing rule applies to
+type PricingRuleKind string
+
+const (
+	PricingRuleFloor           PricingRuleKind = "floor"            // エレベーターなしの階段作業: 基準階を超える1階ごとに金額
+	PricingRuleParkingDistance PricingRuleKind = "parking_distance" // 横持ち: 基準距離を超える刻みごとに金額
+	PricingRuleDisassembly     PricingRuleKind = "disassembly"      // 解体: 1点ごとに金額
+	PricingRuleSameDay         PricingRuleKind = "same_day"         // 当日対応: 品目合計に対する割増率、または固定金額
+	PricingRuleNight           PricingRuleKind = "night"            // 夜間作業: 品目合計に対する割増率、または固定金額
+	PricingRuleMinimumCharge   PricingRuleKind = "minimum_charge"   // 最低料金: 合計が金額に満たない場合に差額を加算
+)
+
+// pricingRuleItemPrefix marks the item ID of lines added by pricing rules, e.g. "rule:floor"
+const pricingRuleItemPrefix = "rule:"
+
+// PricingRule is an editable rule that adds a surcharge line for a job condition
+type PricingRule struct {
+	ID          uint            `json:"id"`
+	Kind        PricingRuleKind `json:"kind"`
+	Name        string          `json:"name"`      // 明細に印字する名称
+	Unit        string          `json:"unit"`      // 明細の単位
+	Amount      float64         `json:"amount"`    // 単価、固定金額、または最低料金 (円)
+	Percent     float64         `json:"percent"`   // 割増率 (%)。当日対応・夜間のみ
+	Threshold   float64         `json:"threshold"` // 割増なしで対応できる階数・距離
+	Step        float64         `json:"step"`      // 距離の刻み (m)
+	TaxCategory TaxCategory     `json:"tax_category"`
+	Enabled     bool            `json:"enabled"`
+	SortOrder   int             `json:"sort_order"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// UpdatePricingRuleRequest holds the editable fields of a pricing rule
+type UpdatePricingRuleRequest struct {
+	Name        string      `json:"name" binding:"required"`
+	Unit        string      `json:"unit"`
+	Amount      float64     `json:"amount" binding:"min=0"`
+	Percent     float64     `json:"percent" binding:"min=0,max=1000"`
+	Threshold   float64     `json:"threshold" binding:"min=0"`
+	Step        float64     `json:"step" binding:"min=0"`
+	TaxCategory TaxCategory `json:"tax_category" binding:"omitempty,oneof=standard reduced exempt non_taxable"`
+	Enabled     bool        `json:"enabled"`
+	SortOrder   int         `json:"sort_order"`
+}
+
+// IsPricingRuleLine reports whether the line was added by a pricing rule
+func (item EstimateItem) IsPricingRuleLine() bool {
+	return strings.HasPrefix(item.ItemID, pricingRuleItemPrefix)
+}
+
+// WithoutPricingRuleLines returns the items except the lines added by pricing rules
+func WithoutPricingRuleLines(items []EstimateItem) []EstimateItem {
+	lines := []EstimateItem{}
+	for _, item := range items {
+		if !item.IsPricingRuleLine() {
+			lines = append(lines, item)
+		}
+	}
+	return lines
+}
+
+// PricingRuleLines returns the surcharge lines the enabled rules add to the items for the
+// job conditions, in rule order. Percentage surcharges are computed on the item subtotal,
+// and the minimum charge is evaluated last against the items plus all other surcharges.
+func PricingRuleLines(items []EstimateItem, conditions JobConditions, rules []PricingRule) []EstimateItem {
+	itemTotal := 0.0
+	for _, item := range items {
+		itemTotal += item.NetAmount()
+	}
+
+	lines := []EstimateItem{}
+	var minimum *PricingRule
+	for i := range rules {
+		rule := rules[i]
+		if !rule.Enabled {
+			continue
+		}
+		if rule.Kind == PricingRuleMinimumCharge {
+			minimum = &rules[i]
+			continue
+		}
+		if quantity, unitPrice := rule.charge(itemTotal, conditions); quantity > 0 && unitPrice > 0 {
+			lines = append(lines, rule.line(quantity, unitPrice))
+		}
+	}
+
+	if minimum != nil {
+		total := itemTotal
+		for _, line := range lines {
+			total += line.NetAmount()
+		}
+		if total < minimum.Amount {
+			lines = append(lines, minimum.line(1, minimum.Amount-total))
+		}
+	}
+	return lines
+}
+
+// charge returns the quantity and unit price the rule charges for the conditions
+func (r PricingRule) charge(itemTotal float64, conditions JobConditions) (float64, float64) {
+	switch r.Kind {
+	case PricingRuleFloor:
+		if conditions.HasElevator || float64(conditions.Floor) <= r.Threshold {
+			return 0, 0
+		}
+		return float64(conditions.Floor) - r.Threshold, r.Amount
+	case PricingRuleParkingDistance:
+		if conditions.ParkingDistance <= r.Threshold {
+			return 0, 0
+		}
+		step := r.Step
+		if step <= 0 {
+			step = 1
+		}
+		return math.Ceil((conditions.ParkingDistance - r.Threshold) / step), r.Amount
+	case PricingRuleDisassembly:
+		return float64(conditions.DisassemblyCount), r.Amount
+	case PricingRuleSameDay:
+		if !conditions.SameDay {
+			return 0, 0
+		}
+		return 1, r.surcharge(itemTotal)
+	case PricingRuleNight:
+		if !conditions.Night {
+			return 0, 0
+		}
+		return 1, r.surcharge(itemTotal)
+	}
+	return 0, 0
+}
+
+// surcharge returns the percentage of the item subtotal rounded down to a yen,
+// or the fixed amount when no percentage is set
+func (r PricingRule) surcharge(itemTotal float64) float64 {
+	if r.Percent > 0 {
+		return math.Floor(itemTotal * r.Percent / 100)
+	}
+	return r.Amount
+}
+
+// line builds the surcharge line of the rule
+func (r PricingRule) line(quantity, unitPrice float64) EstimateItem {
+	return EstimateItem{
+		ItemID:      pricingRuleItemPrefix + string(r.Kind),
+		Description: r.Name,
+		Quantity:    quantity,
+		Unit:        r.Unit,
+		UnitPrice:   unitPrice,
+		Amount:      quantity * unitPrice,
+		TaxCategory: r.TaxCategory.OrDefault(),
+	}
+}
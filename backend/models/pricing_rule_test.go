@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPricingRuleLines(t *testing.T) {
+	rules := []PricingRule{
+		{Kind: PricingRuleFloor, Name: "階段作業費", Unit: "階", Amount: 1000, Threshold: 1, Enabled: true},
+		{Kind: PricingRuleParkingDistance, Name: "横持ち作業費", Unit: "10m", Amount: 1000, Threshold: 20, Step: 10, Enabled: true},
+		{Kind: PricingRuleDisassembly, Name: "解体作業費", Unit: "点", Amount: 2000, Enabled: true},
+		{Kind: PricingRuleSameDay, Name: "当日対応割増", Unit: "式", Percent: 20, Enabled: true},
+		{Kind: PricingRuleNight, Name: "夜間作業割増", Unit: "式", Percent: 25, Enabled: false},
+		{Kind: PricingRuleMinimumCharge, Name: "最低料金調整", Unit: "式", Amount: 30000, Enabled: true},
+	}
+	items := []EstimateItem{{Description: "ソファ", Quantity: 1, UnitPrice: 8000}}
+	conditions := JobConditions{Floor: 3, ParkingDistance: 35, DisassemblyCount: 1, SameDay: true, Night: true}
+
+	lines := PricingRuleLines(items, conditions, rules)
+
+	// 3階は基準階を2階超え、35mは20mを超える15mを10m刻みで2単位、夜間は無効
+	assert.Len(t, lines, 5)
+	assert.Equal(t, EstimateItem{ItemID: "rule:floor", Description: "階段作業費", Quantity: 2, Unit: "階", UnitPrice: 1000, Amount: 2000, TaxCategory: TaxCategoryStandard}, lines[0])
+	assert.Equal(t, 2.0, lines[1].Quantity)
+	assert.Equal(t, 2000.0, lines[2].Amount)
+	assert.Equal(t, 1600.0, lines[3].Amount)
+	// 最低料金は品目と他の割増の合計 15,600円との差額
+	assert.Equal(t, "rule:minimum_charge", lines[4].ItemID)
+	assert.Equal(t, 14400.0, lines[4].Amount)
+
+	// エレベーターがあれば階段作業費はかからない
+	conditions.HasElevator = true
+	assert.Len(t, PricingRuleLines(items, conditions, rules[:1]), 0)
+
+	withLines := append(append([]EstimateItem{}, items...), lines...)
+	assert.Equal(t, items, WithoutPricingRuleLines(withLines))
+}
@@ -0,0 +1,213 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ApplianceClass is an appliance covered by the home appliance recycling law (家電リサイクル法)
+type ApplianceClass string
+
+const (
+	ApplianceTV             ApplianceClass = "tv"              // テレビ（ブラウン管・液晶・有機EL・プラズマ）
+	ApplianceRefrigerator   ApplianceClass = "refrigerator"    // 冷蔵庫・冷凍庫
+	ApplianceWashingMachine ApplianceClass = "washing_machine" // 洗濯機・衣類乾燥機
+	ApplianceAirConditioner ApplianceClass = "air_conditioner" // エアコン
+)
+
+// ApplianceSizeClass is the size class that decides the recycling fee of TVs and refrigerators
+type ApplianceSizeClass string
+
+const (
+	ApplianceSizeSmall ApplianceSizeClass = "small" // テレビ15型以下・冷蔵庫170L以下
+	ApplianceSizeLarge ApplianceSizeClass = "large" // テレビ16型以上・冷蔵庫171L以上
+)
+
+// recyclingFeeItemPrefix marks the item ID of recycling fee lines, e.g. "recycling:tv"
+const recyclingFeeItemPrefix = "recycling:"
+
+// IsValid reports whether c is a known appliance class
+func (c ApplianceClass) IsValid() bool {
+	switch c {
+	case ApplianceTV, ApplianceRefrigerator, ApplianceWashingMachine, ApplianceAirConditioner:
+		return true
+	}
+	return false
+}
+
+// Label returns the name of the class as printed on estimates
+func (c ApplianceClass) Label() string {
+	switch c {
+	case ApplianceTV:
+		return "テレビ"
+	case ApplianceRefrigerator:
+		return "冷蔵庫・冷凍庫"
+	case ApplianceWashingMachine:
+		return "洗濯機・衣類乾燥機"
+	case ApplianceAirConditioner:
+		return "エアコン"
+	}
+	return string(c)
+}
+
+// HasSizeClasses reports whether the recycling fee of the class depends on the size
+func (c ApplianceClass) HasSizeClasses() bool {
+	return c == ApplianceTV || c == ApplianceRefrigerator
+}
+
+// SizeLabel returns the size class as printed on estimates, e.g. 16型以上
+func (c ApplianceClass) SizeLabel(size ApplianceSizeClass) string {
+	switch {
+	case c == ApplianceTV && size == ApplianceSizeSmall:
+		return "15型以下"
+	case c == ApplianceTV && size == ApplianceSizeLarge:
+		return "16型以上"
+	case c == ApplianceRefrigerator && size == ApplianceSizeSmall:
+		return "170L以下"
+	case c == ApplianceRefrigerator && size == ApplianceSizeLarge:
+		return "171L以上"
+	}
+	return ""
+}
+
+// ApplianceRecycling is the recycling law information of an appliance line.
+// One recycling ticket (家電リサイクル券) is issued per unit.
+type ApplianceRecycling struct {
+	Class         ApplianceClass     `json:"class"`
+	Manufacturer  string             `json:"manufacturer"`   // メーカー名（省略時は標準料金）
+	SizeClass     ApplianceSizeClass `json:"size_class"`     // テレビ・冷蔵庫のみ
+	TicketNumbers []string           `json:"ticket_numbers"` // リサイクル券のお問合せ管理票番号
+}
+
+// RecyclingFee is the recycling fee of an appliance class, manufacturer and size class.
+// The row with an empty manufacturer is the standard fee used for unlisted manufacturers.
+type RecyclingFee struct {
+	ID           uint               `json:"id"`
+	Class        ApplianceClass     `json:"class"`
+	Manufacturer string             `json:"manufacturer"`
+	SizeClass    ApplianceSizeClass `json:"size_class"`
+	Fee          float64            `json:"fee"` // リサイクル料金（税込・不課税）
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+// RecyclingFeeRequest holds the fields of a recycling fee table row
+type RecyclingFeeRequest struct {
+	Class        ApplianceClass     `json:"class" binding:"required,oneof=tv refrigerator washing_machine air_conditioner"`
+	Manufacturer string             `json:"manufacturer"`
+	SizeClass    ApplianceSizeClass `json:"size_class" binding:"omitempty,oneof=small large"`
+	Fee          float64            `json:"fee" binding:"min=0"`
+}
+
+// RecyclingTicketsRequest records the recycling ticket numbers of appliance lines
+type RecyclingTicketsRequest struct {
+	Items []RecyclingTicketEntry `json:"items" binding:"required,dive"`
+}
+
+// RecyclingTicketEntry holds the ticket numbers of one appliance line
+type RecyclingTicketEntry struct {
+	Line          int      `json:"line" binding:"min=1"` // 1始まりの明細行
+	TicketNumbers []string `json:"ticket_numbers"`
+}
+
+// Validate checks the size class required by the appliance class
+func (r ApplianceRecycling) Validate() error {
+	if !r.Class.IsValid() {
+		return fmt.Errorf("家電リサイクル法の品目が不正です: %s", r.Class)
+	}
+	if r.Class.HasSizeClasses() && r.Class.SizeLabel(r.SizeClass) == "" {
+		return fmt.Errorf("%sはサイズ区分(small/large)を指定してください", r.Class.Label())
+	}
+	if !r.Class.HasSizeClasses() && r.SizeClass != "" {
+		return fmt.Errorf("%sにサイズ区分はありません", r.Class.Label())
+	}
+	return nil
+}
+
+// IsRecyclingFeeLine reports whether the line is a recycling fee added for an appliance
+func (item EstimateItem) IsRecyclingFeeLine() bool {
+	return strings.HasPrefix(item.ItemID, recyclingFeeItemPrefix)
+}
+
+// FindRecyclingFee returns the fee of the manufacturer, or the standard fee when the
+// manufacturer is not listed
+func FindRecyclingFee(fees []RecyclingFee, recycling ApplianceRecycling) (RecyclingFee, bool) {
+	var standard *RecyclingFee
+	for i, fee := range fees {
+		if fee.Class != recycling.Class || fee.SizeClass != recycling.SizeClass {
+			continue
+		}
+		if fee.Manufacturer == recycling.Manufacturer {
+			return fee, true
+		}
+		if fee.Manufacturer == "" {
+			standard = &fees[i]
+		}
+	}
+	if standard != nil {
+		return *standard, true
+	}
+	return RecyclingFee{}, false
+}
+
+// WithRecyclingFeeLines returns the items with a non-taxable recycling fee line inserted
+// after each appliance line. Existing recycling fee lines are replaced.
+func WithRecyclingFeeLines(items []EstimateItem, fees []RecyclingFee) ([]EstimateItem, error) {
+	lines := []EstimateItem{}
+	for i, item := range items {
+		if item.IsRecyclingFeeLine() {
+			continue
+		}
+		lines = append(lines, item)
+		if item.Recycling == nil {
+			continue
+		}
+
+		recycling := *item.Recycling
+		if err := recycling.Validate(); err != nil {
+			return nil, fmt.Errorf("%d行目: %v", i+1, err)
+		}
+		fee, ok := FindRecyclingFee(fees, recycling)
+		if !ok {
+			return nil, fmt.Errorf("%d行目: %sのリサイクル料金が登録されていません", i+1, recycling.Class.Label())
+		}
+
+		specification := recycling.Manufacturer
+		if size := recycling.Class.SizeLabel(recycling.SizeClass); size != "" {
+			specification = strings.TrimPrefix(specification+"・"+size, "・")
+		}
+		line := EstimateItem{
+			ItemID:        recyclingFeeItemPrefix + string(recycling.Class),
+			Description:   fmt.Sprintf("家電リサイクル料金（%s）", recycling.Class.Label()),
+			Specification: specification,
+			Quantity:      item.Quantity,
+			Unit:          item.Unit,
+			UnitPrice:     fee.Fee,
+			TaxCategory:   TaxCategoryNonTaxable,
+		}
+		line.Amount = line.NetAmount()
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// RecyclingTicketCount returns the number of recycling tickets recorded on the items
+func RecyclingTicketCount(items []EstimateItem) int {
+	count := 0
+	for _, item := range items {
+		if item.Recycling != nil {
+			count += len(item.Recycling.TicketNumbers)
+		}
+	}
+	return count
+}
+
+// HasRecyclingItems reports whether any item is covered by the recycling law
+func HasRecyclingItems(items []EstimateItem) bool {
+	for _, item := range items {
+		if item.Recycling != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRecyclingFeeLines(t *testing.T) {
+	fees := []RecyclingFee{
+		{Class: ApplianceRefrigerator, SizeClass: ApplianceSizeLarge, Fee: 4730},
+		{Class: ApplianceRefrigerator, Manufacturer: "メーカーA", SizeClass: ApplianceSizeLarge, Fee: 5000},
+		{Class: ApplianceWashingMachine, Fee: 2530},
+	}
+	items := []EstimateItem{
+		{ItemID: "refrigerator", Description: "冷蔵庫（収集運搬料金）", Quantity: 1, UnitPrice: 5000,
+			Recycling: &ApplianceRecycling{Class: ApplianceRefrigerator, Manufacturer: "メーカーA", SizeClass: ApplianceSizeLarge}},
+		{ItemID: "washing-machine", Description: "洗濯機（収集運搬料金）", Quantity: 2, UnitPrice: 4000,
+			Recycling: &ApplianceRecycling{Class: ApplianceWashingMachine, Manufacturer: "メーカーB"}},
+	}
+
+	lines, err := WithRecyclingFeeLines(items, fees)
+	require.NoError(t, err)
+	require.Len(t, lines, 4)
+	assert.Equal(t, "recycling:refrigerator", lines[1].ItemID)
+	assert.Equal(t, 5000.0, lines[1].Amount)
+	assert.Equal(t, TaxCategoryNonTaxable, lines[1].TaxCategory)
+	// 料金表にないメーカーは標準料金
+	assert.Equal(t, 5060.0, lines[3].Amount)
+	assert.Equal(t, "メーカーB", lines[3].Specification)
+
+	// 再計算しても料金の明細は重複しない
+	again, err := WithRecyclingFeeLines(lines, fees)
+	require.NoError(t, err)
+	assert.Len(t, again, 4)
+
+	items[0].Recycling = &ApplianceRecycling{Class: ApplianceRefrigerator}
+	_, err = WithRecyclingFeeLines(items, fees)
+	assert.EqualError(t, err, "1行目: 冷蔵庫・冷凍庫はサイズ区分(small/large)を指定してください")
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var registrationNumberPattern = regexp.MustCompile(`^T[1-9][0-9]{12}$`)
+
+// ValidateRegistrationNumber checks a qualified invoice issuer registration number
+// (適格請求書発行事業者登録番号): "T" followed by 13 digits whose first digit is the
+// check digit of the remaining 12 digits, computed as for 法人番号.
+func ValidateRegistrationNumber(number string) error {
+	if !registrationNumberPattern.MatchString(number) {
+		return fmt.Errorf("registration number must be T followed by 13 digits: %q", number)
+	}
+
+	digits := number[2:]
+	sum := 0
+	for n := 1; n <= len(digits); n++ {
+		// n は下位の桁から数えた位置。奇数桁は1、偶数桁は2を掛ける
+		p := int(digits[len(digits)-n] - '0')
+		if n%2 == 0 {
+			sum += p * 2
+		} else {
+			sum += p
+		}
+	}
+
+	if check := 9 - sum%9; int(number[1]-'0') != check {
+		return fmt.Errorf("registration number has an invalid check digit: %q", number)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRegistrationNumber(t *testing.T) {
+	assert.NoError(t, ValidateRegistrationNumber("T7000012050002"))
+
+	for _, number := range []string{
+		"",
+		"7000012050002",   // T がない
+		"T700001205000",   // 12桁
+		"T70000120500021", // 14桁
+		"T0000012050002",  // チェックデジットが0
+		"T8000012050002",  // チェックデジット不一致
+	} {
+		assert.Error(t, ValidateRegistrationNumber(number), number)
+	}
+}
@@ -0,0 +1,98 @@
+package models
+
+// TaxCategory is the consumption tax treatment of a line (税区分)
+type TaxCategory string
+
+const (
+	TaxCategoryStandard   TaxCategory = "standard"    // 標準税率
+	TaxCategoryReduced    TaxCategory = "reduced"     // 軽減税率
+	TaxCategoryExempt     TaxCategory = "exempt"      // 非課税
+	TaxCategoryNonTaxable TaxCategory = "non_taxable" // 不課税（家電リサイクル料金などの預り金・立替金）
+)
+
+// TaxCategories lists the tax categories in the order they are totalled and printed
+var TaxCategories = []TaxCategory{TaxCategoryStandard, TaxCategoryReduced, TaxCategoryExempt, TaxCategoryNonTaxable}
+
+// ReducedTaxRate is the reduced consumption tax rate (軽減税率)
+const ReducedTaxRate = 0.08
+
+// IsValid reports whether c is a known category. An empty category means standard.
+func (c TaxCategory) IsValid() bool {
+	if c == "" {
+		return true
+	}
+	for _, category := range TaxCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// OrDefault returns c, or TaxCategoryStandard when c is empty
+func (c TaxCategory) OrDefault() TaxCategory {
+	if c == "" {
+		return TaxCategoryStandard
+	}
+	return c
+}
+
+// IsTaxable reports whether consumption tax is charged on lines of the category
+func (c TaxCategory) IsTaxable() bool {
+	return c != TaxCategoryExempt && c != TaxCategoryNonTaxable
+}
+
+// Rate returns the tax rate applied to the category.
+// standardRate is the standard rate of the document.
+func (c TaxCategory) Rate(standardRate float64) float64 {
+	switch c {
+	case TaxCategoryReduced:
+		return ReducedTaxRate
+	case TaxCategoryExempt, TaxCategoryNonTaxable:
+		return 0
+	}
+	return standardRate
+}
+
+// TaxBreakdown is the amount and tax of one tax category (税区分ごとの対価の額と消費税額)
+type TaxBreakdown struct {
+	Category TaxCategory `json:"category"`
+	Rate     float64     `json:"rate"`
+	Taxable  float64     `json:"taxable"` // 税区分ごとに区分した対価の額（税込価格の場合は税込）
+	Tax      float64     `json:"tax"`     // 税率ごとに区分した消費税額（非課税・不課税は0）
+}
+
+// CalculateTax totals the line amounts per tax category and computes the tax as the pricing
+// mode specifies. By default the tax is rounded down once per rate, as required for qualified
+// invoices (端数処理は税率ごとに1回). The amounts are tax-included when the prices are.
+// The breakdown only contains the categories used, in the order of TaxCategories.
+func CalculateTax(items []EstimateItem, standardRate float64, mode PricingMode) (subTotal float64, breakdown []TaxBreakdown, tax float64) {
+	mode = mode.OrDefault()
+
+	amounts := map[TaxCategory]float64{}
+	lineTaxes := map[TaxCategory]float64{}
+	for _, item := range items {
+		category := item.TaxCategory.OrDefault()
+		subTotal += item.Amount
+		amounts[category] += item.Amount
+		if mode.RoundingUnit == TaxRoundingPerLine {
+			lineTaxes[category] += mode.Rounding.Apply(mode.tax(item.Amount, category.Rate(standardRate)))
+		}
+	}
+
+	for _, category := range TaxCategories {
+		amount, ok := amounts[category]
+		if !ok {
+			continue
+		}
+		rate := category.Rate(standardRate)
+		categoryTax := lineTaxes[category]
+		if mode.RoundingUnit != TaxRoundingPerLine {
+			categoryTax = mode.Rounding.Apply(mode.tax(amount, rate))
+		}
+		breakdown = append(breakdown, TaxBreakdown{Category: category, Rate: rate, Taxable: amount, Tax: categoryTax})
+		tax += categoryTax
+	}
+
+	return subTotal, breakdown, tax
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateTaxPricingModes(t *testing.T) {
+	items := []EstimateItem{{Amount: 1234}, {Amount: 1555}}
+
+	tests := []struct {
+		name  string
+		mode  PricingMode
+		tax   float64
+		total float64
+	}{
+		{"既定は税抜・税率ごとに切り捨て", PricingMode{}, 278, 3067},
+		{"税抜・行ごとに四捨五入", PricingMode{Rounding: TaxRoundingRound, RoundingUnit: TaxRoundingPerLine}, 279, 3068},
+		{"税抜・切り上げ", PricingMode{Rounding: TaxRoundingCeil}, 279, 3068},
+		{"税込・切り捨て", PricingMode{PriceBasis: PriceBasisInclusive}, 253, 2789},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subTotal, breakdown, tax := CalculateTax(items, DefaultTaxRate, tt.mode)
+			assert.Equal(t, 2789.0, subTotal)
+			assert.Equal(t, tt.tax, tax)
+			assert.Equal(t, tt.tax, breakdown[0].Tax)
+			assert.Equal(t, tt.total, tt.mode.Total(subTotal, tax))
+		})
+	}
+
+	// 税込 11,000円の内消費税は誤差で 999円にならない
+	_, _, tax := CalculateTax([]EstimateItem{{Amount: 11000}}, DefaultTaxRate, PricingMode{PriceBasis: PriceBasisInclusive})
+	assert.Equal(t, 1000.0, tax)
+}
+
+func TestPricingModeValidateForIssuer(t *testing.T) {
+	perLine := PricingMode{RoundingUnit: TaxRoundingPerLine}
+
+	assert.NoError(t, perLine.ValidateForIssuer(""))
+	assert.NoError(t, PricingMode{}.ValidateForIssuer("T7000012050002"))
+	assert.Error(t, perLine.ValidateForIssuer("T7000012050002"))
+}
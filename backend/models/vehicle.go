@@ -0,0 +1,109 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// TransportFeeName is the line name of the collection and transport fee (収集運搬費)
+const TransportFeeName = "収集運搬費"
+
+// transportItemPrefix marks the item ID of transport fee lines, e.g. "vehicle:2t"
+const transportItemPrefix = "vehicle:"
+
+// Vehicle is a collection truck type and its per-trip transport fee
+type Vehicle struct {
+	ID        uint    `json:"id"`
+	Code      string  `json:"code"`     // light, 2t, 4t
+	Name      string  `json:"name"`     // 軽トラック / 2tトラック / 4tトラック
+	Capacity  float64 `json:"capacity"` // 荷台の積載容積 (m³)
+	MaxLoad   float64 `json:"max_load"` // 最大積載量 (kg)
+	Price     float64 `json:"price"`    // 1台1回あたりの収集運搬費 (円)
+	SortOrder int     `json:"sort_order"`
+}
+
+// LoadPlan is the total load of the items and the vehicle recommended to carry it
+type LoadPlan struct {
+	TotalVolume    float64  `json:"total_volume"`    // 合計容積 (m³)
+	TotalWeight    float64  `json:"total_weight"`    // 合計重量 (kg)
+	Vehicle        *Vehicle `json:"vehicle"`         // 推奨車両（車両が登録されていなければ null）
+	Trips          int      `json:"trips"`           // 必要な台数（往復回数）
+	TransportFee   float64  `json:"transport_fee"`   // 収集運搬費 = 車両の料金 × 台数
+	UnmeasuredRows []int    `json:"unmeasured_rows"` // 容積・重量が登録されていない行（1始まり）
+}
+
+// PlanLoad recommends the vehicle that carries the given volume and weight at the lowest
+// transport fee. Ties are broken by fewer trips, then by the order of the vehicles.
+// No vehicle is recommended when there is nothing to load.
+func PlanLoad(volume, weight float64, vehicles []Vehicle) LoadPlan {
+	plan := LoadPlan{TotalVolume: volume, TotalWeight: weight, UnmeasuredRows: []int{}}
+	if volume <= 0 && weight <= 0 {
+		return plan
+	}
+	for i := range vehicles {
+		vehicle := vehicles[i]
+		if vehicle.Capacity <= 0 || vehicle.MaxLoad <= 0 {
+			continue
+		}
+		trips := int(math.Max(tripsFor(volume, vehicle.Capacity), tripsFor(weight, vehicle.MaxLoad)))
+		if trips < 1 {
+			trips = 1
+		}
+		fee := float64(trips) * vehicle.Price
+		if plan.Vehicle == nil || fee < plan.TransportFee || (fee == plan.TransportFee && trips < plan.Trips) {
+			plan.Vehicle = &vehicles[i]
+			plan.Trips = trips
+			plan.TransportFee = fee
+		}
+	}
+	return plan
+}
+
+// tripsFor returns how many loads of the given capacity are needed, ignoring float error
+func tripsFor(total, capacity float64) float64 {
+	return math.Ceil(total/capacity - 1e-9)
+}
+
+// TransportLine returns the estimate line charging the transport fee of the plan.
+// It reports false when no vehicle was recommended.
+func (p LoadPlan) TransportLine() (EstimateItem, bool) {
+	if p.Vehicle == nil {
+		return EstimateItem{}, false
+	}
+	line := EstimateItem{
+		ItemID:        TransportItemID(p.Vehicle.Code),
+		Description:   fmt.Sprintf("%s（%s）", TransportFeeName, p.Vehicle.Name),
+		Specification: fmt.Sprintf("約%sm³・%skg", formatLoad(p.TotalVolume), formatLoad(p.TotalWeight)),
+		Quantity:      float64(p.Trips),
+		Unit:          "台",
+		UnitPrice:     p.Vehicle.Price,
+		TaxCategory:   TaxCategoryStandard,
+	}
+	line.Amount = line.NetAmount()
+	return line, true
+}
+
+// TransportItemID returns the item ID of the transport fee line of a vehicle
+func TransportItemID(code string) string {
+	return transportItemPrefix + code
+}
+
+// IsTransportLine reports whether the line charges the transport fee
+func (item EstimateItem) IsTransportLine() bool {
+	return strings.HasPrefix(item.ItemID, transportItemPrefix)
+}
+
+// VehicleLabel formats the vehicle as printed on instruction sheets, e.g. "2tトラック × 1台"
+func (p LoadPlan) VehicleLabel() string {
+	if p.Vehicle == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s × %d台", p.Vehicle.Name, p.Trips)
+}
+
+// formatLoad formats a volume or weight with at most one decimal place
+func formatLoad(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64)
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanLoad(t *testing.T) {
+	vehicles := []Vehicle{
+		{Code: "light", Name: "軽トラック", Capacity: 3.5, MaxLoad: 350, Price: 15000},
+		{Code: "2t", Name: "2tトラック", Capacity: 10, MaxLoad: 2000, Price: 30000},
+		{Code: "4t", Name: "4tトラック", Capacity: 20, MaxLoad: 4000, Price: 50000},
+	}
+
+	plan := PlanLoad(3.5, 200, vehicles)
+	require.NotNil(t, plan.Vehicle)
+	assert.Equal(t, "light", plan.Vehicle.Code)
+	assert.Equal(t, 1, plan.Trips)
+
+	// 軽トラック2回と2tトラック1台は同額なので台数の少ない2tトラック
+	plan = PlanLoad(5, 300, vehicles)
+	assert.Equal(t, "2t", plan.Vehicle.Code)
+
+	// 重量で決まる場合
+	plan = PlanLoad(2, 3000, vehicles)
+	assert.Equal(t, "4t", plan.Vehicle.Code)
+
+	// 4tトラックでも1台に載らなければ往復する
+	plan = PlanLoad(45, 1000, vehicles)
+	assert.Equal(t, "4t", plan.Vehicle.Code)
+	assert.Equal(t, 3, plan.Trips)
+	assert.Equal(t, 150000.0, plan.TransportFee)
+
+	line, ok := plan.TransportLine()
+	require.True(t, ok)
+	assert.Equal(t, "vehicle:4t", line.ItemID)
+	assert.Equal(t, "収集運搬費（4tトラック）", line.Description)
+	assert.Equal(t, 150000.0, line.Amount)
+	assert.Equal(t, "4tトラック × 3台", plan.VehicleLabel())
+
+	_, ok = PlanLoad(0, 0, vehicles).TransportLine()
+	assert.False(t, ok)
+}
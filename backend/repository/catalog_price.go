@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"line-estimate-backend/models"
+)
+
+// ListPrices returns the price history of an item in order of effective date.
+// The history is kept after the item is deleted.
+func (r *sqlCatalogRepository) ListPrices(ctx context.Context, itemID string) ([]models.CatalogItemPrice, error) {
+	history, err := r.listPrices(ctx, r.db, itemID)
+	if err != nil {
+		return nil, err
+	}
+	prices := history[itemID]
+	if prices == nil {
+		prices = []models.CatalogItemPrice{}
+	}
+	return prices, nil
+}
+
+// ListAllPrices returns the price history of every item, keyed by item ID
+func (r *sqlCatalogRepository) ListAllPrices(ctx context.Context) (map[string][]models.CatalogItemPrice, error) {
+	return r.listPrices(ctx, r.db, "")
+}
+
+// SetPrice records a price effective from a date, replacing the price already recorded for
+// that date. It returns ErrNotFound when the item does not exist.
+func (r *sqlCatalogRepository) SetPrice(ctx context.Context, price *models.CatalogItemPrice) error {
+	var count int
+	if err := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT COUNT(*) FROM catalog_items WHERE id = ?"), price.ItemID).Scan(&count); err != nil {
+		return fmt.Errorf("unable to get catalog item: %v", err)
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	price.CreatedAt = time.Now().UTC()
+	if err := r.savePrice(ctx, tx, price); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeletePrice deletes the price effective from a date, e.g. to cancel a scheduled change.
+// It returns ErrNotFound when no price is recorded for the date.
+func (r *sqlCatalogRepository) DeletePrice(ctx context.Context, itemID, effectiveFrom string) error {
+	result, err := r.db.ExecContext(ctx, r.db.Rebind("DELETE FROM catalog_item_prices WHERE item_id = ? AND effective_from = ?"), itemID, effectiveFrom)
+	if err != nil {
+		return fmt.Errorf("unable to delete catalog item price: %v", err)
+	}
+	return requireAffected(result)
+}
+
+// listPrices reads the price history of an item, or of all items when itemID is empty
+func (r *sqlCatalogRepository) listPrices(ctx context.Context, q queryer, itemID string) (map[string][]models.CatalogItemPrice, error) {
+	query := "SELECT item_id, price, effective_from, note, created_at FROM catalog_item_prices"
+	args := []any{}
+	if itemID != "" {
+		query += " WHERE item_id = ?"
+		args = append(args, itemID)
+	}
+	rows, err := q.QueryContext(ctx, r.db.Rebind(query+" ORDER BY item_id, effective_from"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list catalog item prices: %v", err)
+	}
+	defer rows.Close()
+
+	history := map[string][]models.CatalogItemPrice{}
+	for rows.Next() {
+		var price models.CatalogItemPrice
+		if err := rows.Scan(&price.ItemID, &price.Price, &price.EffectiveFrom, &price.Note, &price.CreatedAt); err != nil {
+			return nil, fmt.Errorf("unable to scan catalog item price: %v", err)
+		}
+		history[price.ItemID] = append(history[price.ItemID], price)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to list catalog item prices: %v", err)
+	}
+	for _, prices := range history {
+		models.SetPriceEffectiveTo(prices)
+	}
+	return history, nil
+}
+
+// recordPrice records the price of an item saved from the admin API or the sheet when it
+// differs from the price effective on item.EffectiveFrom (today when empty), then sets the
+// period of the price on the item. The price history is the only place prices are stored.
+//
+// When keepScheduled is set (pulling the sheet or importing a file), a price change scheduled
+// in advance that has taken effect is kept if the incoming price is the one it replaced: the
+// sheet or file was written before the change and is stale, not a new price.
+func (r *sqlCatalogRepository) recordPrice(ctx context.Context, q queryer, item *models.CatalogItem, now time.Time, keepScheduled bool) error {
+	date := item.EffectiveFrom
+	if date == "" {
+		date = now.In(jst).Format("2006-01-02")
+	}
+	history, err := r.listPrices(ctx, q, item.ID)
+	if err != nil {
+		return err
+	}
+	current, ok := models.PriceAsOf(history[item.ID], date)
+	if ok && keepScheduled && replacedByScheduledPrice(history[item.ID], current, item.Price) {
+		item.Price = current.Price
+	}
+	if !ok || current.Price != item.Price {
+		if err := r.savePrice(ctx, q, &models.CatalogItemPrice{
+			ItemID:        item.ID,
+			Price:         item.Price,
+			EffectiveFrom: date,
+			CreatedAt:     now,
+		}); err != nil {
+			return err
+		}
+		if history, err = r.listPrices(ctx, q, item.ID); err != nil {
+			return err
+		}
+		current, _ = models.PriceAsOf(history[item.ID], date)
+	}
+	item.EffectiveFrom, item.EffectiveTo = current.EffectiveFrom, current.EffectiveTo
+	return nil
+}
+
+// replacedByScheduledPrice reports whether current is a change registered before its effective
+// date and price is the price effective before it
+func replacedByScheduledPrice(prices []models.CatalogItemPrice, current models.CatalogItemPrice, price int) bool {
+	if current.EffectiveFrom <= current.CreatedAt.In(jst).Format("2006-01-02") {
+		return false
+	}
+	for i := len(prices) - 1; i > 0; i-- {
+		if prices[i].EffectiveFrom == current.EffectiveFrom {
+			return prices[i-1].Price == price
+		}
+	}
+	return false
+}
+
+// savePrice replaces the price of an item effective from the same date
+func (r *sqlCatalogRepository) savePrice(ctx context.Context, q queryer, price *models.CatalogItemPrice) error {
+	if _, err := q.ExecContext(ctx, r.db.Rebind("DELETE FROM catalog_item_prices WHERE item_id = ? AND effective_from = ?"), price.ItemID, price.EffectiveFrom); err != nil {
+		return fmt.Errorf("unable to replace catalog item price: %v", err)
+	}
+	if _, err := q.ExecContext(ctx, r.db.Rebind(`INSERT INTO catalog_item_prices
+		(item_id, effective_from, price, note, created_at) VALUES (?, ?, ?, ?, ?)`),
+		price.ItemID, price.EffectiveFrom, price.Price, price.Note, price.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("unable to insert catalog item price: %v", err)
+	}
+	return nil
+}
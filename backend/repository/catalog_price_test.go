@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"line-estimate-backend/models"
+)
+
+func TestReplaceAllKeepsScheduledPrice(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Migrate())
+	catalog := NewCatalogRepository(db)
+
+	now := time.Now().In(jst)
+	today := now.Format("2006-01-02")
+	item := models.CatalogItem{ID: "refrigerator", Name: "冷蔵庫", Price: 3000, Active: true, EffectiveFrom: "2000-01-01"}
+	categories := []models.CatalogCategory{{ID: "appliances", Name: "家電", Active: true, Items: []models.CatalogItem{item}}}
+	require.NoError(t, catalog.ReplaceAll(ctx, categories))
+
+	// 10日前に予約した値上げが昨日から適用されている
+	_, err = db.ExecContext(ctx, db.Rebind(`INSERT INTO catalog_item_prices
+		(item_id, effective_from, price, note, created_at) VALUES (?, ?, ?, ?, ?)`),
+		"refrigerator", now.AddDate(0, 0, -1).Format("2006-01-02"), 3500, "値上げ", now.AddDate(0, 0, -10).UTC())
+	require.NoError(t, err)
+
+	priceToday := func() int {
+		t.Helper()
+		listed, err := catalog.List(ctx)
+		require.NoError(t, err)
+		return listed[0].Items[0].Price
+	}
+
+	// 値上げ前に書かれたシートの価格では、適用済みの値上げを戻さない
+	categories[0].Items[0].EffectiveFrom = today
+	require.NoError(t, catalog.ReplaceAll(ctx, categories))
+	assert.Equal(t, 3500, priceToday())
+
+	// シートで別の価格にした場合は本日から適用する
+	categories[0].Items[0].Price = 4000
+	require.NoError(t, catalog.ReplaceAll(ctx, categories))
+	assert.Equal(t, 4000, priceToday())
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"line-estimate-backend/models"
+)
+
+var (
+	// ErrUnknownCategory is returned when an item refers to a category that does not exist
+	ErrUnknownCategory = errors.New("unknown category")
+	// ErrCategoryNotEmpty is returned when deleting a category that still has items
+	ErrCategoryNotEmpty = errors.New("category has items")
+)
+
+// CatalogRepository provides persistence for the item master edited from the admin API
+type CatalogRepository interface {
+	List(ctx context.Context) ([]models.CatalogCategory, error)
+	ListAsOf(ctx context.Context, date string) ([]models.CatalogCategory, error)
+	CreateCategory(ctx context.Context, category *models.CatalogCategory) error
+	UpdateCategory(ctx context.Context, category *models.CatalogCategory) error
+	DeleteCategory(ctx context.Context, id string) error
+	CreateItem(ctx context.Context, item *models.CatalogItem) error
+	UpdateItem(ctx context.Context, item *models.CatalogItem) error
+	DeleteItem(ctx context.Context, id string) error
+	ReplaceAll(ctx context.Context, categories []models.CatalogCategory) error
+	ListPrices(ctx context.Context, itemID string) ([]models.CatalogItemPrice, error)
+	ListAllPrices(ctx context.Context) (map[string][]models.CatalogItemPrice, error)
+	SetPrice(ctx context.Context, price *models.CatalogItemPrice) error
+	DeletePrice(ctx context.Context, itemID, effectiveFrom string) error
+}
+
+type sqlCatalogRepository struct {
+	db *DB
+}
+
+// NewCatalogRepository creates a CatalogRepository backed by the given database
+func NewCatalogRepository(db *DB) CatalogRepository {
+	return &sqlCatalogRepository{db: db}
+}
+
+const catalogItemColumns = `id, category_id, name, hiragana, unit, volume, weight,
+	recycling_class, sort_order, active, updated_at`
+
+// List returns all categories including inactive ones, each with its items, in display order.
+// Items are priced as of today (JST).
+func (r *sqlCatalogRepository) List(ctx context.Context) ([]models.CatalogCategory, error) {
+	return r.ListAsOf(ctx, time.Now().In(jst).Format("2006-01-02"))
+}
+
+// ListAsOf is List with the price of each item effective on the date (YYYY-MM-DD), read from
+// the price history. Items without a price in effect on the date, i.e. added after it, are
+// left out.
+func (r *sqlCatalogRepository) ListAsOf(ctx context.Context, date string) ([]models.CatalogCategory, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, hiragana, sort_order, active, updated_at
+		FROM catalog_categories ORDER BY sort_order, id`)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list catalog categories: %v", err)
+	}
+	defer rows.Close()
+
+	categories := []models.CatalogCategory{}
+	index := map[string]int{}
+	for rows.Next() {
+		category := models.CatalogCategory{Items: []models.CatalogItem{}}
+		if err := rows.Scan(&category.ID, &category.Name, &category.Hiragana, &category.SortOrder, &category.Active, &category.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("unable to scan catalog category: %v", err)
+		}
+		index[category.ID] = len(categories)
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	itemRows, err := r.db.QueryContext(ctx, "SELECT "+catalogItemColumns+" FROM catalog_items ORDER BY sort_order, id")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list catalog items: %v", err)
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var item models.CatalogItem
+		if err := itemRows.Scan(
+			&item.ID,
+			&item.CategoryID,
+			&item.Name,
+			&item.Hiragana,
+			&item.Unit,
+			&item.Volume,
+			&item.Weight,
+			&item.RecyclingClass,
+			&item.SortOrder,
+			&item.Active,
+			&item.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("unable to scan catalog item: %v", err)
+		}
+		if i, ok := index[item.CategoryID]; ok {
+			categories[i].Items = append(categories[i].Items, item)
+		}
+	}
+	if err := itemRows.Err(); err != nil {
+		return nil, err
+	}
+	itemRows.Close()
+
+	history, err := r.listPrices(ctx, r.db, "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range categories {
+		items := categories[i].Items[:0]
+		for _, item := range categories[i].Items {
+			price, ok := models.PriceAsOf(history[item.ID], date)
+			if !ok {
+				continue
+			}
+			item.Price = price.Price
+			item.EffectiveFrom, item.EffectiveTo = price.EffectiveFrom, price.EffectiveTo
+			items = append(items, item)
+		}
+		categories[i].Items = items
+	}
+	return categories, nil
+}
+
+// CreateCategory adds a category. It returns ErrDuplicate when the ID is already used.
+func (r *sqlCatalogRepository) CreateCategory(ctx context.Context, category *models.CatalogCategory) error {
+	category.UpdatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, r.db.Rebind(`INSERT INTO catalog_categories
+		(id, name, hiragana, sort_order, active, updated_at) VALUES (?, ?, ?, ?, ?, ?)`),
+		category.ID, category.Name, category.Hiragana, category.SortOrder, category.Active, category.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("unable to create catalog category: %v", err)
+	}
+	return nil
+}
+
+// UpdateCategory saves the editable fields of a category
+func (r *sqlCatalogRepository) UpdateCategory(ctx context.Context, category *models.CatalogCategory) error {
+	category.UpdatedAt = time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(`UPDATE catalog_categories SET
+		name = ?, hiragana = ?, sort_order = ?, active = ?, updated_at = ? WHERE id = ?`),
+		category.Name, category.Hiragana, category.SortOrder, category.Active, category.UpdatedAt, category.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to update catalog category: %v", err)
+	}
+	return requireAffected(result)
+}
+
+// DeleteCategory deletes an empty category. It returns ErrCategoryNotEmpty when items remain.
+func (r *sqlCatalogRepository) DeleteCategory(ctx context.Context, id string) error {
+	var count int
+	if err := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT COUNT(*) FROM catalog_items WHERE category_id = ?"), id).Scan(&count); err != nil {
+		return fmt.Errorf("unable to count catalog items: %v", err)
+	}
+	if count > 0 {
+		return ErrCategoryNotEmpty
+	}
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind("DELETE FROM catalog_categories WHERE id = ?"), id)
+	if err != nil {
+		return fmt.Errorf("unable to delete catalog category: %v", err)
+	}
+	return requireAffected(result)
+}
+
+// CreateItem adds an item. It returns ErrDuplicate when the ID is already used and
+// ErrUnknownCategory when the category does not exist.
+func (r *sqlCatalogRepository) CreateItem(ctx context.Context, item *models.CatalogItem) error {
+	if err := r.requireCategory(ctx, item.CategoryID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	item.UpdatedAt = time.Now().UTC()
+	_, err = tx.ExecContext(ctx, r.db.Rebind(`INSERT INTO catalog_items
+		(`+catalogItemColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		item.ID, item.CategoryID, item.Name, item.Hiragana, item.Unit, item.Volume, item.Weight,
+		item.RecyclingClass, item.SortOrder, item.Active, item.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("unable to create catalog item: %v", err)
+	}
+	if err := r.recordPrice(ctx, tx, item, item.UpdatedAt, false); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateItem saves the editable fields of an item. It returns ErrUnknownCategory when
+// the item is moved to a category that does not exist. A changed price is recorded in the
+// price history as of item.EffectiveFrom, or today when it is empty.
+func (r *sqlCatalogRepository) UpdateItem(ctx context.Context, item *models.CatalogItem) error {
+	if err := r.requireCategory(ctx, item.CategoryID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	item.UpdatedAt = time.Now().UTC()
+	result, err := tx.ExecContext(ctx, r.db.Rebind(`UPDATE catalog_items SET
+		category_id = ?, name = ?, hiragana = ?, unit = ?, volume = ?, weight = ?,
+		recycling_class = ?, sort_order = ?, active = ?, updated_at = ?
+		WHERE id = ?`),
+		item.CategoryID, item.Name, item.Hiragana, item.Unit, item.Volume, item.Weight,
+		item.RecyclingClass, item.SortOrder, item.Active, item.UpdatedAt, item.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to update catalog item: %v", err)
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+	if err := r.recordPrice(ctx, tx, item, item.UpdatedAt, false); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteItem deletes an item. Estimates keep the name and price copied into their lines.
+func (r *sqlCatalogRepository) DeleteItem(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, r.db.Rebind("DELETE FROM catalog_items WHERE id = ?"), id)
+	if err != nil {
+		return fmt.Errorf("unable to delete catalog item: %v", err)
+	}
+	return requireAffected(result)
+}
+
+// ReplaceAll replaces the whole catalog with the given categories and their items
+// in one transaction. It is used when pulling the catalog from the sheet. The price history
+// is kept; changed prices are recorded as of each item's EffectiveFrom, except stale prices
+// of a scheduled change that has taken effect since the sheet was written.
+func (r *sqlCatalogRepository) ReplaceAll(ctx context.Context, categories []models.CatalogCategory) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM catalog_items"); err != nil {
+		return fmt.Errorf("unable to clear catalog items: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM catalog_categories"); err != nil {
+		return fmt.Errorf("unable to clear catalog categories: %v", err)
+	}
+
+	now := time.Now().UTC()
+	categoryQuery := r.db.Rebind(`INSERT INTO catalog_categories
+		(id, name, hiragana, sort_order, active, updated_at) VALUES (?, ?, ?, ?, ?, ?)`)
+	itemQuery := r.db.Rebind(`INSERT INTO catalog_items
+		(` + catalogItemColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	for _, category := range categories {
+		if _, err := tx.ExecContext(ctx, categoryQuery,
+			category.ID, category.Name, category.Hiragana, category.SortOrder, category.Active, now,
+		); err != nil {
+			return fmt.Errorf("unable to insert catalog category %s: %v", category.ID, err)
+		}
+		for _, item := range category.Items {
+			if _, err := tx.ExecContext(ctx, itemQuery,
+				item.ID, category.ID, item.Name, item.Hiragana, item.Unit, item.Volume, item.Weight,
+				item.RecyclingClass, item.SortOrder, item.Active, now,
+			); err != nil {
+				return fmt.Errorf("unable to insert catalog item %s: %v", item.ID, err)
+			}
+			if err := r.recordPrice(ctx, tx, &item, now, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// requireCategory returns ErrUnknownCategory when the category does not exist
+func (r *sqlCatalogRepository) requireCategory(ctx context.Context, id string) error {
+	var count int
+	if err := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT COUNT(*) FROM catalog_categories WHERE id = ?"), id).Scan(&count); err != nil {
+		return fmt.Errorf("unable to get catalog category: %v", err)
+	}
+	if count == 0 {
+		return ErrUnknownCategory
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// Supported SQL dialects
+const (
+	DialectSQLite   = "sqlite"
+	DialectPostgres = "postgres"
+)
+
+// DB wraps *sql.DB together with the dialect of the connected database
+type DB struct {
+	*sql.DB
+	dialect string
+}
+
+// Open opens a database connection from a DATABASE_URL style string.
+// "postgres://" and "postgresql://" URLs use PostgreSQL, while "sqlite://<path>",
+// "file:<path>" and ":memory:" use the embedded SQLite driver.
+func Open(databaseURL string) (*DB, error) {
+	var (
+		driver  string
+		dsn     string
+		dialect string
+	)
+
+	switch {
+	case strings.HasPrefix(databaseURL, "postgres://"), strings.HasPrefix(databaseURL, "postgresql://"):
+		driver, dsn, dialect = "pgx", databaseURL, DialectPostgres
+	case strings.HasPrefix(databaseURL, "sqlite://"):
+		driver, dsn, dialect = "sqlite", strings.TrimPrefix(databaseURL, "sqlite://"), DialectSQLite
+	case strings.HasPrefix(databaseURL, "file:"), databaseURL == ":memory:":
+		driver, dsn, dialect = "sqlite", databaseURL, DialectSQLite
+	default:
+		return nil, fmt.Errorf("unsupported database url: %q", databaseURL)
+	}
+
+	if dialect == DialectSQLite {
+		dsn = withSQLitePragmas(dsn)
+	}
+
+	sqlDB, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open database: %v", err)
+	}
+
+	if dialect == DialectSQLite {
+		// SQLiteは単一コネクションで書き込みを直列化する（:memory: の共有にも必要）
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("unable to connect database: %v", err)
+	}
+
+	return &DB{DB: sqlDB, dialect: dialect}, nil
+}
+
+// Dialect returns the SQL dialect of the connected database
+func (db *DB) Dialect() string {
+	return db.dialect
+}
+
+// Rebind converts "?" placeholders to the placeholder style of the dialect
+func (db *DB) Rebind(query string) string {
+	if db.dialect != DialectPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// withSQLitePragmas enables foreign keys and a busy timeout on SQLite connections
+func withSQLitePragmas(dsn string) string {
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return dsn + separator + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
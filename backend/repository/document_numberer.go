@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"line-estimate-backend/models"
+)
+
+// jst is the time zone that decides which day a document number belongs to
+var jst = time.FixedZone("JST", 9*60*60)
+
+// DocumentNumberer hands out sequential document numbers per document type and period.
+//
+// Sequences are stored in document_sequences and incremented with a single upsert,
+// so concurrent requests never receive the same number. When the number is allocated
+// inside the transaction that stores the document, a rolled back insert also rolls
+// back the sequence and no gap is left.
+type DocumentNumberer struct {
+	db    *DB
+	rules map[models.DocumentType]models.NumberingRule
+}
+
+// NewDocumentNumberer creates a DocumentNumberer. Document types without a rule
+// fall back to models.DefaultNumberingRules.
+func NewDocumentNumberer(db *DB, rules map[models.DocumentType]models.NumberingRule) (*DocumentNumberer, error) {
+	merged := make(map[models.DocumentType]models.NumberingRule, len(models.DefaultNumberingRules))
+	for docType, rule := range models.DefaultNumberingRules {
+		merged[docType] = rule
+	}
+	for docType, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid numbering rule for %s: %v", docType, err)
+		}
+		merged[docType] = rule
+	}
+
+	return &DocumentNumberer{db: db, rules: merged}, nil
+}
+
+// Next allocates the next number for a document issued at the given time in its own transaction
+func (n *DocumentNumberer) Next(ctx context.Context, docType models.DocumentType, at time.Time) (string, error) {
+	tx, err := n.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	number, err := n.nextTx(ctx, tx, docType, at)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("unable to commit document number: %v", err)
+	}
+	return number, nil
+}
+
+// nextTx allocates the next number within the caller's transaction
+func (n *DocumentNumberer) nextTx(ctx context.Context, tx *sql.Tx, docType models.DocumentType, at time.Time) (string, error) {
+	rule, ok := n.rules[docType]
+	if !ok {
+		return "", fmt.Errorf("no numbering rule for document type %q", docType)
+	}
+
+	key := string(docType) + ":" + rule.PeriodKey(at)
+	query := n.db.Rebind(`INSERT INTO document_sequences (sequence_key, last_value, updated_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT (sequence_key) DO UPDATE SET
+			last_value = document_sequences.last_value + 1,
+			updated_at = excluded.updated_at
+		RETURNING last_value`)
+
+	var seq int64
+	if err := tx.QueryRowContext(ctx, query, key, time.Now().UTC()).Scan(&seq); err != nil {
+		return "", fmt.Errorf("unable to allocate document number: %v", err)
+	}
+
+	return rule.FormatNumber(seq, at), nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"line-estimate-backend/models"
+)
+
+func TestDocumentNumbererConcurrent(t *testing.T) {
+	db, err := Open("sqlite://" + filepath.Join(t.TempDir(), "numbering.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Migrate())
+
+	numberer, err := NewDocumentNumberer(db, nil)
+	require.NoError(t, err)
+
+	at := time.Date(2026, 4, 1, 10, 0, 0, 0, jst)
+
+	const workers = 20
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		numbers []string
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			number, err := numberer.Next(context.Background(), models.DocumentTypeEstimate, at)
+			assert.NoError(t, err)
+			mu.Lock()
+			numbers = append(numbers, number)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	sort.Strings(numbers)
+	require.Len(t, numbers, workers)
+	assert.Equal(t, "EST-20260401-001", numbers[0])
+	assert.Equal(t, "EST-20260401-020", numbers[workers-1])
+	for i := 1; i < len(numbers); i++ {
+		assert.NotEqual(t, numbers[i-1], numbers[i])
+	}
+
+	// 翌日は1から採番し直す
+	next, err := numberer.Next(context.Background(), models.DocumentTypeEstimate, at.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, "EST-20260402-001", next)
+}
+
+func TestDocumentNumbererRules(t *testing.T) {
+	db, err := Open(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Migrate())
+
+	_, err = NewDocumentNumberer(db, map[models.DocumentType]models.NumberingRule{
+		models.DocumentTypeEstimate: {Format: "EST-{date}", Reset: models.ResetDaily},
+	})
+	assert.Error(t, err, "format without {seq} must be rejected")
+
+	numberer, err := NewDocumentNumberer(db, map[models.DocumentType]models.NumberingRule{
+		models.DocumentTypeInvoice: {Format: "{yy}{mm}-{seq:5}", Reset: models.ResetYearly},
+	})
+	require.NoError(t, err)
+
+	at := time.Date(2026, 12, 15, 23, 0, 0, 0, jst)
+	number, err := numberer.Next(context.Background(), models.DocumentTypeInvoice, at)
+	require.NoError(t, err)
+	assert.Equal(t, "2612-00001", number)
+
+	number, err = numberer.Next(context.Background(), models.DocumentTypeInvoice, at.AddDate(0, -6, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "2606-00002", number)
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"line-estimate-backend/models"
+)
+
+// AddDocument records an issued PDF of an estimate and sets its ID
+func (r *sqlEstimateRepository) AddDocument(ctx context.Context, document *models.EstimateDocument) error {
+	if document.CreatedAt.IsZero() {
+		document.CreatedAt = time.Now().UTC()
+	}
+
+	query := r.db.Rebind(`INSERT INTO estimate_documents
+		(estimate_id, revision, file_name, storage, location, created_at)
+		VALUES (?, ?, ?, ?, ?, ?) RETURNING id`)
+	err := r.db.QueryRowContext(ctx, query,
+		document.EstimateID,
+		document.Revision,
+		document.FileName,
+		document.Storage,
+		document.Location,
+		document.CreatedAt,
+	).Scan(&document.ID)
+	if err != nil {
+		return fmt.Errorf("unable to record estimate document: %v", err)
+	}
+	return nil
+}
+
+// LatestDocument returns the most recently issued PDF of an estimate
+func (r *sqlEstimateRepository) LatestDocument(ctx context.Context, id uint) (*models.EstimateDocument, error) {
+	row := r.db.QueryRowContext(ctx, r.db.Rebind(`SELECT id, estimate_id, revision, file_name, storage, location, created_at
+		FROM estimate_documents WHERE estimate_id = ? ORDER BY id DESC LIMIT 1`), id)
+
+	var document models.EstimateDocument
+	err := row.Scan(
+		&document.ID,
+		&document.EstimateID,
+		&document.Revision,
+		&document.FileName,
+		&document.Storage,
+		&document.Location,
+		&document.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get estimate document: %v", err)
+	}
+	return &document, nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// CountItemUsage returns the number of estimates using each catalog item.
+// Free-text lines without a catalog item ID are not counted.
+func (r *sqlEstimateRepository) CountItemUsage(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT item_id, COUNT(DISTINCT estimate_id) FROM estimate_items
+		WHERE item_id <> '' GROUP BY item_id`)
+	if err != nil {
+		return nil, fmt.Errorf("unable to count item usage: %v", err)
+	}
+	defer rows.Close()
+
+	usage := map[string]int{}
+	for rows.Next() {
+		var itemID string
+		var count int
+		if err := rows.Scan(&itemID, &count); err != nil {
+			return nil, fmt.Errorf("unable to scan item usage: %v", err)
+		}
+		usage[itemID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to count item usage: %v", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,588 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"line-estimate-backend/models"
+)
+
+// ErrNotFound is returned when the requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
+var (
+	// ErrEstimateNotDraft is returned when deleting an estimate that is no longer a draft
+	ErrEstimateNotDraft = errors.New("estimate is not a draft")
+	// ErrEstimateHasDocuments is returned when deleting an estimate that invoices, instructions
+	// or manifests have been issued for. Issued documents must be kept; manifests and their
+	// returned copies have a legal retention period.
+	ErrEstimateHasDocuments = errors.New("estimate has issued documents")
+)
+
+// EstimateRepository provides persistence for estimates
+type EstimateRepository interface {
+	List(ctx context.Context, filter models.EstimateFilter) (*models.EstimatePage, error)
+	Get(ctx context.Context, id uint) (*models.Estimate, error)
+	Create(ctx context.Context, estimate *models.Estimate) error
+	Update(ctx context.Context, estimate *models.Estimate) error
+	Delete(ctx context.Context, id uint) error
+	Transition(ctx context.Context, id uint, to models.EstimateStatus, note string) (*models.Estimate, error)
+	ListTransitions(ctx context.Context, id uint) ([]models.EstimateTransition, error)
+	ListRevisions(ctx context.Context, id uint) ([]models.EstimateRevision, error)
+	GetRevision(ctx context.Context, id uint, revision int) (*models.EstimateRevision, error)
+	SetRecyclingTickets(ctx context.Context, id uint, tickets map[int][]string) error
+	AddDocument(ctx context.Context, document *models.EstimateDocument) error
+	LatestDocument(ctx context.Context, id uint) (*models.EstimateDocument, error)
+	CountItemUsage(ctx context.Context) (map[string]int, error)
+}
+
+type sqlEstimateRepository struct {
+	db       *DB
+	numberer *DocumentNumberer
+}
+
+// NewEstimateRepository creates an EstimateRepository backed by the given database.
+// New estimates are numbered by numberer within the insert transaction.
+func NewEstimateRepository(db *DB, numberer *DocumentNumberer) EstimateRepository {
+	return &sqlEstimateRepository{db: db, numberer: numberer}
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+const estimateColumns = `id, estimate_no, user_id, title, description, total_lines, hourly_rate,
+	customer_name, customer_address, customer_phone, customer_email, disposal_date,
+	sub_total, tax_rate, tax, total_cost, price_basis, tax_rounding, tax_rounding_unit,
+	discount_type, discount_value, discount_label, discount_amount, job_conditions,
+	revision, status, created_at, updated_at`
+
+func scanEstimate(row rowScanner) (*models.Estimate, error) {
+	var (
+		estimate   models.Estimate
+		conditions string
+	)
+	if err := row.Scan(
+		&estimate.ID,
+		&estimate.EstimateNo,
+		&estimate.UserID,
+		&estimate.Title,
+		&estimate.Description,
+		&estimate.TotalLines,
+		&estimate.HourlyRate,
+		&estimate.Customer.Name,
+		&estimate.Customer.Address,
+		&estimate.Customer.Phone,
+		&estimate.Customer.Email,
+		&estimate.Customer.DisposalDate,
+		&estimate.SubTotal,
+		&estimate.TaxRate,
+		&estimate.Tax,
+		&estimate.TotalCost,
+		&estimate.Pricing.PriceBasis,
+		&estimate.Pricing.Rounding,
+		&estimate.Pricing.RoundingUnit,
+		&estimate.Discount.Type,
+		&estimate.Discount.Value,
+		&estimate.Discount.Label,
+		&estimate.DiscountAmount,
+		&conditions,
+		&estimate.Revision,
+		&estimate.Status,
+		&estimate.CreatedAt,
+		&estimate.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(conditions), &estimate.Conditions); err != nil {
+		return nil, fmt.Errorf("unable to decode job conditions of estimate %d: %v", estimate.ID, err)
+	}
+	return &estimate, nil
+}
+
+// Get returns the estimate with the given ID including its items
+func (r *sqlEstimateRepository) Get(ctx context.Context, id uint) (*models.Estimate, error) {
+	return r.get(ctx, r.db, id)
+}
+
+func (r *sqlEstimateRepository) get(ctx context.Context, q queryer, id uint) (*models.Estimate, error) {
+	row := q.QueryRowContext(ctx, r.db.Rebind("SELECT "+estimateColumns+" FROM estimates WHERE id = ?"), id)
+	estimate, err := scanEstimate(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get estimate: %v", err)
+	}
+
+	estimate.Items, err = r.listItems(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(estimate.Items) > 0 {
+		lines := models.WithDiscountLines(estimate.Items, estimate.Discount, estimate.DiscountAmount)
+		_, estimate.TaxBreakdown, _ = models.CalculateTax(lines, estimate.TaxRate, estimate.Pricing)
+	}
+	return estimate, nil
+}
+
+// Create inserts a new estimate and sets its ID and timestamps.
+// The initial status is recorded as the first transition and the content as revision 1.
+func (r *sqlEstimateRepository) Create(ctx context.Context, estimate *models.Estimate) error {
+	now := time.Now().UTC()
+	estimate.CreatedAt = now
+	estimate.UpdatedAt = now
+	estimate.Revision = 1
+	estimate.Pricing = estimate.Pricing.OrDefault()
+	if estimate.Status == "" {
+		estimate.Status = models.EstimateStatusDraft
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if estimate.EstimateNo == "" {
+		estimate.EstimateNo, err = r.numberer.nextTx(ctx, tx, models.DocumentTypeEstimate, now.In(jst))
+		if err != nil {
+			return err
+		}
+	}
+	conditions, err := json.Marshal(estimate.Conditions)
+	if err != nil {
+		return fmt.Errorf("unable to encode job conditions: %v", err)
+	}
+
+	query := r.db.Rebind(`INSERT INTO estimates
+		(estimate_no, user_id, title, description, total_lines, hourly_rate,
+		customer_name, customer_address, customer_phone, customer_email, disposal_date,
+		sub_total, tax_rate, tax, total_cost, price_basis, tax_rounding, tax_rounding_unit,
+		discount_type, discount_value, discount_label, discount_amount, job_conditions,
+		revision, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`)
+	err = tx.QueryRowContext(ctx, query,
+		estimate.EstimateNo,
+		estimate.UserID,
+		estimate.Title,
+		estimate.Description,
+		estimate.TotalLines,
+		estimate.HourlyRate,
+		estimate.Customer.Name,
+		estimate.Customer.Address,
+		estimate.Customer.Phone,
+		estimate.Customer.Email,
+		estimate.Customer.DisposalDate,
+		estimate.SubTotal,
+		estimate.TaxRate,
+		estimate.Tax,
+		estimate.TotalCost,
+		estimate.Pricing.PriceBasis,
+		estimate.Pricing.Rounding,
+		estimate.Pricing.RoundingUnit,
+		estimate.Discount.Type,
+		estimate.Discount.Value,
+		estimate.Discount.Label,
+		estimate.DiscountAmount,
+		string(conditions),
+		estimate.Revision,
+		estimate.Status,
+		estimate.CreatedAt,
+		estimate.UpdatedAt,
+	).Scan(&estimate.ID)
+	if err != nil {
+		return fmt.Errorf("unable to create estimate: %v", err)
+	}
+
+	if err := r.replaceItems(ctx, tx, estimate.ID, estimate.Items); err != nil {
+		return err
+	}
+
+	if err := r.insertRevision(ctx, tx, estimate.ID, estimate.Revision, estimate.Snapshot(), now); err != nil {
+		return err
+	}
+
+	transition := models.EstimateTransition{
+		EstimateID:     estimate.ID,
+		ToStatus:       estimate.Status,
+		TransitionedAt: now,
+	}
+	if err := r.insertTransition(ctx, tx, &transition); err != nil {
+		return err
+	}
+	estimate.Transitions = []models.EstimateTransition{transition}
+
+	return tx.Commit()
+}
+
+// Update saves the editable fields and items of an existing estimate.
+// A new revision is recorded whenever the content differs from the latest one.
+// The status is only changed through Transition.
+func (r *sqlEstimateRepository) Update(ctx context.Context, estimate *models.Estimate) error {
+	now := time.Now().UTC()
+	estimate.Pricing = estimate.Pricing.OrDefault()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	latest, err := r.latestRevision(ctx, tx, estimate.ID)
+	if err != nil {
+		return err
+	}
+
+	conditions, err := json.Marshal(estimate.Conditions)
+	if err != nil {
+		return fmt.Errorf("unable to encode job conditions: %v", err)
+	}
+
+	revision := latest.Revision
+	snapshot := estimate.Snapshot()
+	if !sameSnapshot(latest.Snapshot, snapshot) {
+		revision++
+		if err := r.insertRevision(ctx, tx, estimate.ID, revision, snapshot, now); err != nil {
+			return err
+		}
+	}
+
+	query := r.db.Rebind(`UPDATE estimates SET
+		user_id = ?, title = ?, description = ?, total_lines = ?, hourly_rate = ?,
+		customer_name = ?, customer_address = ?, customer_phone = ?, customer_email = ?, disposal_date = ?,
+		sub_total = ?, tax_rate = ?, tax = ?, total_cost = ?,
+		price_basis = ?, tax_rounding = ?, tax_rounding_unit = ?,
+		discount_type = ?, discount_value = ?, discount_label = ?, discount_amount = ?, job_conditions = ?,
+		revision = ?, updated_at = ?
+		WHERE id = ?`)
+	result, err := tx.ExecContext(ctx, query,
+		estimate.UserID,
+		estimate.Title,
+		estimate.Description,
+		estimate.TotalLines,
+		estimate.HourlyRate,
+		estimate.Customer.Name,
+		estimate.Customer.Address,
+		estimate.Customer.Phone,
+		estimate.Customer.Email,
+		estimate.Customer.DisposalDate,
+		estimate.SubTotal,
+		estimate.TaxRate,
+		estimate.Tax,
+		estimate.TotalCost,
+		estimate.Pricing.PriceBasis,
+		estimate.Pricing.Rounding,
+		estimate.Pricing.RoundingUnit,
+		estimate.Discount.Type,
+		estimate.Discount.Value,
+		estimate.Discount.Label,
+		estimate.DiscountAmount,
+		string(conditions),
+		revision,
+		now,
+		estimate.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to update estimate: %v", err)
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+
+	previous, err := r.listItems(ctx, tx, estimate.ID)
+	if err != nil {
+		return err
+	}
+	carryOverTickets(estimate.Items, previous)
+	if err := r.replaceItems(ctx, tx, estimate.ID, estimate.Items); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit estimate: %v", err)
+	}
+
+	estimate.Revision = revision
+	estimate.UpdatedAt = now
+	return nil
+}
+
+// Delete removes a draft estimate with the given ID. It returns ErrEstimateNotDraft for
+// estimates past draft and ErrEstimateHasDocuments when documents have been issued for it.
+func (r *sqlEstimateRepository) Delete(ctx context.Context, id uint) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var status models.EstimateStatus
+	err = tx.QueryRowContext(ctx, r.db.Rebind("SELECT status FROM estimates WHERE id = ?"), id).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get estimate: %v", err)
+	}
+	if status != models.EstimateStatusDraft {
+		return ErrEstimateNotDraft
+	}
+
+	for _, table := range estimateDocumentTables {
+		var count int
+		if err := tx.QueryRowContext(ctx, r.db.Rebind("SELECT COUNT(*) FROM "+table+" WHERE estimate_id = ?"), id).Scan(&count); err != nil {
+			return fmt.Errorf("unable to count %s: %v", table, err)
+		}
+		if count > 0 {
+			return ErrEstimateHasDocuments
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, r.db.Rebind("DELETE FROM estimates WHERE id = ?"), id)
+	if err != nil {
+		return fmt.Errorf("unable to delete estimate: %v", err)
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// estimateDocumentTables are the issued documents that keep an estimate from being deleted
+var estimateDocumentTables = []string{"invoices", "instructions", "manifests"}
+
+// listItems returns the items of an estimate in display order
+func (r *sqlEstimateRepository) listItems(ctx context.Context, q queryer, id uint) ([]models.EstimateItem, error) {
+	rows, err := q.QueryContext(ctx, r.db.Rebind(`SELECT item_id, description, category, specification, quantity, unit, unit_price, discount_type, discount_value, amount, tax_category,
+		recycling_class, recycling_manufacturer, recycling_size_class, recycling_ticket_numbers
+		FROM estimate_items WHERE estimate_id = ? ORDER BY position`), id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list estimate items: %v", err)
+	}
+	defer rows.Close()
+
+	items := []models.EstimateItem{}
+	for rows.Next() {
+		var (
+			item      models.EstimateItem
+			recycling models.ApplianceRecycling
+			tickets   string
+		)
+		if err := rows.Scan(&item.ItemID, &item.Description, &item.Category, &item.Specification, &item.Quantity, &item.Unit, &item.UnitPrice, &item.Discount.Type, &item.Discount.Value, &item.Amount, &item.TaxCategory,
+			&recycling.Class, &recycling.Manufacturer, &recycling.SizeClass, &tickets); err != nil {
+			return nil, fmt.Errorf("unable to scan estimate item: %v", err)
+		}
+		if recycling.Class != "" {
+			recycling.TicketNumbers = splitTicketNumbers(tickets)
+			item.Recycling = &recycling
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// replaceItems replaces all items of an estimate within the given transaction
+func (r *sqlEstimateRepository) replaceItems(ctx context.Context, tx *sql.Tx, id uint, items []models.EstimateItem) error {
+	if _, err := tx.ExecContext(ctx, r.db.Rebind("DELETE FROM estimate_items WHERE estimate_id = ?"), id); err != nil {
+		return fmt.Errorf("unable to delete estimate items: %v", err)
+	}
+
+	query := r.db.Rebind(`INSERT INTO estimate_items
+		(estimate_id, position, item_id, description, category, specification, quantity, unit, unit_price,
+		discount_type, discount_value, amount, tax_category,
+		recycling_class, recycling_manufacturer, recycling_size_class, recycling_ticket_numbers)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	for i, item := range items {
+		var recycling models.ApplianceRecycling
+		if item.Recycling != nil {
+			recycling = *item.Recycling
+		}
+		if _, err := tx.ExecContext(ctx, query,
+			id, i, item.ItemID, item.Description, item.Category, item.Specification, item.Quantity, item.Unit, item.UnitPrice,
+			item.Discount.Type, item.Discount.Value, item.Amount, item.TaxCategory.OrDefault(),
+			recycling.Class, recycling.Manufacturer, recycling.SizeClass, strings.Join(recycling.TicketNumbers, ","),
+		); err != nil {
+			return fmt.Errorf("unable to insert estimate item: %v", err)
+		}
+	}
+	return nil
+}
+
+// carryOverTickets keeps the recycling ticket numbers recorded on the stored lines when the
+// items are replaced. Tickets are only recorded through SetRecyclingTickets, so each appliance
+// line takes the tickets of the first unused stored line of the same item, appliance class,
+// manufacturer and size class, up to its quantity. Tickets of removed or changed lines are dropped.
+func carryOverTickets(items, previous []models.EstimateItem) {
+	used := make([]bool, len(previous))
+	for i := range items {
+		if items[i].Recycling == nil {
+			continue
+		}
+		recycling := *items[i].Recycling
+		recycling.TicketNumbers = nil
+		for j, old := range previous {
+			if used[j] || old.Recycling == nil || old.ItemID != items[i].ItemID ||
+				old.Recycling.Class != recycling.Class ||
+				old.Recycling.Manufacturer != recycling.Manufacturer ||
+				old.Recycling.SizeClass != recycling.SizeClass {
+				continue
+			}
+			used[j] = true
+			tickets := old.Recycling.TicketNumbers
+			if limit := int(items[i].Quantity); len(tickets) > limit {
+				tickets = tickets[:limit]
+			}
+			recycling.TicketNumbers = tickets
+			break
+		}
+		items[i].Recycling = &recycling
+	}
+}
+
+// SetRecyclingTickets records the recycling ticket numbers of the appliance lines at the
+// given 0-based positions. Ticket numbers are field records, so no revision is created.
+func (r *sqlEstimateRepository) SetRecyclingTickets(ctx context.Context, id uint, tickets map[int][]string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := r.db.Rebind(`UPDATE estimate_items SET recycling_ticket_numbers = ?
+		WHERE estimate_id = ? AND position = ? AND recycling_class <> ''`)
+	for position, numbers := range tickets {
+		result, err := tx.ExecContext(ctx, query, strings.Join(numbers, ","), id, position)
+		if err != nil {
+			return fmt.Errorf("unable to update recycling tickets: %v", err)
+		}
+		if err := requireAffected(result); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// splitTicketNumbers parses the comma separated ticket numbers of an item
+func splitTicketNumbers(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+// Transition moves an estimate to a new status if the lifecycle allows it
+// and records the change. It returns *models.TransitionError for illegal moves.
+func (r *sqlEstimateRepository) Transition(ctx context.Context, id uint, to models.EstimateStatus, note string) (*models.Estimate, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	estimate, err := r.get(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := estimate.Status
+	if !from.CanTransitionTo(to) {
+		return nil, &models.TransitionError{From: from, To: to}
+	}
+
+	now := time.Now().UTC()
+	// 同時更新で別の遷移が先に確定していないことを status 条件で保証する
+	result, err := tx.ExecContext(ctx, r.db.Rebind("UPDATE estimates SET status = ?, updated_at = ? WHERE id = ? AND status = ?"), to, now, id, from)
+	if err != nil {
+		return nil, fmt.Errorf("unable to update estimate status: %v", err)
+	}
+	if err := requireAffected(result); err != nil {
+		return nil, &models.TransitionError{From: from, To: to}
+	}
+
+	transition := models.EstimateTransition{
+		EstimateID:     id,
+		FromStatus:     from,
+		ToStatus:       to,
+		Note:           note,
+		TransitionedAt: now,
+	}
+	if err := r.insertTransition(ctx, tx, &transition); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to commit transition: %v", err)
+	}
+
+	estimate.Status = to
+	estimate.UpdatedAt = now
+	estimate.Transitions, err = r.ListTransitions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return estimate, nil
+}
+
+// ListTransitions returns the status history of an estimate, oldest first
+func (r *sqlEstimateRepository) ListTransitions(ctx context.Context, id uint) ([]models.EstimateTransition, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.Rebind(`SELECT id, estimate_id, from_status, to_status, note, transitioned_at
+		FROM estimate_status_transitions WHERE estimate_id = ? ORDER BY id`), id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list transitions: %v", err)
+	}
+	defer rows.Close()
+
+	transitions := []models.EstimateTransition{}
+	for rows.Next() {
+		var t models.EstimateTransition
+		if err := rows.Scan(&t.ID, &t.EstimateID, &t.FromStatus, &t.ToStatus, &t.Note, &t.TransitionedAt); err != nil {
+			return nil, fmt.Errorf("unable to scan transition: %v", err)
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, rows.Err()
+}
+
+// insertTransition records a status change within the given transaction
+func (r *sqlEstimateRepository) insertTransition(ctx context.Context, tx *sql.Tx, transition *models.EstimateTransition) error {
+	query := r.db.Rebind(`INSERT INTO estimate_status_transitions
+		(estimate_id, from_status, to_status, note, transitioned_at)
+		VALUES (?, ?, ?, ?, ?) RETURNING id`)
+	err := tx.QueryRowContext(ctx, query,
+		transition.EstimateID,
+		transition.FromStatus,
+		transition.ToStatus,
+		transition.Note,
+		transition.TransitionedAt,
+	).Scan(&transition.ID)
+	if err != nil {
+		return fmt.Errorf("unable to record transition: %v", err)
+	}
+	return nil
+}
+
+// requireAffected returns ErrNotFound when a statement did not touch any row
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
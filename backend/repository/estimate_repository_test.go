@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"line-estimate-backend/models"
+)
+
+func TestUpdateEstimateKeepsRecyclingTickets(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Migrate())
+
+	numberer, err := NewDocumentNumberer(db, nil)
+	require.NoError(t, err)
+	estimates := NewEstimateRepository(db, numberer)
+
+	tv := models.EstimateItem{ItemID: "tv", Description: "テレビ", Quantity: 2, UnitPrice: 3000,
+		Recycling: &models.ApplianceRecycling{Class: models.ApplianceTV, SizeClass: models.ApplianceSizeLarge}}
+	refrigerator := models.EstimateItem{ItemID: "refrigerator", Description: "冷蔵庫", Quantity: 1, UnitPrice: 6000,
+		Recycling: &models.ApplianceRecycling{Class: models.ApplianceRefrigerator, SizeClass: models.ApplianceSizeLarge}}
+	futon := models.EstimateItem{ItemID: "futon", Description: "布団", Quantity: 1, UnitPrice: 1000}
+	estimate := &models.Estimate{Title: "回収", Customer: models.EstimateCustomer{Name: "佐藤"},
+		Items: []models.EstimateItem{tv, refrigerator}}
+	require.NoError(t, estimates.Create(ctx, estimate))
+	require.NoError(t, estimates.SetRecyclingTickets(ctx, estimate.ID, map[int][]string{
+		0: {"1000000000001", "1000000000002"},
+		1: {"2000000000001"},
+	}))
+
+	// 行を並べ替え・追加しても同じ家電の行の券番号は残り、数量を超える分とメーカーを変えた行の券番号は外れる
+	tv.Quantity = 1
+	refrigerator.Recycling = &models.ApplianceRecycling{Class: models.ApplianceRefrigerator, Manufacturer: "パナソニック", SizeClass: models.ApplianceSizeLarge}
+	estimate.Items = []models.EstimateItem{futon, refrigerator, tv}
+	require.NoError(t, estimates.Update(ctx, estimate))
+
+	stored, err := estimates.Get(ctx, estimate.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Items, 3)
+	assert.Nil(t, stored.Items[0].Recycling)
+	assert.Empty(t, stored.Items[1].Recycling.TicketNumbers)
+	assert.Equal(t, []string{"1000000000001"}, stored.Items[2].Recycling.TicketNumbers)
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"line-estimate-backend/models"
+)
+
+// ListRevisions returns all revisions of an estimate, oldest first
+func (r *sqlEstimateRepository) ListRevisions(ctx context.Context, id uint) ([]models.EstimateRevision, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.Rebind(`SELECT id, estimate_id, revision, snapshot, created_at
+		FROM estimate_revisions WHERE estimate_id = ? ORDER BY revision`), id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list revisions: %v", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.EstimateRevision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *revision)
+	}
+	return revisions, rows.Err()
+}
+
+// GetRevision returns a single revision of an estimate
+func (r *sqlEstimateRepository) GetRevision(ctx context.Context, id uint, revision int) (*models.EstimateRevision, error) {
+	row := r.db.QueryRowContext(ctx, r.db.Rebind(`SELECT id, estimate_id, revision, snapshot, created_at
+		FROM estimate_revisions WHERE estimate_id = ? AND revision = ?`), id, revision)
+	rev, err := scanRevision(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return rev, err
+}
+
+// latestRevision returns the newest revision of an estimate within the given transaction
+func (r *sqlEstimateRepository) latestRevision(ctx context.Context, q queryer, id uint) (*models.EstimateRevision, error) {
+	row := q.QueryRowContext(ctx, r.db.Rebind(`SELECT id, estimate_id, revision, snapshot, created_at
+		FROM estimate_revisions WHERE estimate_id = ? ORDER BY revision DESC LIMIT 1`), id)
+	rev, err := scanRevision(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return rev, err
+}
+
+// insertRevision stores an immutable snapshot of the estimate
+func (r *sqlEstimateRepository) insertRevision(ctx context.Context, tx *sql.Tx, id uint, revision int, snapshot models.EstimateSnapshot, createdAt time.Time) error {
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("unable to encode revision: %v", err)
+	}
+
+	_, err = tx.ExecContext(ctx, r.db.Rebind(`INSERT INTO estimate_revisions
+		(estimate_id, revision, snapshot, created_at) VALUES (?, ?, ?, ?)`),
+		id, revision, string(body), createdAt)
+	if err != nil {
+		return fmt.Errorf("unable to insert revision: %v", err)
+	}
+	return nil
+}
+
+func scanRevision(row rowScanner) (*models.EstimateRevision, error) {
+	var (
+		revision models.EstimateRevision
+		snapshot string
+	)
+	if err := row.Scan(&revision.ID, &revision.EstimateID, &revision.Revision, &snapshot, &revision.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unable to scan revision: %v", err)
+	}
+	if err := json.Unmarshal([]byte(snapshot), &revision.Snapshot); err != nil {
+		return nil, fmt.Errorf("unable to decode revision %d: %v", revision.Revision, err)
+	}
+	return &revision, nil
+}
+
+// sameSnapshot reports whether two snapshots have identical content
+func sameSnapshot(a, b models.EstimateSnapshot) bool {
+	// nil と空の明細、税区分や計算方法の省略と既定値を同一視するため正規化してから JSON で比較する
+	a.Items = normalizeSnapshotItems(a.Items)
+	b.Items = normalizeSnapshotItems(b.Items)
+	a.Pricing = a.Pricing.OrDefault()
+	b.Pricing = b.Pricing.OrDefault()
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+// normalizeSnapshotItems returns a copy of the items with default values filled in,
+// so snapshots stored before a field was added compare equal to current ones
+func normalizeSnapshotItems(items []models.EstimateItem) []models.EstimateItem {
+	normalized := make([]models.EstimateItem, len(items))
+	for i, item := range items {
+		item.TaxCategory = item.TaxCategory.OrDefault()
+		if item.Recycling != nil {
+			// リサイクル券番号は回収時に記録するもので見積もりの内容ではない
+			recycling := *item.Recycling
+			recycling.TicketNumbers = nil
+			item.Recycling = &recycling
+		}
+		normalized[i] = item
+	}
+	return normalized
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"line-estimate-backend/models"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+// or was issued for a different sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// estimateSortColumns maps the public sort keys to columns.
+// created_at は id と同順なので id だけで並べる（SQLite では created_at が文字列比較になるため）
+var estimateSortColumns = map[string]string{
+	models.EstimateSortCreatedAt:  "",
+	models.EstimateSortTotal:      "total_cost",
+	models.EstimateSortCustomer:   "customer_name",
+	models.EstimateSortEstimateNo: "estimate_no",
+}
+
+// estimateCursor is the decoded form of the opaque cursor handed to clients.
+// It holds the sort value and ID of the last row of the previous page.
+type estimateCursor struct {
+	Sort       string          `json:"s"`
+	Descending bool            `json:"d"`
+	Value      json.RawMessage `json:"v,omitempty"`
+	ID         uint            `json:"id"`
+}
+
+// List returns a page of estimates matching the filter. Items are not loaded.
+// Pages are addressed by keyset cursors on (sort column, id), so concurrent
+// inserts never shift or duplicate rows between pages.
+func (r *sqlEstimateRepository) List(ctx context.Context, filter models.EstimateFilter) (*models.EstimatePage, error) {
+	column, ok := estimateSortColumns[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort: %s", filter.Sort)
+	}
+
+	where, args := estimateFilterConditions(filter)
+
+	page := &models.EstimatePage{Estimates: []models.Estimate{}}
+	countQuery := "SELECT COUNT(*) FROM estimates" + whereClause(where)
+	if err := r.db.QueryRowContext(ctx, r.db.Rebind(countQuery), args...).Scan(&page.Total); err != nil {
+		return nil, fmt.Errorf("unable to count estimates: %v", err)
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := decodeEstimateCursor(filter.Cursor)
+		if err != nil || cursor.Sort != filter.Sort || cursor.Descending != filter.Descending {
+			return nil, ErrInvalidCursor
+		}
+		condition, cursorArgs, err := cursorCondition(column, filter.Sort, filter.Descending, cursor)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, condition)
+		args = append(args, cursorArgs...)
+	}
+
+	direction := "ASC"
+	if filter.Descending {
+		direction = "DESC"
+	}
+	orderBy := "id " + direction
+	if column != "" {
+		orderBy = column + " " + direction + ", " + orderBy
+	}
+
+	query := "SELECT " + estimateColumns + " FROM estimates" + whereClause(where) +
+		" ORDER BY " + orderBy + " LIMIT ?"
+	args = append(args, filter.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list estimates: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		estimate, err := scanEstimate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("unable to scan estimate: %v", err)
+		}
+		page.Estimates = append(page.Estimates, *estimate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Estimates) > filter.Limit {
+		page.Estimates = page.Estimates[:filter.Limit]
+		page.HasMore = true
+		page.NextCursor, err = encodeEstimateCursor(filter, page.Estimates[len(page.Estimates)-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// estimateFilterConditions builds the WHERE conditions and their arguments for a filter
+func estimateFilterConditions(filter models.EstimateFilter) ([]string, []any) {
+	var (
+		where []string
+		args  []any
+	)
+
+	if len(filter.Statuses) > 0 {
+		placeholders := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			placeholders[i] = "?"
+			args = append(args, status)
+		}
+		where = append(where, "status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if filter.CreatedFrom != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, filter.CreatedFrom.UTC())
+	}
+	if filter.CreatedTo != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, filter.CreatedTo.UTC())
+	}
+	if filter.Customer != "" {
+		where = append(where, `LOWER(customer_name) LIKE LOWER(?) ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(filter.Customer)+"%")
+	}
+	if filter.Phone != "" {
+		where = append(where, `REPLACE(customer_phone, '-', '') LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(filter.Phone)+"%")
+	}
+	if filter.UserID != 0 {
+		where = append(where, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.MinTotal != nil {
+		where = append(where, "total_cost >= ?")
+		args = append(args, *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		where = append(where, "total_cost <= ?")
+		args = append(args, *filter.MaxTotal)
+	}
+
+	return where, args
+}
+
+// cursorCondition returns the keyset condition selecting the rows after the cursor
+func cursorCondition(column, sort string, descending bool, cursor *estimateCursor) (string, []any, error) {
+	op := ">"
+	if descending {
+		op = "<"
+	}
+	if column == "" {
+		return "id " + op + " ?", []any{cursor.ID}, nil
+	}
+
+	var value any
+	if sort == models.EstimateSortTotal {
+		var total float64
+		if err := json.Unmarshal(cursor.Value, &total); err != nil {
+			return "", nil, ErrInvalidCursor
+		}
+		value = total
+	} else {
+		var text string
+		if err := json.Unmarshal(cursor.Value, &text); err != nil {
+			return "", nil, ErrInvalidCursor
+		}
+		value = text
+	}
+
+	condition := fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", column, op, column, op)
+	return condition, []any{value, value, cursor.ID}, nil
+}
+
+func encodeEstimateCursor(filter models.EstimateFilter, last models.Estimate) (string, error) {
+	cursor := estimateCursor{Sort: filter.Sort, Descending: filter.Descending, ID: last.ID}
+
+	var value any
+	switch filter.Sort {
+	case models.EstimateSortTotal:
+		value = last.TotalCost
+	case models.EstimateSortCustomer:
+		value = last.Customer.Name
+	case models.EstimateSortEstimateNo:
+		value = last.EstimateNo
+	}
+	if value != nil {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("unable to encode cursor: %v", err)
+		}
+		cursor.Value = raw
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode cursor: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeEstimateCursor(encoded string) (*estimateCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var cursor estimateCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// escapeLike escapes the LIKE wildcards so user input is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
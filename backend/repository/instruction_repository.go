@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"line-estimate-backend/models"
+)
+
+// InstructionRepository provides persistence for work instruction sheets
+type InstructionRepository interface {
+	Create(ctx context.Context, instruction *models.Instruction) error
+	Get(ctx context.Context, id uint) (*models.Instruction, error)
+	GetByNumber(ctx context.Context, instructionNo string) (*models.Instruction, error)
+	SetArchive(ctx context.Context, id uint, fileName string, storage models.DocumentStorage, location string) error
+	ListByEstimate(ctx context.Context, estimateID uint) ([]models.Instruction, error)
+}
+
+type sqlInstructionRepository struct {
+	db       *DB
+	numberer *DocumentNumberer
+}
+
+// NewInstructionRepository creates an InstructionRepository backed by the given database.
+// Instructions without a number are numbered by numberer within the insert transaction.
+func NewInstructionRepository(db *DB, numberer *DocumentNumberer) InstructionRepository {
+	return &sqlInstructionRepository{db: db, numberer: numberer}
+}
+
+// Create stores a new instruction sheet and sets its ID and number
+func (r *sqlInstructionRepository) Create(ctx context.Context, instruction *models.Instruction) error {
+	instruction.CreatedAt = time.Now().UTC()
+	if instruction.Content.IssueDate.IsZero() {
+		instruction.Content.IssueDate = instruction.CreatedAt.In(jst)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if instruction.InstructionNo == "" {
+		instruction.InstructionNo, err = r.numberer.nextTx(ctx, tx, models.DocumentTypeInstruction, instruction.Content.IssueDate.In(jst))
+		if err != nil {
+			return err
+		}
+	}
+	instruction.Content.InstructionNo = instruction.InstructionNo
+
+	content, err := json.Marshal(instruction.Content)
+	if err != nil {
+		return fmt.Errorf("unable to encode instruction: %v", err)
+	}
+
+	query := r.db.Rebind(`INSERT INTO instructions
+		(instruction_no, estimate_id, content, file_name, storage, location, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id`)
+	err = tx.QueryRowContext(ctx, query,
+		instruction.InstructionNo,
+		instruction.EstimateID,
+		string(content),
+		instruction.FileName,
+		instruction.Storage,
+		instruction.Location,
+		instruction.CreatedAt,
+	).Scan(&instruction.ID)
+	if err != nil {
+		return fmt.Errorf("unable to create instruction: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// SetArchive records where the PDF of an instruction sheet was stored
+func (r *sqlInstructionRepository) SetArchive(ctx context.Context, id uint, fileName string, storage models.DocumentStorage, location string) error {
+	result, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE instructions SET file_name = ?, storage = ?, location = ? WHERE id = ?"),
+		fileName, storage, location, id)
+	if err != nil {
+		return fmt.Errorf("unable to update instruction: %v", err)
+	}
+	return requireAffected(result)
+}
+
+const instructionColumns = `id, instruction_no, estimate_id, content, file_name, storage, location, created_at`
+
+func scanInstruction(row rowScanner) (*models.Instruction, error) {
+	var (
+		instruction models.Instruction
+		content     string
+	)
+	if err := row.Scan(
+		&instruction.ID,
+		&instruction.InstructionNo,
+		&instruction.EstimateID,
+		&content,
+		&instruction.FileName,
+		&instruction.Storage,
+		&instruction.Location,
+		&instruction.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(content), &instruction.Content); err != nil {
+		return nil, fmt.Errorf("unable to decode instruction %d: %v", instruction.ID, err)
+	}
+	return &instruction, nil
+}
+
+// Get returns the instruction sheet with the given ID
+func (r *sqlInstructionRepository) Get(ctx context.Context, id uint) (*models.Instruction, error) {
+	row := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT "+instructionColumns+" FROM instructions WHERE id = ?"), id)
+	instruction, err := scanInstruction(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get instruction: %v", err)
+	}
+	return instruction, nil
+}
+
+// GetByNumber returns the instruction sheet with the given number
+func (r *sqlInstructionRepository) GetByNumber(ctx context.Context, instructionNo string) (*models.Instruction, error) {
+	row := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT "+instructionColumns+" FROM instructions WHERE instruction_no = ?"), instructionNo)
+	instruction, err := scanInstruction(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get instruction: %v", err)
+	}
+	return instruction, nil
+}
+
+// ListByEstimate returns the instruction sheets issued from an estimate, oldest first
+func (r *sqlInstructionRepository) ListByEstimate(ctx context.Context, estimateID uint) ([]models.Instruction, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.Rebind("SELECT "+instructionColumns+" FROM instructions WHERE estimate_id = ? ORDER BY id"), estimateID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list instructions: %v", err)
+	}
+	defer rows.Close()
+
+	instructions := []models.Instruction{}
+	for rows.Next() {
+		instruction, err := scanInstruction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("unable to scan instruction: %v", err)
+		}
+		instructions = append(instructions, *instruction)
+	}
+	return instructions, rows.Err()
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"line-estimate-backend/models"
+)
+
+// InvoiceRepository provides persistence for invoices
+type InvoiceRepository interface {
+	Create(ctx context.Context, invoice *models.Invoice) error
+	SetArchive(ctx context.Context, id uint, fileName string, storage models.DocumentStorage, location string) error
+	ListByEstimate(ctx context.Context, estimateID uint) ([]models.Invoice, error)
+}
+
+type sqlInvoiceRepository struct {
+	db       *DB
+	numberer *DocumentNumberer
+}
+
+// NewInvoiceRepository creates an InvoiceRepository backed by the given database.
+// Invoices without a number are numbered by numberer within the insert transaction.
+func NewInvoiceRepository(db *DB, numberer *DocumentNumberer) InvoiceRepository {
+	return &sqlInvoiceRepository{db: db, numberer: numberer}
+}
+
+// Create stores a new invoice and sets its ID and number
+func (r *sqlInvoiceRepository) Create(ctx context.Context, invoice *models.Invoice) error {
+	invoice.CreatedAt = time.Now().UTC()
+	if invoice.IssueDate.IsZero() {
+		invoice.IssueDate = invoice.CreatedAt.In(jst)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if invoice.InvoiceNo == "" {
+		invoice.InvoiceNo, err = r.numberer.nextTx(ctx, tx, models.DocumentTypeInvoice, invoice.IssueDate.In(jst))
+		if err != nil {
+			return err
+		}
+	}
+
+	content, err := json.Marshal(invoice)
+	if err != nil {
+		return fmt.Errorf("unable to encode invoice: %v", err)
+	}
+
+	query := r.db.Rebind(`INSERT INTO invoices
+		(invoice_no, estimate_id, issue_date, due_date, total, content, file_name, storage, location, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`)
+	err = tx.QueryRowContext(ctx, query,
+		invoice.InvoiceNo,
+		invoice.EstimateID,
+		invoice.IssueDate.UTC(),
+		invoice.DueDate.UTC(),
+		invoice.Total,
+		string(content),
+		invoice.FileName,
+		invoice.Storage,
+		invoice.Location,
+		invoice.CreatedAt,
+	).Scan(&invoice.ID)
+	if err != nil {
+		return fmt.Errorf("unable to create invoice: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// SetArchive records where the PDF of an invoice was stored
+func (r *sqlInvoiceRepository) SetArchive(ctx context.Context, id uint, fileName string, storage models.DocumentStorage, location string) error {
+	result, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE invoices SET file_name = ?, storage = ?, location = ? WHERE id = ?"),
+		fileName, storage, location, id)
+	if err != nil {
+		return fmt.Errorf("unable to update invoice: %v", err)
+	}
+	return requireAffected(result)
+}
+
+// ListByEstimate returns the invoices issued for an estimate, oldest first
+func (r *sqlInvoiceRepository) ListByEstimate(ctx context.Context, estimateID uint) ([]models.Invoice, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.Rebind(`SELECT id, invoice_no, estimate_id, content, file_name, storage, location, created_at
+		FROM invoices WHERE estimate_id = ? ORDER BY id`), estimateID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list invoices: %v", err)
+	}
+	defer rows.Close()
+
+	invoices := []models.Invoice{}
+	for rows.Next() {
+		var (
+			invoice models.Invoice
+			stored  models.Invoice
+			content string
+		)
+		if err := rows.Scan(
+			&stored.ID,
+			&stored.InvoiceNo,
+			&stored.EstimateID,
+			&content,
+			&stored.FileName,
+			&stored.Storage,
+			&stored.Location,
+			&stored.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("unable to scan invoice: %v", err)
+		}
+		if err := json.Unmarshal([]byte(content), &invoice); err != nil {
+			return nil, fmt.Errorf("unable to decode invoice %d: %v", stored.ID, err)
+		}
+
+		// 保管先などは登録後に更新されるため列の値を優先する
+		invoice.ID = stored.ID
+		invoice.InvoiceNo = stored.InvoiceNo
+		invoice.EstimateID = stored.EstimateID
+		invoice.FileName = stored.FileName
+		invoice.Storage = stored.Storage
+		invoice.Location = stored.Location
+		invoice.CreatedAt = stored.CreatedAt
+		invoices = append(invoices, invoice)
+	}
+	return invoices, rows.Err()
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"line-estimate-backend/models"
+)
+
+// ManifestRepository provides persistence for industrial waste manifests
+type ManifestRepository interface {
+	Create(ctx context.Context, manifest *models.Manifest) error
+	Get(ctx context.Context, id uint) (*models.Manifest, error)
+	List(ctx context.Context, filter models.ManifestFilter) ([]models.Manifest, error)
+	RecordReturn(ctx context.Context, id uint, copy models.ManifestCopy, completedOn, returnedOn string) error
+}
+
+type sqlManifestRepository struct {
+	db *DB
+}
+
+// NewManifestRepository creates a ManifestRepository backed by the given database
+func NewManifestRepository(db *DB) ManifestRepository {
+	return &sqlManifestRepository{db: db}
+}
+
+const manifestColumns = `id, manifest_no, instruction_id, estimate_id, issue_date, discharger, site_name, site_address,
+	waste_type, quantity, unit, packing_form, transporter, disposer, disposal_method, special_control, note,
+	b2_completed_on, b2_returned_on, d_completed_on, d_returned_on, e_completed_on, e_returned_on,
+	created_at, updated_at`
+
+// manifestStatusConditions selects the manifests in each status by the latest returned copy
+var manifestStatusConditions = map[models.ManifestStatus]string{
+	models.ManifestStatusIssued:      "b2_returned_on = '' AND d_returned_on = '' AND e_returned_on = ''",
+	models.ManifestStatusTransported: "b2_returned_on <> '' AND d_returned_on = '' AND e_returned_on = ''",
+	models.ManifestStatusDisposed:    "d_returned_on <> '' AND e_returned_on = ''",
+	models.ManifestStatusCompleted:   "e_returned_on <> ''",
+}
+
+func scanManifest(row rowScanner) (*models.Manifest, error) {
+	manifest := models.Manifest{Stages: models.NewManifestStages()}
+	if err := row.Scan(
+		&manifest.ID,
+		&manifest.ManifestNo,
+		&manifest.InstructionID,
+		&manifest.EstimateID,
+		&manifest.IssueDate,
+		&manifest.Discharger,
+		&manifest.SiteName,
+		&manifest.SiteAddress,
+		&manifest.WasteType,
+		&manifest.Quantity,
+		&manifest.Unit,
+		&manifest.PackingForm,
+		&manifest.Transporter,
+		&manifest.Disposer,
+		&manifest.DisposalMethod,
+		&manifest.SpecialControl,
+		&manifest.Note,
+		&manifest.Stages[0].CompletedOn,
+		&manifest.Stages[0].ReturnedOn,
+		&manifest.Stages[1].CompletedOn,
+		&manifest.Stages[1].ReturnedOn,
+		&manifest.Stages[2].CompletedOn,
+		&manifest.Stages[2].ReturnedOn,
+		&manifest.CreatedAt,
+		&manifest.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// Create stores a new manifest and sets its ID. It returns ErrDuplicate when the
+// manifest number has already been registered.
+func (r *sqlManifestRepository) Create(ctx context.Context, manifest *models.Manifest) error {
+	manifest.CreatedAt = time.Now().UTC()
+	manifest.UpdatedAt = manifest.CreatedAt
+	if len(manifest.Stages) == 0 {
+		manifest.Stages = models.NewManifestStages()
+	}
+
+	query := r.db.Rebind(`INSERT INTO manifests
+		(manifest_no, instruction_id, estimate_id, issue_date, discharger, site_name, site_address,
+		waste_type, quantity, unit, packing_form, transporter, disposer, disposal_method, special_control, note,
+		created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`)
+	err := r.db.QueryRowContext(ctx, query,
+		manifest.ManifestNo,
+		manifest.InstructionID,
+		manifest.EstimateID,
+		manifest.IssueDate,
+		manifest.Discharger,
+		manifest.SiteName,
+		manifest.SiteAddress,
+		manifest.WasteType,
+		manifest.Quantity,
+		manifest.Unit,
+		manifest.PackingForm,
+		manifest.Transporter,
+		manifest.Disposer,
+		manifest.DisposalMethod,
+		manifest.SpecialControl,
+		manifest.Note,
+		manifest.CreatedAt,
+		manifest.UpdatedAt,
+	).Scan(&manifest.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("unable to create manifest: %v", err)
+	}
+	return nil
+}
+
+// Get returns the manifest with the given ID
+func (r *sqlManifestRepository) Get(ctx context.Context, id uint) (*models.Manifest, error) {
+	row := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT "+manifestColumns+" FROM manifests WHERE id = ?"), id)
+	manifest, err := scanManifest(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get manifest: %v", err)
+	}
+	return manifest, nil
+}
+
+// List returns the manifests matching the filter ordered by issue date
+func (r *sqlManifestRepository) List(ctx context.Context, filter models.ManifestFilter) ([]models.Manifest, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if filter.Status != "" {
+		condition, ok := manifestStatusConditions[filter.Status]
+		if !ok {
+			return nil, fmt.Errorf("unknown manifest status: %s", filter.Status)
+		}
+		where = append(where, condition)
+	}
+	if filter.EstimateID != 0 {
+		where = append(where, "estimate_id = ?")
+		args = append(args, filter.EstimateID)
+	}
+	if filter.IssuedFrom != "" {
+		where = append(where, "issue_date >= ?")
+		args = append(args, filter.IssuedFrom)
+	}
+	if filter.IssuedTo != "" {
+		where = append(where, "issue_date <= ?")
+		args = append(args, filter.IssuedTo)
+	}
+
+	query := "SELECT " + manifestColumns + " FROM manifests" + whereClause(where) + " ORDER BY issue_date, id"
+
+	rows, err := r.db.QueryContext(ctx, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list manifests: %v", err)
+	}
+	defer rows.Close()
+
+	manifests := []models.Manifest{}
+	for rows.Next() {
+		manifest, err := scanManifest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("unable to scan manifest: %v", err)
+		}
+		manifests = append(manifests, *manifest)
+	}
+	return manifests, rows.Err()
+}
+
+// RecordReturn records the completion date written on a returned copy and the date it was received
+func (r *sqlManifestRepository) RecordReturn(ctx context.Context, id uint, copy models.ManifestCopy, completedOn, returnedOn string) error {
+	var prefix string
+	switch copy {
+	case models.ManifestCopyB2:
+		prefix = "b2"
+	case models.ManifestCopyD:
+		prefix = "d"
+	case models.ManifestCopyE:
+		prefix = "e"
+	default:
+		return fmt.Errorf("unknown manifest copy: %s", copy)
+	}
+
+	query := fmt.Sprintf("UPDATE manifests SET %[1]s_completed_on = ?, %[1]s_returned_on = ?, updated_at = ? WHERE id = ?", prefix)
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(query), completedOn, returnedOn, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("unable to update manifest: %v", err)
+	}
+	return requireAffected(result)
+}
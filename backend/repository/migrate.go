@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// Migrate applies all pending migrations for the connected dialect.
+// Each migration file runs in its own transaction and is recorded in schema_migrations.
+func (db *DB) Migrate() error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    VARCHAR(255) PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("unable to create schema_migrations: %v", err)
+	}
+
+	dir := path.Join("migrations", db.dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return fmt.Errorf("unable to read migrations: %v", err)
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+			versions = append(versions, entry.Name())
+		}
+	}
+	sort.Strings(versions)
+
+	for _, version := range versions {
+		var count int
+		if err := db.QueryRow(db.Rebind("SELECT COUNT(*) FROM schema_migrations WHERE version = ?"), version).Scan(&count); err != nil {
+			return fmt.Errorf("unable to check migration %s: %v", version, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		body, err := migrationFiles.ReadFile(path.Join(dir, version))
+		if err != nil {
+			return fmt.Errorf("unable to read migration %s: %v", version, err)
+		}
+
+		if err := db.applyMigration(version, string(body)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyMigration executes a single migration file and records its version
+func (db *DB) applyMigration(version, body string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range splitStatements(body) {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("migration %s failed: %v", version, err)
+		}
+	}
+
+	if _, err := tx.Exec(db.Rebind("INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)"), version, time.Now().UTC()); err != nil {
+		return fmt.Errorf("unable to record migration %s: %v", version, err)
+	}
+
+	return tx.Commit()
+}
+
+// splitStatements splits a migration file into statements, dropping "--" comment lines
+func splitStatements(body string) []string {
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var statements []string
+	for _, statement := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if strings.TrimSpace(statement) != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
@@ -0,0 +1,15 @@
+-- 見積もりテーブル
+CREATE TABLE IF NOT EXISTS estimates (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL DEFAULT 0,
+    title       TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    total_lines INTEGER NOT NULL DEFAULT 0,
+    hourly_rate DOUBLE PRECISION NOT NULL DEFAULT 0,
+    total_cost  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    status      VARCHAR(32) NOT NULL DEFAULT 'draft',
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_estimates_user_id ON estimates (user_id);
@@ -0,0 +1,13 @@
+-- 書類番号の採番テーブル（書類種別と期間ごとの最終値）
+CREATE TABLE IF NOT EXISTS document_sequences (
+    sequence_key VARCHAR(255) PRIMARY KEY,
+    last_value   BIGINT NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL
+);
+
+-- 見積書番号
+ALTER TABLE estimates ADD COLUMN estimate_no VARCHAR(64) NOT NULL DEFAULT '';
+
+UPDATE estimates SET estimate_no = 'EST-' || lpad(id::text, 6, '0') WHERE estimate_no = '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_estimates_estimate_no ON estimates (estimate_no);
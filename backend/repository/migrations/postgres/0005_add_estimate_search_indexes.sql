@@ -0,0 +1,5 @@
+-- 見積もり一覧の検索・並び替え用インデックス
+CREATE INDEX IF NOT EXISTS idx_estimates_status ON estimates (status, id);
+CREATE INDEX IF NOT EXISTS idx_estimates_created_at ON estimates (created_at);
+CREATE INDEX IF NOT EXISTS idx_estimates_total_cost ON estimates (total_cost, id);
+CREATE INDEX IF NOT EXISTS idx_estimates_customer_name ON estimates (customer_name, id);
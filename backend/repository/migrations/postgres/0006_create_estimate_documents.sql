@@ -0,0 +1,12 @@
+-- 発行済み見積書PDFの保管先（ローカルまたはGoogle Drive）
+CREATE TABLE IF NOT EXISTS estimate_documents (
+    id          BIGSERIAL PRIMARY KEY,
+    estimate_id BIGINT NOT NULL REFERENCES estimates (id) ON DELETE CASCADE,
+    revision    INTEGER NOT NULL,
+    file_name   TEXT NOT NULL,
+    storage     TEXT NOT NULL,
+    location    TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_estimate_documents_estimate_id ON estimate_documents (estimate_id);
@@ -0,0 +1,2 @@
+-- 明細ごとの税区分（standard: 標準税率, reduced: 軽減税率）
+ALTER TABLE estimate_items ADD COLUMN tax_category TEXT NOT NULL DEFAULT 'standard';
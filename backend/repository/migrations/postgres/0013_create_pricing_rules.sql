@@ -0,0 +1,29 @@
+-- 作業条件による割増ルール（管理画面から編集する）
+CREATE TABLE IF NOT EXISTS pricing_rules (
+    id           BIGSERIAL PRIMARY KEY,
+    kind         TEXT NOT NULL UNIQUE,
+    name         TEXT NOT NULL,
+    unit         TEXT NOT NULL DEFAULT '',
+    amount       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    percent      DOUBLE PRECISION NOT NULL DEFAULT 0,
+    threshold    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    step         DOUBLE PRECISION NOT NULL DEFAULT 0,
+    tax_category TEXT NOT NULL DEFAULT 'standard',
+    enabled      BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order   INTEGER NOT NULL DEFAULT 0,
+    updated_at   TIMESTAMPTZ NOT NULL
+);
+
+INSERT INTO pricing_rules (kind, name, unit, amount, percent, threshold, step, sort_order, updated_at) VALUES
+    ('floor', '階段作業費（エレベーターなし）', '階', 1000, 0, 1, 0, 10, CURRENT_TIMESTAMP),
+    ('parking_distance', '横持ち作業費', '10m', 1000, 0, 20, 10, 20, CURRENT_TIMESTAMP),
+    ('disassembly', '解体作業費', '点', 2000, 0, 0, 0, 30, CURRENT_TIMESTAMP),
+    ('same_day', '当日対応割増', '式', 0, 20, 0, 0, 40, CURRENT_TIMESTAMP),
+    ('night', '夜間作業割増', '式', 0, 25, 0, 0, 50, CURRENT_TIMESTAMP),
+    ('minimum_charge', '最低料金調整', '式', 10000, 0, 0, 0, 60, CURRENT_TIMESTAMP);
+
+-- 最低料金は事業者ごとに異なるため、金額を確認してから有効にする
+UPDATE pricing_rules SET enabled = FALSE WHERE kind = 'minimum_charge';
+
+-- 見積もりの作業条件 (JSON)
+ALTER TABLE estimates ADD COLUMN job_conditions TEXT NOT NULL DEFAULT '{}';
@@ -0,0 +1,15 @@
+-- 収集に使う車両と1台あたりの収集運搬費
+CREATE TABLE IF NOT EXISTS vehicles (
+    id         BIGSERIAL PRIMARY KEY,
+    code       TEXT NOT NULL UNIQUE,
+    name       TEXT NOT NULL,
+    capacity   DOUBLE PRECISION NOT NULL,
+    max_load   DOUBLE PRECISION NOT NULL,
+    price      DOUBLE PRECISION NOT NULL DEFAULT 0,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO vehicles (code, name, capacity, max_load, price, sort_order) VALUES
+    ('light', '軽トラック', 3.5, 350, 15000, 10),
+    ('2t', '2tトラック', 10, 2000, 30000, 20),
+    ('4t', '4tトラック', 20, 4000, 50000, 30);
@@ -0,0 +1,25 @@
+-- 家電リサイクル法の品目のリサイクル料金（メーカー・サイズ区分ごと）
+-- メーカーが空の行は料金表にないメーカーに使う標準料金。各メーカーの料金は管理画面から登録する
+CREATE TABLE IF NOT EXISTS recycling_fees (
+    id              BIGSERIAL PRIMARY KEY,
+    appliance_class TEXT NOT NULL,
+    manufacturer    TEXT NOT NULL DEFAULT '',
+    size_class      TEXT NOT NULL DEFAULT '',
+    fee             DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at      TIMESTAMPTZ NOT NULL,
+    UNIQUE (appliance_class, manufacturer, size_class)
+);
+
+INSERT INTO recycling_fees (appliance_class, manufacturer, size_class, fee, updated_at) VALUES
+    ('tv', '', 'small', 2970, CURRENT_TIMESTAMP),
+    ('tv', '', 'large', 3700, CURRENT_TIMESTAMP),
+    ('refrigerator', '', 'small', 3740, CURRENT_TIMESTAMP),
+    ('refrigerator', '', 'large', 4730, CURRENT_TIMESTAMP),
+    ('washing_machine', '', '', 2530, CURRENT_TIMESTAMP),
+    ('air_conditioner', '', '', 990, CURRENT_TIMESTAMP);
+
+-- 明細ごとの家電リサイクル法の情報とリサイクル券番号（カンマ区切り）
+ALTER TABLE estimate_items ADD COLUMN recycling_class TEXT NOT NULL DEFAULT '';
+ALTER TABLE estimate_items ADD COLUMN recycling_manufacturer TEXT NOT NULL DEFAULT '';
+ALTER TABLE estimate_items ADD COLUMN recycling_size_class TEXT NOT NULL DEFAULT '';
+ALTER TABLE estimate_items ADD COLUMN recycling_ticket_numbers TEXT NOT NULL DEFAULT '';
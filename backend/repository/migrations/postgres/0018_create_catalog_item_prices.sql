@@ -0,0 +1,13 @@
+-- 品目マスタの価格改定の履歴。見積もり時点の価格を引けるよう、アイテムを削除しても残す
+CREATE TABLE IF NOT EXISTS catalog_item_prices (
+    item_id        TEXT NOT NULL,
+    effective_from TEXT NOT NULL,
+    price          INTEGER NOT NULL,
+    note           TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (item_id, effective_from)
+);
+
+-- 既存のアイテムの価格は履歴を記録する前から有効だったものとする
+INSERT INTO catalog_item_prices (item_id, effective_from, price, note, created_at)
+SELECT id, '2000-01-01', price, '', updated_at FROM catalog_items;
@@ -0,0 +1,15 @@
+-- 見積もりテーブル
+CREATE TABLE IF NOT EXISTS estimates (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER NOT NULL DEFAULT 0,
+    title       TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    total_lines INTEGER NOT NULL DEFAULT 0,
+    hourly_rate REAL NOT NULL DEFAULT 0,
+    total_cost  REAL NOT NULL DEFAULT 0,
+    status      TEXT NOT NULL DEFAULT 'draft',
+    created_at  TIMESTAMP NOT NULL,
+    updated_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_estimates_user_id ON estimates (user_id);
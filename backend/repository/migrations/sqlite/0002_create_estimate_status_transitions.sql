@@ -0,0 +1,14 @@
+-- 見積もりステータス遷移履歴
+CREATE TABLE IF NOT EXISTS estimate_status_transitions (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    estimate_id     INTEGER NOT NULL REFERENCES estimates (id) ON DELETE CASCADE,
+    from_status     TEXT NOT NULL DEFAULT '',
+    to_status       TEXT NOT NULL,
+    note            TEXT NOT NULL DEFAULT '',
+    transitioned_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_estimate_status_transitions_estimate_id ON estimate_status_transitions (estimate_id);
+
+-- 旧モックの "completed" はライフサイクル上の "closed" に読み替える
+UPDATE estimates SET status = 'closed' WHERE status = 'completed';
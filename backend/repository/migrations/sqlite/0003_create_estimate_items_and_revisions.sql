@@ -0,0 +1,50 @@
+-- 見積もりの顧客情報・税額・版数
+ALTER TABLE estimates ADD COLUMN customer_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE estimates ADD COLUMN customer_address TEXT NOT NULL DEFAULT '';
+ALTER TABLE estimates ADD COLUMN customer_phone TEXT NOT NULL DEFAULT '';
+ALTER TABLE estimates ADD COLUMN customer_email TEXT NOT NULL DEFAULT '';
+ALTER TABLE estimates ADD COLUMN disposal_date TEXT NOT NULL DEFAULT '';
+ALTER TABLE estimates ADD COLUMN sub_total REAL NOT NULL DEFAULT 0;
+ALTER TABLE estimates ADD COLUMN tax_rate REAL NOT NULL DEFAULT 0;
+ALTER TABLE estimates ADD COLUMN tax REAL NOT NULL DEFAULT 0;
+ALTER TABLE estimates ADD COLUMN revision INTEGER NOT NULL DEFAULT 1;
+
+-- 見積もり明細
+CREATE TABLE IF NOT EXISTS estimate_items (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    estimate_id   INTEGER NOT NULL REFERENCES estimates (id) ON DELETE CASCADE,
+    position      INTEGER NOT NULL,
+    item_id       TEXT NOT NULL DEFAULT '',
+    description   TEXT NOT NULL,
+    specification TEXT NOT NULL DEFAULT '',
+    quantity      REAL NOT NULL DEFAULT 0,
+    unit          TEXT NOT NULL DEFAULT '',
+    unit_price    REAL NOT NULL DEFAULT 0,
+    amount        REAL NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_estimate_items_estimate_id ON estimate_items (estimate_id);
+
+-- 見積もりの版（変更不可のスナップショット）
+CREATE TABLE IF NOT EXISTS estimate_revisions (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    estimate_id INTEGER NOT NULL REFERENCES estimates (id) ON DELETE CASCADE,
+    revision    INTEGER NOT NULL,
+    snapshot    TEXT NOT NULL,
+    created_at  TIMESTAMP NOT NULL,
+    UNIQUE (estimate_id, revision)
+);
+
+-- 既存の見積もりを第1版として登録
+INSERT INTO estimate_revisions (estimate_id, revision, snapshot, created_at)
+SELECT id, 1, json_object(
+    'title', title,
+    'description', description,
+    'customer', json_object('name', '', 'address', '', 'phone', '', 'email', '', 'disposal_date', ''),
+    'items', json('[]'),
+    'sub_total', 0,
+    'tax_rate', 0,
+    'tax', 0,
+    'total', total_cost
+), updated_at
+FROM estimates;
@@ -0,0 +1,13 @@
+-- 書類番号の採番テーブル（書類種別と期間ごとの最終値）
+CREATE TABLE IF NOT EXISTS document_sequences (
+    sequence_key TEXT PRIMARY KEY,
+    last_value   INTEGER NOT NULL,
+    updated_at   TIMESTAMP NOT NULL
+);
+
+-- 見積書番号
+ALTER TABLE estimates ADD COLUMN estimate_no TEXT NOT NULL DEFAULT '';
+
+UPDATE estimates SET estimate_no = 'EST-' || printf('%06d', id) WHERE estimate_no = '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_estimates_estimate_no ON estimates (estimate_no);
@@ -0,0 +1,13 @@
+-- 見積もりから発行した作業指示書
+CREATE TABLE IF NOT EXISTS instructions (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    instruction_no TEXT NOT NULL UNIQUE,
+    estimate_id    INTEGER NOT NULL REFERENCES estimates (id) ON DELETE RESTRICT,
+    content        TEXT NOT NULL,
+    file_name      TEXT NOT NULL DEFAULT '',
+    storage        TEXT NOT NULL DEFAULT '',
+    location       TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_instructions_estimate_id ON instructions (estimate_id);
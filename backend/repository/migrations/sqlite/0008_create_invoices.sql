@@ -0,0 +1,17 @@
+-- 完了した作業の請求書
+CREATE TABLE IF NOT EXISTS invoices (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_no  TEXT NOT NULL UNIQUE,
+    estimate_id INTEGER NOT NULL REFERENCES estimates (id) ON DELETE RESTRICT,
+    issue_date  TIMESTAMP NOT NULL,
+    due_date    TIMESTAMP NOT NULL,
+    total       REAL NOT NULL DEFAULT 0,
+    content     TEXT NOT NULL,
+    file_name   TEXT NOT NULL DEFAULT '',
+    storage     TEXT NOT NULL DEFAULT '',
+    location    TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_estimate_id ON invoices (estimate_id);
+CREATE INDEX IF NOT EXISTS idx_invoices_due_date ON invoices (due_date);
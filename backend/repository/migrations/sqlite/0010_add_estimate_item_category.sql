@@ -0,0 +1,2 @@
+-- 明細のカタログカテゴリー名（自由入力の明細は空）
+ALTER TABLE estimate_items ADD COLUMN category TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- 見積もりの計算方法（税込/税抜、端数処理、端数処理の単位）
+ALTER TABLE estimates ADD COLUMN price_basis TEXT NOT NULL DEFAULT 'exclusive';
+ALTER TABLE estimates ADD COLUMN tax_rounding TEXT NOT NULL DEFAULT 'floor';
+ALTER TABLE estimates ADD COLUMN tax_rounding_unit TEXT NOT NULL DEFAULT 'document';
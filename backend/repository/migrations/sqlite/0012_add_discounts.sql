@@ -0,0 +1,7 @@
+-- 明細と書類全体の値引き
+ALTER TABLE estimate_items ADD COLUMN discount_type TEXT NOT NULL DEFAULT '';
+ALTER TABLE estimate_items ADD COLUMN discount_value REAL NOT NULL DEFAULT 0;
+ALTER TABLE estimates ADD COLUMN discount_type TEXT NOT NULL DEFAULT '';
+ALTER TABLE estimates ADD COLUMN discount_value REAL NOT NULL DEFAULT 0;
+ALTER TABLE estimates ADD COLUMN discount_label TEXT NOT NULL DEFAULT '';
+ALTER TABLE estimates ADD COLUMN discount_amount REAL NOT NULL DEFAULT 0;
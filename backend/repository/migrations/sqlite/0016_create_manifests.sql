@@ -0,0 +1,32 @@
+-- 作業指示書に紐づく産業廃棄物管理票（マニフェスト）
+-- 日付は写しに記載された年月日をYYYY-MM-DDで保存する。未返送の写しは空文字
+CREATE TABLE IF NOT EXISTS manifests (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    manifest_no     TEXT NOT NULL UNIQUE,
+    instruction_id  INTEGER NOT NULL REFERENCES instructions (id) ON DELETE RESTRICT,
+    estimate_id     INTEGER NOT NULL REFERENCES estimates (id) ON DELETE RESTRICT,
+    issue_date      TEXT NOT NULL,
+    discharger      TEXT NOT NULL DEFAULT '',
+    site_name       TEXT NOT NULL DEFAULT '',
+    site_address    TEXT NOT NULL DEFAULT '',
+    waste_type      TEXT NOT NULL,
+    quantity        REAL NOT NULL DEFAULT 0,
+    unit            TEXT NOT NULL DEFAULT '',
+    packing_form    TEXT NOT NULL DEFAULT '',
+    transporter     TEXT NOT NULL DEFAULT '',
+    disposer        TEXT NOT NULL DEFAULT '',
+    disposal_method TEXT NOT NULL DEFAULT '',
+    special_control INTEGER NOT NULL DEFAULT 0,
+    note            TEXT NOT NULL DEFAULT '',
+    b2_completed_on TEXT NOT NULL DEFAULT '',
+    b2_returned_on  TEXT NOT NULL DEFAULT '',
+    d_completed_on  TEXT NOT NULL DEFAULT '',
+    d_returned_on   TEXT NOT NULL DEFAULT '',
+    e_completed_on  TEXT NOT NULL DEFAULT '',
+    e_returned_on   TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_manifests_instruction_id ON manifests (instruction_id);
+CREATE INDEX IF NOT EXISTS idx_manifests_issue_date ON manifests (issue_date);
@@ -0,0 +1,26 @@
+-- 管理画面から編集する品目マスタ。カテゴリーが1件以上あればGoogleスプレッドシートより優先する
+CREATE TABLE IF NOT EXISTS catalog_categories (
+    id         TEXT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    hiragana   TEXT NOT NULL DEFAULT '',
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    active     INTEGER NOT NULL DEFAULT 1,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS catalog_items (
+    id              TEXT PRIMARY KEY,
+    category_id     TEXT NOT NULL REFERENCES catalog_categories (id),
+    name            TEXT NOT NULL,
+    hiragana        TEXT NOT NULL DEFAULT '',
+    price           INTEGER NOT NULL DEFAULT 0,
+    unit            TEXT NOT NULL DEFAULT '',
+    volume          REAL NOT NULL DEFAULT 0,
+    weight          REAL NOT NULL DEFAULT 0,
+    recycling_class TEXT NOT NULL DEFAULT '',
+    sort_order      INTEGER NOT NULL DEFAULT 0,
+    active          INTEGER NOT NULL DEFAULT 1,
+    updated_at      TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_items_category_id ON catalog_items (category_id);
@@ -0,0 +1,2 @@
+-- 単価は価格改定の履歴 (catalog_item_prices) だけに持ち、二重管理による食い違いを防ぐ
+ALTER TABLE catalog_items DROP COLUMN price;
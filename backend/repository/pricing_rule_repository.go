@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"line-estimate-backend/models"
+)
+
+// PricingRuleRepository provides persistence for the job condition pricing rules
+type PricingRuleRepository interface {
+	List(ctx context.Context) ([]models.PricingRule, error)
+	Get(ctx context.Context, id uint) (*models.PricingRule, error)
+	Update(ctx context.Context, rule *models.PricingRule) error
+}
+
+type sqlPricingRuleRepository struct {
+	db *DB
+}
+
+// NewPricingRuleRepository creates a PricingRuleRepository backed by the given database
+func NewPricingRuleRepository(db *DB) PricingRuleRepository {
+	return &sqlPricingRuleRepository{db: db}
+}
+
+const pricingRuleColumns = `id, kind, name, unit, amount, percent, threshold, step,
+	tax_category, enabled, sort_order, updated_at`
+
+func scanPricingRule(row rowScanner) (*models.PricingRule, error) {
+	var rule models.PricingRule
+	if err := row.Scan(
+		&rule.ID,
+		&rule.Kind,
+		&rule.Name,
+		&rule.Unit,
+		&rule.Amount,
+		&rule.Percent,
+		&rule.Threshold,
+		&rule.Step,
+		&rule.TaxCategory,
+		&rule.Enabled,
+		&rule.SortOrder,
+		&rule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// List returns all pricing rules in the order they are applied
+func (r *sqlPricingRuleRepository) List(ctx context.Context) ([]models.PricingRule, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+pricingRuleColumns+" FROM pricing_rules ORDER BY sort_order, id")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list pricing rules: %v", err)
+	}
+	defer rows.Close()
+
+	rules := []models.PricingRule{}
+	for rows.Next() {
+		rule, err := scanPricingRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("unable to scan pricing rule: %v", err)
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+// Get returns the pricing rule with the given ID
+func (r *sqlPricingRuleRepository) Get(ctx context.Context, id uint) (*models.PricingRule, error) {
+	row := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT "+pricingRuleColumns+" FROM pricing_rules WHERE id = ?"), id)
+	rule, err := scanPricingRule(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pricing rule: %v", err)
+	}
+	return rule, nil
+}
+
+// Update saves the editable fields of a pricing rule. The kind cannot be changed.
+func (r *sqlPricingRuleRepository) Update(ctx context.Context, rule *models.PricingRule) error {
+	rule.UpdatedAt = time.Now().UTC()
+	rule.TaxCategory = rule.TaxCategory.OrDefault()
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(`UPDATE pricing_rules SET
+		name = ?, unit = ?, amount = ?, percent = ?, threshold = ?, step = ?,
+		tax_category = ?, enabled = ?, sort_order = ?, updated_at = ?
+		WHERE id = ?`),
+		rule.Name,
+		rule.Unit,
+		rule.Amount,
+		rule.Percent,
+		rule.Threshold,
+		rule.Step,
+		rule.TaxCategory,
+		rule.Enabled,
+		rule.SortOrder,
+		rule.UpdatedAt,
+		rule.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to update pricing rule: %v", err)
+	}
+	return requireAffected(result)
+}
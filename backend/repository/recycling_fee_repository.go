@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"line-estimate-backend/models"
+)
+
+// ErrDuplicate is returned when a record with the same unique key already exists
+var ErrDuplicate = errors.New("duplicate record")
+
+// RecyclingFeeRepository provides persistence for the recycling fee table
+type RecyclingFeeRepository interface {
+	List(ctx context.Context) ([]models.RecyclingFee, error)
+	Create(ctx context.Context, fee *models.RecyclingFee) error
+	Update(ctx context.Context, fee *models.RecyclingFee) error
+}
+
+type sqlRecyclingFeeRepository struct {
+	db *DB
+}
+
+// NewRecyclingFeeRepository creates a RecyclingFeeRepository backed by the given database
+func NewRecyclingFeeRepository(db *DB) RecyclingFeeRepository {
+	return &sqlRecyclingFeeRepository{db: db}
+}
+
+// List returns the fee table ordered by appliance class, manufacturer and size class
+func (r *sqlRecyclingFeeRepository) List(ctx context.Context) ([]models.RecyclingFee, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, appliance_class, manufacturer, size_class, fee, updated_at
+		FROM recycling_fees ORDER BY appliance_class, manufacturer, size_class`)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list recycling fees: %v", err)
+	}
+	defer rows.Close()
+
+	fees := []models.RecyclingFee{}
+	for rows.Next() {
+		var fee models.RecyclingFee
+		if err := rows.Scan(&fee.ID, &fee.Class, &fee.Manufacturer, &fee.SizeClass, &fee.Fee, &fee.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("unable to scan recycling fee: %v", err)
+		}
+		fees = append(fees, fee)
+	}
+	return fees, rows.Err()
+}
+
+// Create adds a row to the fee table. It returns ErrDuplicate when the manufacturer
+// already has a fee for the appliance and size class.
+func (r *sqlRecyclingFeeRepository) Create(ctx context.Context, fee *models.RecyclingFee) error {
+	fee.UpdatedAt = time.Now().UTC()
+	err := r.db.QueryRowContext(ctx, r.db.Rebind(`INSERT INTO recycling_fees
+		(appliance_class, manufacturer, size_class, fee, updated_at)
+		VALUES (?, ?, ?, ?, ?) RETURNING id`),
+		fee.Class, fee.Manufacturer, fee.SizeClass, fee.Fee, fee.UpdatedAt,
+	).Scan(&fee.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("unable to create recycling fee: %v", err)
+	}
+	return nil
+}
+
+// Update saves a row of the fee table
+func (r *sqlRecyclingFeeRepository) Update(ctx context.Context, fee *models.RecyclingFee) error {
+	fee.UpdatedAt = time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(`UPDATE recycling_fees SET
+		appliance_class = ?, manufacturer = ?, size_class = ?, fee = ?, updated_at = ?
+		WHERE id = ?`),
+		fee.Class, fee.Manufacturer, fee.SizeClass, fee.Fee, fee.UpdatedAt, fee.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("unable to update recycling fee: %v", err)
+	}
+	return requireAffected(result)
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation of either driver
+func isUniqueViolation(err error) bool {
+	message := err.Error()
+	return strings.Contains(message, "UNIQUE constraint failed") || strings.Contains(message, "SQLSTATE 23505")
+}
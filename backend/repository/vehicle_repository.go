@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"line-estimate-backend/models"
+)
+
+// VehicleRepository provides the collection vehicles used for load planning
+type VehicleRepository interface {
+	List(ctx context.Context) ([]models.Vehicle, error)
+}
+
+type sqlVehicleRepository struct {
+	db *DB
+}
+
+// NewVehicleRepository creates a VehicleRepository backed by the given database
+func NewVehicleRepository(db *DB) VehicleRepository {
+	return &sqlVehicleRepository{db: db}
+}
+
+// List returns all vehicles from the smallest to the largest
+func (r *sqlVehicleRepository) List(ctx context.Context) ([]models.Vehicle, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, code, name, capacity, max_load, price, sort_order
+		FROM vehicles ORDER BY sort_order, id`)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list vehicles: %v", err)
+	}
+	defer rows.Close()
+
+	vehicles := []models.Vehicle{}
+	for rows.Next() {
+		var vehicle models.Vehicle
+		if err := rows.Scan(
+			&vehicle.ID,
+			&vehicle.Code,
+			&vehicle.Name,
+			&vehicle.Capacity,
+			&vehicle.MaxLoad,
+			&vehicle.Price,
+			&vehicle.SortOrder,
+		); err != nil {
+			return nil, fmt.Errorf("unable to scan vehicle: %v", err)
+		}
+		vehicles = append(vehicles, vehicle)
+	}
+	return vehicles, rows.Err()
+}
@@ -6,7 +6,7 @@ import (
 )
 
 // SetupPDFRoutes PDFに関連するルートを設定
-func SetupPDFRoutes(r *gin.Engine) {
+func SetupPDFRoutes(r *gin.Engine, estimateHandler *handlers.EstimateHandler, instructionHandler *handlers.InstructionHandler) {
 	// API グループ
 	api := r.Group("/api")
 	{
@@ -17,10 +17,10 @@ func SetupPDFRoutes(r *gin.Engine) {
 			pdf.POST("/test", handlers.CreatePDF)
 
 			// 見積書PDF生成（POST /api/pdf/estimate）
-			pdf.POST("/estimate", handlers.CreateEstimatePDF)
+			pdf.POST("/estimate", estimateHandler.CreateEstimatePDF)
 
 			// 作業指示書PDF生成（POST /api/pdf/instruction）
-			pdf.POST("/instruction", handlers.CreateInstructionPDF)
+			pdf.POST("/instruction", instructionHandler.CreateInstructionPDF)
 		}
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -20,25 +21,9 @@ type DriveService struct {
 func NewDriveService() (*DriveService, error) {
 	ctx := context.Background()
 
-	// サービスアカウントキーの環境変数から取得
-	serviceAccountKey := os.Getenv("GOOGLE_SERVICE_ACCOUNT_KEY")
-	if serviceAccountKey == "" {
-		return nil, fmt.Errorf("GOOGLE_SERVICE_ACCOUNT_KEY environment variable is not set")
-	}
-
-	var jsonCredentials []byte
-	var err error
-
-	// ファイルパスかJSONかを判定
-	if strings.HasPrefix(serviceAccountKey, "{") {
-		// JSON文字列の場合
-		jsonCredentials = []byte(serviceAccountKey)
-	} else {
-		// ファイルパスの場合
-		jsonCredentials, err = os.ReadFile(serviceAccountKey)
-		if err != nil {
-			return nil, fmt.Errorf("unable to read service account key file: %v", err)
-		}
+	jsonCredentials, err := serviceAccountKey()
+	if err != nil {
+		return nil, err
 	}
 
 	// サービスアカウントの認証情報を作成
@@ -62,6 +47,29 @@ func NewDriveService() (*DriveService, error) {
 	}, nil
 }
 
+// serviceAccountKey reads the service account key from GOOGLE_SERVICE_ACCOUNT_KEY,
+// which holds either the JSON itself or the path to the key file
+func serviceAccountKey() ([]byte, error) {
+	// サービスアカウントキーの環境変数から取得
+	serviceAccountKey := os.Getenv("GOOGLE_SERVICE_ACCOUNT_KEY")
+	if serviceAccountKey == "" {
+		return nil, fmt.Errorf("GOOGLE_SERVICE_ACCOUNT_KEY environment variable is not set")
+	}
+
+	// ファイルパスかJSONかを判定
+	if strings.HasPrefix(serviceAccountKey, "{") {
+		// JSON文字列の場合
+		return []byte(serviceAccountKey), nil
+	}
+
+	// ファイルパスの場合
+	jsonCredentials, err := os.ReadFile(serviceAccountKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read service account key file: %v", err)
+	}
+	return jsonCredentials, nil
+}
+
 // UploadFile uploads a file to Google Drive
 func (ds *DriveService) UploadFile(fileName string, mimeType string, data []byte) (*drive.File, error) {
 	// PDFフォルダIDを環境変数から取得（オプション）
@@ -148,3 +156,20 @@ func (ds *DriveService) CreateFolder(folderName string, parentFolderID string) (
 
 	return createdFolder, nil
 }
+
+// DownloadFile downloads the content of a file by ID
+func (ds *DriveService) DownloadFile(fileID string) ([]byte, error) {
+	resp, err := ds.service.Files.Get(fileID).
+		SupportsAllDrives(true).
+		Download()
+	if err != nil {
+		return nil, fmt.Errorf("unable to download file: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file content: %v", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// SheetsService writes to Google Sheets with the service account.
+// Reading the catalog only needs the API key, but writing requires the
+// service account to be shared on the spreadsheet as an editor.
+type SheetsService struct {
+	service *sheets.Service
+	ctx     context.Context
+}
+
+func NewSheetsService() (*SheetsService, error) {
+	ctx := context.Background()
+
+	jsonCredentials, err := serviceAccountKey()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := google.JWTConfigFromJSON(jsonCredentials, sheets.SpreadsheetsScope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse service account key: %v", err)
+	}
+
+	srv, err := sheets.NewService(ctx, option.WithHTTPClient(config.Client(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve Sheets service: %v", err)
+	}
+
+	return &SheetsService{
+		service: srv,
+		ctx:     ctx,
+	}, nil
+}
+
+// ReplaceValues clears the range and writes the rows from its top-left cell
+func (ss *SheetsService) ReplaceValues(spreadsheetID, writeRange string, rows [][]string) error {
+	if _, err := ss.service.Spreadsheets.Values.Clear(spreadsheetID, writeRange, &sheets.ClearValuesRequest{}).Do(); err != nil {
+		return fmt.Errorf("unable to clear sheet: %v", err)
+	}
+
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = make([]interface{}, len(row))
+		for j, cell := range row {
+			values[i][j] = cell
+		}
+	}
+
+	// RAW で書き込み、ID や読みが数式・日付として解釈されないようにする
+	_, err := ss.service.Spreadsheets.Values.Update(spreadsheetID, writeRange, &sheets.ValueRange{Values: values}).
+		ValueInputOption("RAW").
+		Do()
+	if err != nil {
+		return fmt.Errorf("unable to write sheet: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Combining voiced sound marks produced by NFD (が = か + U+3099)
+const (
+	combiningDakuten    = '゙'
+	combiningHandakuten = '゚'
+	prolongedSoundMark  = 'ー'
+)
+
+// smallKana maps small hiragana to their full-size forms
+var smallKana = map[rune]rune{
+	'ぁ': 'あ', 'ぃ': 'い', 'ぅ': 'う', 'ぇ': 'え', 'ぉ': 'お',
+	'っ': 'つ', 'ゃ': 'や', 'ゅ': 'ゆ', 'ょ': 'よ', 'ゎ': 'わ',
+	'ゕ': 'か', 'ゖ': 'け',
+}
+
+// kanaVowels maps each full-size unvoiced hiragana to the vowel of its 五十音 column,
+// used to give the prolonged sound mark (ー) the vowel of the preceding kana
+var kanaVowels = func() map[rune]rune {
+	vowels := map[rune]rune{}
+	for vowel, row := range map[rune]string{
+		'あ': "あかさたなはまやらわ",
+		'い': "いきしちにひみりゐ",
+		'う': "うくすつぬふむゆる",
+		'え': "えけせてねへめれゑ",
+		'お': "おこそとのほもよろを",
+	} {
+		for _, r := range row {
+			vowels[r] = vowel
+		}
+	}
+	return vowels
+}()
+
+// collationKey holds the levels used to compare strings in 五十音 order.
+// The primary level folds katakana, small kana, voiced marks and ー; ties are
+// broken by voiced marks, then small kana and ー, then hiragana before katakana.
+type collationKey struct {
+	primary    []rune
+	secondary  []byte // 0: 清音, 1: 濁音, 2: 半濁音
+	tertiary   []byte // 0: 小書き, 1: 通常, 2: 長音
+	quaternary []byte // 0: ひらがな・その他, 1: カタカナ
+}
+
+func newCollationKey(s string) collationKey {
+	var key collationKey
+	for _, r := range norm.NFD.String(norm.NFKC.String(s)) {
+		switch {
+		case r == combiningDakuten || r == combiningHandakuten:
+			if n := len(key.secondary); n > 0 {
+				key.secondary[n-1] = byte(r - combiningDakuten + 1)
+			}
+			continue
+		case r == prolongedSoundMark:
+			vowel := r
+			if n := len(key.primary); n > 0 {
+				if v, ok := kanaVowels[key.primary[n-1]]; ok {
+					vowel = v
+				}
+			}
+			key.append(vowel, 0, 2, 1)
+			continue
+		}
+
+		var quaternary byte
+		if r >= 'ァ' && r <= 'ヶ' {
+			r -= 'ァ' - 'ぁ'
+			quaternary = 1
+		}
+		tertiary := byte(1)
+		if full, ok := smallKana[r]; ok {
+			r, tertiary = full, 0
+		}
+		key.append(unicode.ToLower(r), 0, tertiary, quaternary)
+	}
+	return key
+}
+
+func (k *collationKey) append(r rune, secondary, tertiary, quaternary byte) {
+	k.primary = append(k.primary, r)
+	k.secondary = append(k.secondary, secondary)
+	k.tertiary = append(k.tertiary, tertiary)
+	k.quaternary = append(k.quaternary, quaternary)
+}
+
+func (k collationKey) compare(other collationKey) int {
+	if c := compareRunes(k.primary, other.primary); c != 0 {
+		return c
+	}
+	for _, level := range [][2][]byte{
+		{k.secondary, other.secondary},
+		{k.tertiary, other.tertiary},
+		{k.quaternary, other.quaternary},
+	} {
+		if c := strings.Compare(string(level[0]), string(level[1])); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareRunes(a, b []rune) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// CompareJapanese compares two readings in 五十音 order (JIS X 4061 style):
+// hiragana and katakana are folded, voiced kana follow their unvoiced form,
+// small kana precede full-size ones and ー takes the vowel of the preceding kana.
+func CompareJapanese(a, b string) int {
+	if c := newCollationKey(a).compare(newCollationKey(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// SortJapanese sorts a slice stably by the reading returned by key in 五十音 order.
+// The collation keys are computed once per element.
+func SortJapanese[T any](items []T, key func(T) string) {
+	keys := make([]collationKey, len(items))
+	for i, item := range items {
+		keys[i] = newCollationKey(key(item))
+	}
+	indexes := make([]int, len(items))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return keys[indexes[i]].compare(keys[indexes[j]]) < 0
+	})
+
+	sorted := make([]T, len(items))
+	for i, index := range indexes {
+		sorted[i] = items[index]
+	}
+	copy(items, sorted)
+}
+
+// NormalizeKana folds a search string for matching: full-width and half-width forms are
+// unified (NFKC), katakana becomes hiragana and latin letters are lowercased.
+// Voiced marks are kept, so ば does not match は.
+func NormalizeKana(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKC.String(s) {
+		if r >= 'ァ' && r <= 'ヶ' {
+			r -= 'ァ' - 'ぁ'
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// hepburn is the Hepburn romanization of each hiragana
+var hepburn = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n", 'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa",
+}
+
+// ToRomaji converts a reading into Hepburn romaji for romaji search, e.g. れいぞうこ → reizouko.
+// Long vowels are written as they are spelled in kana and ー becomes "-" as typed with an IME.
+func ToRomaji(reading string) string {
+	runes := []rune(NormalizeKana(reading))
+	var b strings.Builder
+	geminate := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch r {
+		case 'っ':
+			geminate = true
+			continue
+		case prolongedSoundMark:
+			b.WriteByte('-')
+			continue
+		}
+
+		romaji, ok := hepburn[r]
+		if !ok {
+			b.WriteRune(r)
+			geminate = false
+			continue
+		}
+		if i+1 < len(runes) {
+			if small, ok := hepburn[runes[i+1]]; ok && smallKana[runes[i+1]] != 0 && len(romaji) > 1 {
+				romaji = contractRomaji(romaji, small)
+				i++
+			}
+		}
+		if geminate && romaji != "" && !strings.ContainsRune("aiueon", rune(romaji[0])) {
+			if strings.HasPrefix(romaji, "ch") {
+				b.WriteByte('t')
+			} else {
+				b.WriteByte(romaji[0])
+			}
+		}
+		geminate = false
+		b.WriteString(romaji)
+	}
+	return b.String()
+}
+
+// contractRomaji joins a kana with the small kana following it, e.g. き+ゃ → kya, し+ょ → sho, ふ+ぁ → fa
+func contractRomaji(base, small string) string {
+	stem := base[:len(base)-1]
+	if strings.HasSuffix(base, "i") && strings.HasPrefix(small, "y") {
+		if stem == "sh" || stem == "ch" || stem == "j" {
+			return stem + small[1:]
+		}
+		return stem + small
+	}
+	return stem + small[len(small)-1:]
+}
+
+// romajiInput maps romaji typed with an IME to hiragana. Hepburn, Kunrei and the
+// usual IME spellings (si, tu, hu, zya, xa, ...) are accepted.
+var romajiInput = func() map[string]string {
+	table := map[string]string{}
+	for r, romaji := range hepburn {
+		if smallKana[r] != 0 || r == 'ゐ' || r == 'ゑ' || r == 'を' || r == 'ぢ' || r == 'づ' {
+			continue
+		}
+		table[romaji] = string(r)
+	}
+	for _, base := range "きしちにひみりぎじびぴ" {
+		for _, small := range "ゃゅょ" {
+			table[contractRomaji(hepburn[base], hepburn[small])] = string(base) + string(small)
+		}
+	}
+	for romaji, kana := range map[string]string{
+		"si": "し", "ti": "ち", "tu": "つ", "hu": "ふ", "zi": "じ", "di": "ぢ", "du": "づ", "wo": "を",
+		"sya": "しゃ", "syu": "しゅ", "syo": "しょ", "tya": "ちゃ", "tyu": "ちゅ", "tyo": "ちょ",
+		"cya": "ちゃ", "cyu": "ちゅ", "cyo": "ちょ", "zya": "じゃ", "zyu": "じゅ", "zyo": "じょ",
+		"jya": "じゃ", "jyu": "じゅ", "jyo": "じょ", "she": "しぇ", "che": "ちぇ", "je": "じぇ",
+		"fa": "ふぁ", "fi": "ふぃ", "fe": "ふぇ", "fo": "ふぉ", "thi": "てぃ", "dhi": "でぃ",
+		"va": "ゔぁ", "vi": "ゔぃ", "ve": "ゔぇ", "vo": "ゔぉ",
+		"xa": "ぁ", "xi": "ぃ", "xu": "ぅ", "xe": "ぇ", "xo": "ぉ", "xtu": "っ", "xya": "ゃ", "xyu": "ゅ", "xyo": "ょ",
+		"la": "ぁ", "li": "ぃ", "lu": "ぅ", "le": "ぇ", "lo": "ぉ", "ltu": "っ", "lya": "ゃ", "lyu": "ゅ", "lyo": "ょ",
+		"nn": "ん", "n'": "ん", "-": "ー",
+	} {
+		table[romaji] = kana
+	}
+	return table
+}()
+
+// RomajiToHiragana converts romaji typed into a search box into hiragana, e.g. reizouko → れいぞうこ.
+// A trailing incomplete syllable is dropped so that the result can be used as a prefix,
+// except a final n, which becomes ん.
+func RomajiToHiragana(input string) string {
+	s := strings.ToLower(norm.NFKC.String(input))
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		// 子音の重ね（kk, tt, tch など）は促音にする
+		if i+1 < len(s) && isRomajiConsonant(c) && c != 'n' && (s[i+1] == c || (c == 't' && s[i+1] == 'c')) {
+			b.WriteRune('っ')
+			i++
+			continue
+		}
+
+		matched := false
+		for size := 3; size >= 1; size-- {
+			if i+size > len(s) {
+				continue
+			}
+			if kana, ok := romajiInput[s[i:i+size]]; ok {
+				// n は後ろに母音・y が続くときは「な行」の一部になる（konnichiha → こんにちは）
+				if (size == 1 && c == 'n' && i+1 < len(s)) || (s[i:i+size] == "nn" && i+2 < len(s) && strings.ContainsRune("aiueoy", rune(s[i+2]))) {
+					break
+				}
+				b.WriteString(kana)
+				i += size
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		switch {
+		case c == 'n' && !strings.ContainsRune("aiueoy", rune(s[i+1])):
+			b.WriteRune('ん')
+			i++
+		case isRomajiConsonant(c) && len(s)-i <= 2 && isRomajiLetters(s[i:]):
+			// 末尾の入力途中の音節（k, ky など）は前方一致のために捨てる
+			return b.String()
+		default:
+			r := []rune(s[i:])[0]
+			b.WriteRune(r)
+			i += len(string(r))
+		}
+	}
+	return b.String()
+}
+
+func isRomajiConsonant(c byte) bool {
+	return c >= 'a' && c <= 'z' && !strings.ContainsRune("aiueo", rune(c))
+}
+
+func isRomajiLetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'a' || s[i] > 'z' {
+			return false
+		}
+	}
+	return s != ""
+}
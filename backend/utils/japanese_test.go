@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareJapanese(t *testing.T) {
+	readings := []string{"ぱいぷいす", "ハンガー", "はんがー", "ばいく", "はこ", "きゃりーばっぐ", "きやく", "こーと", "こおり", "エアコン", "あいろん"}
+	SortJapanese(readings, func(s string) string { return s })
+	assert.Equal(t, []string{"あいろん", "エアコン", "きやく", "きゃりーばっぐ", "こーと", "こおり", "ばいく", "ぱいぷいす", "はこ", "はんがー", "ハンガー"}, readings)
+
+	assert.Equal(t, 0, CompareJapanese("れいぞうこ", "れいぞうこ"))
+	assert.Negative(t, CompareJapanese("たな", "だんぼーる"))
+	assert.Positive(t, CompareJapanese("ﾃﾚﾋﾞ", "てれび"), "half-width katakana sorts after hiragana only on the last level")
+	assert.Negative(t, CompareJapanese("てれび", "てれびだい"))
+}
+
+func TestToRomaji(t *testing.T) {
+	assert.Equal(t, "reizouko", ToRomaji("れいぞうこ"))
+	assert.Equal(t, "shokkidana", ToRomaji("しょっきだな"))
+	assert.Equal(t, "matcha", ToRomaji("まっちゃ"))
+	assert.Equal(t, "sofa-", ToRomaji("ソファー"))
+	assert.Equal(t, "jitensha", ToRomaji("じてんしゃ"))
+}
+
+func TestRomajiToHiragana(t *testing.T) {
+	assert.Equal(t, "れいぞうこ", RomajiToHiragana("reizouko"))
+	assert.Equal(t, "しょっきだな", RomajiToHiragana("syokkidana"))
+	assert.Equal(t, "こんにちは", RomajiToHiragana("konnichiha"))
+	assert.Equal(t, "せんたくき", RomajiToHiragana("sentakuki"))
+	assert.Equal(t, "ほん", RomajiToHiragana("hon"))
+	assert.Equal(t, "しょ", RomajiToHiragana("shok"), "trailing incomplete syllable is dropped")
+	assert.Equal(t, "てれ", RomajiToHiragana("ＴＥＲＥ"))
+}
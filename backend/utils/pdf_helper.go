@@ -3,7 +3,9 @@ package utils
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/signintech/gopdf"
 
@@ -35,6 +37,23 @@ func (h *PDFHelper) DrawHeader(estimate *models.PDFEstimate) error {
 	h.pdf.Line(210, 80, 460, 80)
 	h.pdf.Line(210, 82, 460, 82)
 
+	// Revision marker (第2版 and later)
+	if estimate.Revision >= 2 {
+		if err := h.pdf.SetFont("noto-sans", "", 12); err != nil {
+			return err
+		}
+		h.pdf.SetX(470)
+		h.pdf.SetY(62)
+		h.pdf.Cell(nil, fmt.Sprintf("第%d版", estimate.Revision))
+	}
+
+	return h.DrawIssuer(estimate.IssueDate, estimate.Issuer.RegistrationNumber)
+}
+
+// DrawIssuer draws the issue date and the issuing company info on the right side,
+// including the qualified invoice registration number when set.
+// It is shared by estimates and invoices.
+func (h *PDFHelper) DrawIssuer(issueDate time.Time, registrationNumber string) error {
 	// Company info (right side)
 	if err := h.pdf.SetFont("noto-sans", "", 10); err != nil {
 		return err
@@ -43,12 +62,12 @@ func (h *PDFHelper) DrawHeader(estimate *models.PDFEstimate) error {
 	// Issue date in 令和 format
 	h.pdf.SetX(460)
 	h.pdf.SetY(130)
-	year := estimate.IssueDate.Year()
+	year := issueDate.Year()
 	reiwaYear := year - 2018
 	h.pdf.Cell(nil, fmt.Sprintf("令和 %d年 %d月 %d日",
 		reiwaYear,
-		estimate.IssueDate.Month(),
-		estimate.IssueDate.Day()))
+		issueDate.Month(),
+		issueDate.Day()))
 
 	// Company info with stamp image
 	// Use the path that works in Docker container
@@ -72,6 +91,13 @@ func (h *PDFHelper) DrawHeader(estimate *models.PDFEstimate) error {
 	h.pdf.SetY(230)
 	h.pdf.Cell(nil, "MAIL : sakai@marukyou.com")
 
+	// 適格請求書発行事業者登録番号
+	if registrationNumber != "" {
+		h.pdf.SetX(400)
+		h.pdf.SetY(240)
+		h.pdf.Cell(nil, "登録番号 : "+registrationNumber)
+	}
+
 	return nil
 }
 
@@ -165,8 +191,9 @@ func (h *PDFHelper) DrawEstimateInfo(estimate *models.PDFEstimate) error {
 	return nil
 }
 
-// DrawTotalAmount draws the large total amount display with stamp box
-func (h *PDFHelper) DrawTotalAmount(total float64) error {
+// DrawTotalAmount draws the large total amount display with stamp box.
+// The total always includes tax; the line below it shows the tax contained in it.
+func (h *PDFHelper) DrawTotalAmount(estimate *models.PDFEstimate) error {
 	// Draw total amount box
 	boxX := 50.0
 	boxY := 280.0
@@ -180,11 +207,23 @@ func (h *PDFHelper) DrawTotalAmount(total float64) error {
 
 	// Draw label
 	h.pdf.SetX(boxX + 10)
-	h.pdf.SetY(boxY + 15)
+	h.pdf.SetY(boxY + 8)
 	if err := h.pdf.SetFont("noto-sans", "", 14); err != nil {
 		return err
 	}
-	h.pdf.Cell(nil, fmt.Sprintf("合計金額   ¥ %s", FormatCurrency(total)))
+	h.pdf.Cell(nil, fmt.Sprintf("合計金額   ¥ %s（税込）", FormatCurrency(estimate.Total)))
+
+	// 税抜価格なら税抜金額と消費税、税込価格なら内消費税を添える
+	h.pdf.SetX(boxX + 10)
+	h.pdf.SetY(boxY + 32)
+	if err := h.pdf.SetFont("noto-sans", "", 9); err != nil {
+		return err
+	}
+	if estimate.Pricing.IsInclusive() {
+		h.pdf.Cell(nil, fmt.Sprintf("（うち消費税 ¥ %s）", FormatCurrency(estimate.Tax)))
+	} else {
+		h.pdf.Cell(nil, fmt.Sprintf("（税抜金額 ¥ %s ／ 消費税 ¥ %s）", FormatCurrency(estimate.SubTotal), FormatCurrency(estimate.Tax)))
+	}
 
 	// Draw stamp box
 	stampBoxX := 400.0
@@ -257,8 +296,10 @@ func (h *PDFHelper) DrawTable(estimate *models.PDFEstimate, startY float64) (flo
 	marginLeft := 50.0
 	rowHeight := 25.0
 	items := estimate.Items
-	// Fixed 10 rows for items + 3 rows for subtotal, tax, and total
+	// Fixed 13 rows: item rows followed by the subtotal, tax per rate and total rows
 	maxRows := 10 + 3
+	summary := taxSummaryRows(estimate)
+	itemRows := maxRows - len(summary)
 
 	// Create a new table layout
 	table := h.pdf.NewTableLayout(marginLeft, startY, rowHeight, maxRows)
@@ -266,8 +307,10 @@ func (h *PDFHelper) DrawTable(estimate *models.PDFEstimate, startY float64) (flo
 	// Add columns to the table (total width: 495 to match header)
 	table.AddColumn("項目", 180, "left")
 	table.AddColumn("数量", 50, "right")
-	table.AddColumn("単価", 70, "right")
-	table.AddColumn("金額", 70, "right")
+	// 単価・金額が税込か税抜かを見出しに表示する
+	basis := PriceBasisLabel(estimate.Pricing)
+	table.AddColumn("単価("+basis+")", 70, "right")
+	table.AddColumn("金額("+basis+")", 70, "right")
 	table.AddColumn("備考", 125, "left")
 
 	// Set the style for table header
@@ -302,8 +345,8 @@ func (h *PDFHelper) DrawTable(estimate *models.PDFEstimate, startY float64) (flo
 		FontSize:  10,
 	})
 
-	// Add rows to the table (fixed 10 rows for items)
-	for i := 0; i < 10; i++ {
+	// Add rows to the table (fixed number of rows for items)
+	for i := 0; i < itemRows; i++ {
 		if i < len(items) {
 			// Add actual item data
 			item := items[i]
@@ -311,17 +354,29 @@ func (h *PDFHelper) DrawTable(estimate *models.PDFEstimate, startY float64) (flo
 			if item.Unit != "" {
 				quantityStr = fmt.Sprintf("%.0f%s", item.Quantity, item.Unit)
 			}
-			unitPriceStr := FormatCurrency(item.UnitPrice)
-			amountStr := FormatCurrency(item.Amount)
+			unitPriceStr := FormatAmount(item.UnitPrice)
+			amountStr := FormatAmount(item.Amount)
 
-			// Use Specification field if available, otherwise empty
-			specification := ""
-			if item.Specification != "" {
-				specification = item.Specification
+			// 標準税率以外の明細には税区分の記号を付ける
+			description := item.Description
+			if marker := TaxCategoryMarker(item.TaxCategory); marker != "" {
+				description += " " + marker
 			}
 
+			// 備考欄にはカテゴリーと仕様、明細の値引き額を印字する
+			notes := []string{}
+			for _, note := range []string{item.Category, item.Specification} {
+				if note != "" {
+					notes = append(notes, note)
+				}
+			}
+			if math.Round(item.DiscountAmount) > 0 {
+				notes = append(notes, "値引 "+FormatAmount(-item.DiscountAmount))
+			}
+			specification := strings.Join(notes, " / ")
+
 			table.AddRow([]string{
-				item.Description,
+				description,
 				quantityStr,
 				unitPriceStr,
 				amountStr,
@@ -339,46 +394,30 @@ func (h *PDFHelper) DrawTable(estimate *models.PDFEstimate, startY float64) (flo
 		}
 	}
 
-	// Add subtotal row
-	table.AddRow([]string{
-		"",
-		"",
-		"小計",
-		FormatCurrency(estimate.SubTotal),
-		"",
-	})
-
-	// Add tax row
-	table.AddRow([]string{
-		"",
-		"",
-		"消費税",
-		FormatCurrency(estimate.Tax),
-		"",
-	})
-
-	// Add total row
-	table.AddRow([]string{
-		"",
-		"",
-		"合計金額",
-		FormatCurrency(estimate.Total),
-		"",
-	})
+	// Add subtotal, tax and total rows
+	for _, row := range summary {
+		table.AddRow([]string{
+			"",
+			"",
+			row.label,
+			FormatAmount(row.amount),
+			"",
+		})
+	}
 
 	// Draw the table
 	table.DrawTable()
 
-	// Draw a thicker line above subtotal row
-	// Position: header (1 row) + 10 item rows
-	subtotalRowY := startY + rowHeight + (rowHeight * float64(10))
+	// Draw a thicker line above the first summary row
+	// Position: header (1 row) + item rows
+	subtotalRowY := startY + rowHeight + (rowHeight * float64(itemRows))
 	h.pdf.SetLineWidth(1.5) // Thicker line
 	h.pdf.SetStrokeColor(0, 0, 0)
 	h.pdf.Line(marginLeft, subtotalRowY, marginLeft+495, subtotalRowY)
 
 	// Draw a thicker line above total row
-	// Position: header (1 row) + 10 item rows + subtotal row + tax row
-	totalRowY := startY + rowHeight + (rowHeight * float64(10+2))
+	// Position: header (1 row) + all rows except the total row
+	totalRowY := startY + rowHeight + (rowHeight * float64(maxRows-1))
 	h.pdf.SetLineWidth(1.5) // Thicker line
 	h.pdf.SetStrokeColor(0, 0, 0)
 	h.pdf.Line(marginLeft, totalRowY, marginLeft+495, totalRowY)
@@ -389,6 +428,99 @@ func (h *PDFHelper) DrawTable(estimate *models.PDFEstimate, startY float64) (flo
 	return endY, nil
 }
 
+// summaryRow is a labelled amount printed below the items table
+type summaryRow struct {
+	label  string
+	amount float64
+}
+
+// taxSummaryRows returns the rows printed below the items. When every line is taxed at
+// one rate it prints 小計 / 消費税(10%) / 合計金額; otherwise it prints the amount of
+// each tax category and the tax for each rate, as required for qualified invoices.
+// With tax-inclusive prices the amounts are labelled 税込 and the tax as 内消費税.
+func taxSummaryRows(estimate *models.PDFEstimate) []summaryRow {
+	breakdown := estimate.TaxBreakdown
+	if len(breakdown) == 0 {
+		breakdown = []models.TaxBreakdown{{Category: models.TaxCategoryStandard, Rate: estimate.TaxRate, Taxable: estimate.SubTotal, Tax: estimate.Tax}}
+	}
+
+	basis := PriceBasisLabel(estimate.Pricing)
+	taxLabel := "消費税"
+	if estimate.Pricing.IsInclusive() {
+		taxLabel = "内消費税"
+	}
+
+	rows := []summaryRow{}
+	if len(breakdown) == 1 && breakdown[0].Category.OrDefault().IsTaxable() {
+		rows = append(rows,
+			summaryRow{fmt.Sprintf("小計(%s)", basis), estimate.SubTotal},
+			summaryRow{fmt.Sprintf("%s(%s)", taxLabel, FormatTaxRate(breakdown[0].Rate)), breakdown[0].Tax},
+		)
+	} else {
+		for _, b := range breakdown {
+			switch b.Category {
+			case models.TaxCategoryExempt:
+				rows = append(rows, summaryRow{"非課税", b.Taxable})
+			case models.TaxCategoryNonTaxable:
+				rows = append(rows, summaryRow{"不課税", b.Taxable})
+			default:
+				rows = append(rows,
+					summaryRow{fmt.Sprintf("%s対象(%s)", FormatTaxRate(b.Rate), basis), b.Taxable},
+					summaryRow{fmt.Sprintf("%s(%s)", taxLabel, FormatTaxRate(b.Rate)), b.Tax},
+				)
+			}
+		}
+	}
+	return append(rows, summaryRow{"合計金額(税込)", estimate.Total})
+}
+
+// PriceBasisLabel returns 税込 or 税抜 for the prices of the pricing mode
+func PriceBasisLabel(pricing models.PricingMode) string {
+	if pricing.IsInclusive() {
+		return "税込"
+	}
+	return "税抜"
+}
+
+// TaxCategoryMarker returns the marker printed after the description of lines that are
+// not taxed at the standard rate
+func TaxCategoryMarker(category models.TaxCategory) string {
+	switch category {
+	case models.TaxCategoryReduced:
+		return "※"
+	case models.TaxCategoryExempt:
+		return "◇"
+	case models.TaxCategoryNonTaxable:
+		return "◆"
+	}
+	return ""
+}
+
+// FormatTaxRate formats a tax rate as a percentage, e.g. 0.08 → "8%"
+func FormatTaxRate(rate float64) string {
+	return fmt.Sprintf("%g%%", math.Round(rate*10000)/100)
+}
+
+// TaxRemarks returns the notes explaining the tax category markers used in the items
+func TaxRemarks(items []models.PDFLineItem) []string {
+	used := map[models.TaxCategory]bool{}
+	for _, item := range items {
+		used[item.TaxCategory] = true
+	}
+
+	remarks := []string{}
+	if used[models.TaxCategoryReduced] {
+		remarks = append(remarks, fmt.Sprintf("※印は軽減税率（%s）対象品目です。", FormatTaxRate(models.ReducedTaxRate)))
+	}
+	if used[models.TaxCategoryExempt] {
+		remarks = append(remarks, "◇印は非課税品目です。")
+	}
+	if used[models.TaxCategoryNonTaxable] {
+		remarks = append(remarks, "◆印は不課税（消費税の対象外）です。")
+	}
+	return remarks
+}
+
 // DrawTotals draws the totals section
 func (h *PDFHelper) DrawTotals(estimate *models.PDFEstimate, startY float64) error {
 	if err := h.pdf.SetFont("noto-sans", "", 10); err != nil {
@@ -459,8 +591,15 @@ func (h *PDFHelper) DrawRemarks(remarks []string, startY float64) error {
 
 // FormatCurrency formats a number as Japanese currency
 func FormatCurrency(amount float64) string {
+	// 符号は桁区切りの対象外にする（-0 は 0 と表示する）
+	rounded := math.Round(amount)
+	sign := ""
+	if rounded < 0 {
+		sign = "-"
+	}
+
 	// Format with comma separators
-	formatted := fmt.Sprintf("%.0f", amount)
+	formatted := fmt.Sprintf("%.0f", math.Abs(rounded))
 
 	// Add commas
 	parts := []string{}
@@ -472,7 +611,16 @@ func FormatCurrency(amount float64) string {
 		parts = append([]string{formatted[start:i]}, parts...)
 	}
 
-	return strings.Join(parts, ",")
+	return sign + strings.Join(parts, ",")
+}
+
+// FormatAmount formats an amount printed in the items table, marking negative amounts
+// such as discounts and buybacks with ▲ (e.g. ▲1,000)
+func FormatAmount(amount float64) string {
+	if math.Round(amount) < 0 {
+		return "▲" + FormatCurrency(-amount)
+	}
+	return FormatCurrency(amount)
 }
 
 // DrawImageGrid draws images in a grid layout
@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatNegativeAmounts(t *testing.T) {
+	assert.Equal(t, "1,234,567", FormatCurrency(1234567))
+	assert.Equal(t, "-123,456", FormatCurrency(-123456))
+	assert.Equal(t, "-1,000", FormatCurrency(-1000))
+	assert.Equal(t, "0", FormatCurrency(-0.4))
+	assert.Equal(t, "▲123,456", FormatAmount(-123456))
+	assert.Equal(t, "3,000", FormatAmount(3000))
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/signintech/gopdf"
 	"line-estimate-backend/models"
@@ -64,6 +65,11 @@ func (h *PDFInstructionHelper) DrawSection(offsetX float64, instruction *models.
 		return err
 	}
 
+	// Draw vehicle next to the content title
+	if err := h.DrawVehicle(offsetX, instruction.Vehicle); err != nil {
+		return err
+	}
+
 	// Draw footer
 	if err := h.DrawFooter(offsetX, instruction.WorkDetails); err != nil {
 		return err
@@ -260,6 +266,20 @@ func (h *PDFInstructionHelper) DrawContentSection(offsetX float64, items []model
 	return nil
 }
 
+// DrawVehicle draws the vehicle to use on the right of the content title
+func (h *PDFInstructionHelper) DrawVehicle(offsetX float64, vehicle string) error {
+	if vehicle == "" {
+		return nil
+	}
+	if err := h.pdf.SetFont("noto-sans", "", 10); err != nil {
+		return err
+	}
+	h.pdf.SetX(offsetX + 200)
+	h.pdf.SetY(191)
+	h.pdf.Cell(nil, "車両："+vehicle)
+	return nil
+}
+
 // DrawFooter draws the footer section with work details
 func (h *PDFInstructionHelper) DrawFooter(offsetX float64, details models.PDFWorkDetails) error {
 	// Footer box
@@ -372,3 +392,15 @@ func (h *PDFInstructionHelper) DrawCenterDivider() error {
 	h.pdf.SetStrokeColor(0, 0, 0) // Reset to black
 	return nil
 }
+
+// japaneseWeekdays are the weekday abbreviations printed after dates
+var japaneseWeekdays = [...]string{"日", "月", "火", "水", "木", "金", "土"}
+
+// FormatJapaneseDate formats a date in the Reiwa era, e.g. 令和7年4月30日（水）
+func FormatJapaneseDate(t time.Time) string {
+	year := fmt.Sprintf("令和%d年", t.Year()-2018)
+	if t.Year() == 2019 {
+		year = "令和元年"
+	}
+	return fmt.Sprintf("%s%d月%d日（%s）", year, int(t.Month()), t.Day(), japaneseWeekdays[t.Weekday()])
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/signintech/gopdf"
+
+	"line-estimate-backend/models"
+)
+
+// PDFInvoiceHelper provides helper functions for invoice PDF generation.
+// The issuer block, items table and remarks box share the estimate layout.
+type PDFInvoiceHelper struct {
+	*PDFHelper
+}
+
+// NewPDFInvoiceHelper creates a new PDF invoice helper instance
+func NewPDFInvoiceHelper(pdf *gopdf.GoPdf) *PDFInvoiceHelper {
+	return &PDFInvoiceHelper{PDFHelper: NewPDFHelper(pdf)}
+}
+
+// DrawHeader draws the header section of the invoice
+func (h *PDFInvoiceHelper) DrawHeader(invoice *models.PDFInvoice) error {
+	// Title "御請求書" with underline
+	h.pdf.SetX(240)
+	h.pdf.SetY(50)
+	if err := h.pdf.SetFont("noto-sans", "", 28); err != nil {
+		return err
+	}
+	h.pdf.Cell(nil, "御　請　求　書")
+
+	h.pdf.SetLineWidth(1)
+	h.pdf.Line(210, 80, 460, 80)
+	h.pdf.Line(210, 82, 460, 82)
+
+	// 登録番号があれば適格請求書である旨を表示する
+	if invoice.Issuer.RegistrationNumber != "" {
+		if err := h.pdf.SetFont("noto-sans", "", 10); err != nil {
+			return err
+		}
+		h.pdf.SetX(470)
+		h.pdf.SetY(64)
+		h.pdf.Cell(nil, "（適格請求書）")
+	}
+
+	return h.DrawIssuer(invoice.IssueDate, invoice.Issuer.RegistrationNumber)
+}
+
+// DrawCustomerInfo draws the billed customer and the greeting
+func (h *PDFInvoiceHelper) DrawCustomerInfo(invoice *models.PDFInvoice) error {
+	if err := h.pdf.SetFont("noto-sans", "", 16); err != nil {
+		return err
+	}
+	h.pdf.SetX(50)
+	h.pdf.SetY(98)
+	h.pdf.Cell(nil, invoice.Customer.CompanyName)
+	h.pdf.SetX(270)
+	h.pdf.SetY(100)
+	h.pdf.Cell(nil, "様")
+
+	h.pdf.SetLineWidth(1)
+	h.pdf.Line(50, 115, 290, 115)
+
+	h.pdf.SetX(50)
+	h.pdf.SetY(130)
+	if err := h.pdf.SetFont("noto-sans", "", 11); err != nil {
+		return err
+	}
+	h.pdf.Cell(nil, "下記のとおり御請求申し上げます。")
+
+	h.pdf.SetX(50)
+	h.pdf.SetY(145)
+	h.pdf.Cell(nil, "期日までにお振込みをお願い申し上げます。")
+
+	return nil
+}
+
+// DrawInvoiceInfo draws the invoice number, work date, measured weight and due date
+func (h *PDFInvoiceHelper) DrawInvoiceInfo(invoice *models.PDFInvoice) error {
+	startY := 170.0
+	leftX := 50.0
+	labelWidth := 80.0
+
+	if err := h.pdf.SetFont("noto-sans", "", 10); err != nil {
+		return err
+	}
+
+	weight := ""
+	if invoice.ActualWeight > 0 {
+		weight = fmt.Sprintf("%s kg", FormatCurrency(invoice.ActualWeight))
+	}
+
+	rows := []struct {
+		label string
+		value string
+	}{
+		{"請求番号", invoice.InvoiceNo},
+		{"作 業 日", invoice.WorkDate},
+		{"計　　量", weight},
+		{"お支払期限", formatReiwaDate(invoice.DueDate) + "迄"},
+	}
+	for i, row := range rows {
+		y := startY + float64(i)*20
+		h.pdf.SetX(leftX)
+		h.pdf.SetY(y)
+		h.pdf.Cell(nil, row.label)
+		h.pdf.SetX(leftX + labelWidth)
+		h.pdf.Cell(nil, row.value)
+		h.pdf.Line(leftX, y+10, leftX+250, y+10)
+	}
+
+	// 廃棄物搬出・収集運搬・処分 (Waste disposal info)
+	h.pdf.Line(leftX, startY+78, leftX+500, startY+78)
+	h.pdf.Line(leftX, startY+80, leftX+500, startY+80)
+	h.pdf.SetX(leftX + 100)
+	h.pdf.SetY(startY + 80)
+	if err := h.pdf.SetFont("noto-sans", "", 20); err != nil {
+		return err
+	}
+	h.pdf.Cell(nil, "廃棄物搬出・収集運搬・処分")
+	h.pdf.Line(leftX, startY+100, leftX+500, startY+100)
+	h.pdf.Line(leftX, startY+102, leftX+500, startY+102)
+
+	return nil
+}
+
+// DrawBillingAmount draws the large billed amount box
+func (h *PDFInvoiceHelper) DrawBillingAmount(total float64) error {
+	boxX := 50.0
+	boxY := 280.0
+
+	h.pdf.SetLineWidth(1)
+	h.pdf.SetStrokeColor(0, 0, 0)
+	h.pdf.RectFromUpperLeftWithStyle(boxX, boxY, 300, 50, "D")
+
+	h.pdf.SetX(boxX + 10)
+	h.pdf.SetY(boxY + 15)
+	if err := h.pdf.SetFont("noto-sans", "", 14); err != nil {
+		return err
+	}
+	h.pdf.Cell(nil, fmt.Sprintf("ご請求金額   ¥ %s（税込）", FormatCurrency(total)))
+
+	return nil
+}
+
+// DrawTable draws the items table using the estimate layout
+func (h *PDFInvoiceHelper) DrawTable(invoice *models.PDFInvoice, startY float64) (float64, error) {
+	return h.PDFHelper.DrawTable(&models.PDFEstimate{
+		Items:        invoice.Items,
+		SubTotal:     invoice.SubTotal,
+		TaxRate:      invoice.TaxRate,
+		Tax:          invoice.Tax,
+		Total:        invoice.Total,
+		TaxBreakdown: invoice.TaxBreakdown,
+		Pricing:      invoice.Pricing,
+	}, startY)
+}
+
+// DrawPaymentInfo draws the bank transfer details followed by the remarks
+func (h *PDFInvoiceHelper) DrawPaymentInfo(invoice *models.PDFInvoice, startY float64) error {
+	lines := []string{}
+	if bank := FormatBankAccount(invoice.Bank); bank != "" {
+		lines = append(lines, "お振込先："+bank)
+	}
+	lines = append(lines, TaxRemarks(invoice.Items)...)
+	lines = append(lines, invoice.Remarks...)
+
+	return h.DrawRemarks(lines, startY)
+}
+
+// FormatBankAccount formats a bank account on one line, e.g. ○○銀行 ○○支店 普通 1234567 カ）マルキョウ
+func FormatBankAccount(bank models.BankAccount) string {
+	if bank.BankName == "" || bank.AccountNumber == "" {
+		return ""
+	}
+
+	parts := []string{}
+	for _, part := range []string{bank.BankName, bank.BranchName, bank.AccountType, bank.AccountNumber, bank.AccountHolder} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatReiwaDate formats a date as printed on documents, e.g. 令和 7 年 5 月 31 日
+func formatReiwaDate(t time.Time) string {
+	return fmt.Sprintf("令和 %d 年 %d 月 %d 日", t.Year()-2018, t.Month(), t.Day())
+}
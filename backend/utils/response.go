@@ -42,6 +42,15 @@ func SendErrorResponse(c *gin.Context, statusCode int, message string) {
 	})
 }
 
+// SendErrorResponseWithData sends an error response with details describing the error
+func SendErrorResponseWithData(c *gin.Context, statusCode int, message string, data interface{}) {
+	c.JSON(statusCode, Response{
+		Success: false,
+		Error:   message,
+		Data:    data,
+	})
+}
+
 // ParseJSON parses JSON from an io.Reader into a struct
 func ParseJSON(reader io.Reader, v interface{}) error {
 	decoder := json.NewDecoder(reader)